@@ -0,0 +1,102 @@
+package gosimplifier
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// SimplifyAll returns simplified copies of every element of items, simplifying up to parallelism
+// elements concurrently (one at a time if parallelism < 2). If an element fails, the error of the
+// first failing element is returned; partial results of WithBestEffort simplifiers are kept and
+// their errors are combined into a single PartialError.
+func SimplifyAll[T any](s Simplifier, items []T, parallelism int) ([]T, error) {
+	if items == nil {
+		return nil, nil
+	}
+	impl, ok := s.(*simplifierImpl)
+	if !ok {
+		return simplifyAllGeneric(s, items)
+	}
+
+	out := make([]T, len(items))
+	errs := make([]error, len(items))
+	outValue, itemsValue := reflect.ValueOf(out), reflect.ValueOf(items)
+	simplifyRange := func(from, to int) {
+		for i := from; i < to; i++ {
+			elem := outValue.Index(i)
+			copied, err := deepCopy(elem, itemsValue.Index(i))
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			elem.Set(copied)
+			errs[i] = impl.simplify(context.Background(), elem, nil)
+		}
+	}
+
+	if parallelism < 2 || len(items) < 2 {
+		simplifyRange(0, len(items))
+	} else {
+		if parallelism > len(items) {
+			parallelism = len(items)
+		}
+		chunk := (len(items) + parallelism - 1) / parallelism
+		var wg sync.WaitGroup
+		for from := 0; from < len(items); from += chunk {
+			to := from + chunk
+			if to > len(items) {
+				to = len(items)
+			}
+			wg.Add(1)
+			go func(from, to int) {
+				defer wg.Done()
+				simplifyRange(from, to)
+			}(from, to)
+		}
+		wg.Wait()
+	}
+	return batchResult(out, errs)
+}
+
+// simplifyAllGeneric falls back to Simplify for Simplifier implementations other than simplifierImpl.
+func simplifyAllGeneric[T any](s Simplifier, items []T) ([]T, error) {
+	out := make([]T, len(items))
+	errs := make([]error, len(items))
+	for i, item := range items {
+		simplified, err := s.Simplify(item)
+		errs[i] = err
+		if simplified != nil {
+			out[i] = simplified.(T)
+		}
+	}
+	return batchResult(out, errs)
+}
+
+// batchResult returns out unless an element failed with an error other than a PartialError.
+func batchResult[T any](out []T, errs []error) ([]T, error) {
+	err := batchError(errs)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	return out, err
+}
+
+// batchError returns the first error that is not a PartialError, or the partial errors combined.
+func batchError(errs []error) error {
+	var partial []error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if p, ok := err.(*PartialError); ok {
+			partial = append(partial, p.Errors...)
+			continue
+		}
+		return err
+	}
+	if len(partial) > 0 {
+		return &PartialError{Errors: partial}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyAll(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+
+	items := make([]ExampleStruct, 50)
+	expected := make([]ExampleStruct, 50)
+	for i := range items {
+		items[i] = ExampleStruct{Test: i, Debug: "debug"}
+		expected[i] = ExampleStruct{Test: i}
+	}
+
+	for _, parallelism := range []int{0, 4, 100} {
+		simplified, err := SimplifyAll(simplifier, items, parallelism)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(simplified, expected) {
+			t.Errorf("Parallelism %d: unexpected result %+v", parallelism, simplified)
+		}
+	}
+	if items[0].Debug != "debug" {
+		t.Error("The original items must not be modified")
+	}
+
+	pointers := []*ExampleStruct{{Test: 1, Debug: "debug"}, nil}
+	simplifiedPointers, err := SimplifyAll(simplifier, pointers, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplifiedPointers[0] == pointers[0] || simplifiedPointers[0].Debug != "" || simplifiedPointers[1] != nil {
+		t.Errorf("Unexpected result %+v", simplifiedPointers)
+	}
+}
+
+func TestSimplifyAllError(t *testing.T) {
+	errFailed := errors.New("failed")
+	failOn := func(test int) Middleware {
+		return func(next Walker) Walker {
+			return func(node *Node) error {
+				if node.Name() == "Test" && node.Value.Int() == int64(test) {
+					return errFailed
+				}
+				return next(node)
+			}
+		}
+	}
+	items := []ExampleStruct{{Test: 1}, {Test: 2}, {Test: 3}}
+
+	simplifier, _ := NewSimplifier(`{}`, WithMiddleware(failOn(2)))
+	if simplified, err := SimplifyAll(simplifier, items, 3); !errors.Is(err, errFailed) || simplified != nil {
+		t.Errorf("Expected the element error, got %v, %v", simplified, err)
+	}
+
+	simplifier, _ = NewSimplifier(`{}`, WithMiddleware(failOn(2)), WithBestEffort())
+	simplified, err := SimplifyAll(simplifier, items, 0)
+	if !isPartial(err) || len(simplified) != 3 || simplified[1].Test != 0 {
+		t.Errorf("Expected a partial result, got %v, %v", simplified, err)
+	}
+}
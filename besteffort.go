@@ -0,0 +1,92 @@
+package gosimplifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultBestEffortNodeBudget is the node budget of WithBestEffort unless WithNodeBudget is set.
+const defaultBestEffortNodeBudget = 1 << 20
+
+// ErrBudgetExceeded is returned when Simplify visits more nodes than allowed by WithNodeBudget.
+var ErrBudgetExceeded = errors.New("node budget exceeded")
+
+// PartialError is returned by WithBestEffort simplifiers together with a partial result.
+// Every value that failed to simplify was removed from the result, so the result never carries
+// a value the rules could not be applied to.
+type PartialError struct {
+	Errors []error
+}
+
+func (e *PartialError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("simplified partially, %d values removed: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the removed values.
+func (e *PartialError) Unwrap() []error {
+	return e.Errors
+}
+
+// WithBestEffort tunes a simplifier for paths such as logging, where losing a field is acceptable
+// but failing is not. It combines:
+//
+//   - panic recovery: a panic while simplifying a value is turned into an error for that value
+//   - error aggregation: a value that fails is removed, and the traversal continues with the others
+//   - a node budget: values visited after the budget is exhausted are removed (see WithNodeBudget)
+//
+// Simplify then returns the partial result together with a *PartialError listing the failures.
+func WithBestEffort() Option {
+	return func(o *options) {
+		o.bestEffort = true
+	}
+}
+
+// WithNodeBudget limits the number of values a single Simplify call visits. Exceeding it fails
+// with ErrBudgetExceeded, or removes the remaining values with WithBestEffort.
+func WithNodeBudget(n int) Option {
+	return func(o *options) {
+		o.nodeBudget = n
+	}
+}
+
+// budgetMiddleware counts the visited nodes of a call against the budget.
+func budgetMiddleware(budget int) Middleware {
+	return func(next Walker) Walker {
+		return func(node *Node) error {
+			node.walk.visited++
+			if node.walk.visited > budget {
+				return fmt.Errorf("%s: %w", displayPath(node.Path()), ErrBudgetExceeded)
+			}
+			return next(node)
+		}
+	}
+}
+
+// bestEffortMiddleware recovers from the failure of a node by removing it and recording the error.
+// A failure of the root is not recovered, as there would be nothing left to return, and neither
+// is the cancellation of the call.
+func bestEffortMiddleware(next Walker) Walker {
+	return func(node *Node) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%s: panic: %v", displayPath(node.Path()), r)
+			}
+			if err != nil && node.parent != nil && !node.walk.cancelled() {
+				node.walk.errors = append(node.walk.errors, err)
+				err = removeRulerSingleton.apply(node)
+			}
+		}()
+		return next(node)
+	}
+}
+
+// isPartial reports whether err comes with a usable partial result.
+func isPartial(err error) bool {
+	var partial *PartialError
+	return errors.As(err, &partial)
+}
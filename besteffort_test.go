@@ -0,0 +1,94 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBestEffortRecoversPanics(t *testing.T) {
+	panicky := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Path() == "EntityList[1]" {
+				panic("broken element")
+			}
+			if node.Path() == "Data" {
+				return errors.New("broken data")
+			}
+			return next(node)
+		}
+	}
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithMiddleware(panicky), WithBestEffort())
+
+	simplified, err := simplifier.Simplify(ExampleStruct{
+		Test:  5,
+		Debug: "debug",
+		Data:  DataStruct{DataTest: "data_test"},
+		EntityList: []EntityStruct{
+			{SubProperties: SubPropertyStruct{ABC: "a"}},
+			{SubProperties: SubPropertyStruct{ABC: "b"}},
+		},
+	})
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if len(partial.Errors) != 2 {
+		t.Errorf("Expected 2 recovered errors, got %v", partial.Errors)
+	}
+	expected := ExampleStruct{
+		Test: 5,
+		EntityList: []EntityStruct{
+			{SubProperties: SubPropertyStruct{ABC: "a"}},
+			{},
+		},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+}
+
+func TestNodeBudget(t *testing.T) {
+	original := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "y": 2},
+		"b": map[string]interface{}{"x": 1, "y": 2},
+	}
+
+	strict, _ := NewSimplifier(`{}`, WithNodeBudget(4))
+	if _, err := strict.Simplify(original); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded, got %v", err)
+	}
+
+	bestEffort, _ := NewSimplifier(`{}`, WithNodeBudget(4), WithBestEffort())
+	simplified, err := bestEffort.Simplify(original)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Expected ErrBudgetExceeded in the partial error, got %v", err)
+	}
+	expected := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "y": 2},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
+
+func TestBestEffortRootFailure(t *testing.T) {
+	failRoot := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Depth == 0 {
+				panic("broken root")
+			}
+			return next(node)
+		}
+	}
+	simplifier, _ := NewSimplifier(`{}`, WithMiddleware(failRoot), WithBestEffort())
+
+	simplified, err := simplifier.Simplify(ExampleStruct{Test: 5})
+	if err == nil || isPartial(err) {
+		t.Errorf("Expected a plain error, got %v", err)
+	}
+	if simplified != nil {
+		t.Error("Expected no result when the root fails")
+	}
+}
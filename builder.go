@@ -0,0 +1,66 @@
+package gosimplifier
+
+// RuleBuilder constructs a Rule in Go code instead of JSON.
+//
+// Example:
+//
+//	rule := NewRuleBuilder().
+//		Remove("Debug").
+//		Property("Data", func(b *RuleBuilder) {
+//			b.Remove("DataTest", "DataDebug")
+//		}).
+//		Build()
+type RuleBuilder struct {
+	removeProperties    []string
+	propertyBuilders    map[string]*RuleBuilder
+	propertyBuilderKeys []string
+}
+
+// NewRuleBuilder creates an empty RuleBuilder.
+func NewRuleBuilder() *RuleBuilder {
+	return &RuleBuilder{}
+}
+
+// Remove adds the given properties to remove_properties.
+func (b *RuleBuilder) Remove(props ...string) *RuleBuilder {
+	for _, prop := range props {
+		if !contains(b.removeProperties, prop) {
+			b.removeProperties = append(b.removeProperties, prop)
+		}
+	}
+	return b
+}
+
+// Property configures the nested rule for the given property in property_simplifiers.
+// Calling it again for the same property continues configuring the same nested rule.
+func (b *RuleBuilder) Property(name string, build func(b *RuleBuilder)) *RuleBuilder {
+	if b.propertyBuilders == nil {
+		b.propertyBuilders = make(map[string]*RuleBuilder)
+	}
+	sub, ok := b.propertyBuilders[name]
+	if !ok {
+		sub = NewRuleBuilder()
+		b.propertyBuilders[name] = sub
+		b.propertyBuilderKeys = append(b.propertyBuilderKeys, name)
+	}
+	if build != nil {
+		build(sub)
+	}
+	return b
+}
+
+// Build returns the Rule described by the builder. The builder can keep being used afterwards
+// without affecting the returned Rule.
+func (b *RuleBuilder) Build() *Rule {
+	rule := &Rule{
+		RemoveProperties: make([]string, len(b.removeProperties)),
+	}
+	copy(rule.RemoveProperties, b.removeProperties)
+	if len(b.propertyBuilderKeys) > 0 {
+		rule.PropertySimplifiers = make(map[string]*Rule, len(b.propertyBuilderKeys))
+		for _, name := range b.propertyBuilderKeys {
+			rule.PropertySimplifiers[name] = b.propertyBuilders[name].Build()
+		}
+	}
+	return rule
+}
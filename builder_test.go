@@ -0,0 +1,73 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleBuilder(t *testing.T) {
+	rule := NewRuleBuilder().
+		Remove("Debug").
+		Property("Data", func(b *RuleBuilder) {
+			b.Remove("DataTest", "DataDebug")
+		}).
+		Property("EntityList", func(b *RuleBuilder) {
+			b.Property("SubProperties", func(b *RuleBuilder) {
+				b.Remove("ABC")
+			})
+		}).
+		Property("EntityList", func(b *RuleBuilder) {
+			b.Property("SubProperties", func(b *RuleBuilder) {
+				b.Remove("DEF", "ABC")
+			})
+		}).
+		Build()
+
+	expected := &Rule{
+		RemoveProperties: []string{"Debug"},
+		PropertySimplifiers: map[string]*Rule{
+			"Data": {
+				RemoveProperties: []string{"DataTest", "DataDebug"},
+			},
+			"EntityList": {
+				RemoveProperties: []string{},
+				PropertySimplifiers: map[string]*Rule{
+					"SubProperties": {
+						RemoveProperties: []string{"ABC", "DEF"},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(rule, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rule)
+	}
+
+	simplifier, err := NewSimplifierByRule(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(ExampleStruct{
+		Test:       5,
+		Debug:      "debug",
+		Data:       DataStruct{DataTest: "data_test", DataDebug: 123},
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{ABC: "abc", DEF: "def"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deepCheck(t, simplified.(ExampleStruct), ExampleStruct{
+		Test:       5,
+		EntityList: []EntityStruct{{}},
+	})
+}
+
+func TestRuleBuilderBuildIsIndependent(t *testing.T) {
+	builder := NewRuleBuilder().Remove("Test")
+	first := builder.Build()
+	builder.Remove("Debug")
+
+	if !reflect.DeepEqual(first.RemoveProperties, []string{"Test"}) {
+		t.Errorf("Expected the built rule to be unaffected, got %v", first.RemoveProperties)
+	}
+}
@@ -0,0 +1,95 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Bundle is a set of named policies loaded from a directory of rule files.
+//
+// Every .json file below the directory contributes the policies it declares under a namespace
+// derived from its path, e.g. billing.json declaring
+//
+//	{
+//	  "public":   { "remove_properties": [ "InternalID" ] },
+//	  "internal": { "remove_properties": [ "CardNumber" ] }
+//	}
+//
+// yields the policies "billing/public" and "billing/internal", and teams/search/v2.json declaring
+// "default" yields "teams/search/v2/default".
+type Bundle struct {
+	policies map[string]Simplifier
+	sources  map[string]string
+}
+
+// LoadBundle loads every .json file below dir in fsys into a Bundle, compiling each policy with
+// the given options. Two files declaring the same fully qualified policy name are an error.
+func LoadBundle(fsys fs.FS, dir string, opts ...Option) (*Bundle, error) {
+	b := &Bundle{
+		policies: make(map[string]Simplifier),
+		sources:  make(map[string]string),
+	}
+	err := fs.WalkDir(fsys, dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || path.Ext(filePath) != ".json" {
+			return nil
+		}
+		return b.loadFile(fsys, dir, filePath, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Bundle) loadFile(fsys fs.FS, dir string, filePath string, opts []Option) error {
+	data, err := fs.ReadFile(fsys, filePath)
+	if err != nil {
+		return err
+	}
+	var rules map[string]*Rule
+	if err := decodeRules(data, &rules); err != nil {
+		return fmt.Errorf("%s: %w", filePath, err)
+	}
+	namespace := strings.TrimSuffix(filePath, ".json")
+	if dir != "." {
+		namespace = strings.TrimPrefix(namespace, dir+"/")
+	}
+	for policyName, rule := range rules {
+		if rule == nil {
+			return fmt.Errorf("%s: policy %q is null", filePath, policyName)
+		}
+		name := namespace + "/" + policyName
+		if source, ok := b.sources[name]; ok {
+			return fmt.Errorf("%s: policy %q is already declared in %s", filePath, name, source)
+		}
+		s, err := NewSimplifierByRule(rule, opts...)
+		if err != nil {
+			return fmt.Errorf("%s: policy %q: %w", filePath, policyName, err)
+		}
+		b.policies[name] = s
+		b.sources[name] = filePath
+	}
+	return nil
+}
+
+// Policy returns the policy with the fully qualified name, e.g. "billing/public".
+func (b *Bundle) Policy(name string) (Simplifier, bool) {
+	s, ok := b.policies[name]
+	return s, ok
+}
+
+// Names returns the fully qualified names of all policies in sorted order.
+func (b *Bundle) Names() []string {
+	names := make([]string, 0, len(b.policies))
+	for name := range b.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,67 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadBundle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"policies/billing.json": {Data: []byte(`{
+			"public": { "remove_properties": [ "Debug" ] },
+			"internal": { "remove_properties": [ "Test" ] }
+		}`)},
+		"policies/teams/search/v2.json": {Data: []byte(`{ "default": {} }`)},
+		"policies/README.md":            {Data: []byte(`not a policy`)},
+	}
+
+	bundle, err := LoadBundle(fsys, "policies")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"billing/internal", "billing/public", "teams/search/v2/default"}
+	if !reflect.DeepEqual(bundle.Names(), expected) {
+		t.Errorf("Expected %v, got %v", expected, bundle.Names())
+	}
+
+	public, ok := bundle.Policy("billing/public")
+	if !ok {
+		t.Fatal("Expected billing/public to exist")
+	}
+	simplified, err := public.Simplify(ExampleStruct{Test: 1, Debug: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := simplified.(ExampleStruct); result.Debug != "" || result.Test != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	if _, ok := bundle.Policy("billing/missing"); ok {
+		t.Error("Expected an unknown policy to be missing")
+	}
+}
+
+func TestLoadBundleCollision(t *testing.T) {
+	fsys := fstest.MapFS{
+		"billing.json":    {Data: []byte(`{ "eu/public": {} }`)},
+		"billing/eu.json": {Data: []byte(`{ "public": {} }`)},
+	}
+
+	_, err := LoadBundle(fsys, ".")
+	if err == nil || !strings.Contains(err.Error(), `policy "billing/eu/public" is already declared`) {
+		t.Errorf("Expected a collision error, got %v", err)
+	}
+}
+
+func TestLoadBundleInvalidFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"billing.json": {Data: []byte(`{ "public": [] }`)},
+	}
+
+	if _, err := LoadBundle(fsys, "."); err == nil || !strings.Contains(err.Error(), "billing.json") {
+		t.Errorf("Expected an error naming the file, got %v", err)
+	}
+}
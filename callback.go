@@ -0,0 +1,44 @@
+package gosimplifier
+
+import "reflect"
+
+// RemovalEvent describes a value removed by the rules, see WithOnRemove.
+type RemovalEvent struct {
+	// Rule is the location of the removing rule in the rule tree, see Node.RulePath
+	Rule string
+	// Path is the location of the removed value, e.g. "EntityList[3].SubProperties.ABC"
+	Path string
+	// Type is the type of the removed value
+	Type reflect.Type
+}
+
+// WithOnRemove registers fn to be called for every value the rules remove, e.g. to export
+// counters of how often each rule triggers:
+//
+//	gosimplifier.WithOnRemove(func(e gosimplifier.RemovalEvent) {
+//		removals.WithLabelValues(e.Rule).Inc()
+//	})
+//
+// fn is called during the Simplify call, before the value is removed, and from several
+// goroutines with WithParallelism, so it must be fast and safe for concurrent use. Several
+// callbacks can be registered, and are called in order.
+func WithOnRemove(fn func(RemovalEvent)) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, onRemoveMiddleware(fn))
+	}
+}
+
+func onRemoveMiddleware(fn func(RemovalEvent)) Middleware {
+	return func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.parent != nil && node.Removing() {
+				var t reflect.Type
+				if node.Value.IsValid() {
+					t = node.Value.Type()
+				}
+				fn(RemovalEvent{Rule: node.RulePath(), Path: node.Path(), Type: t})
+			}
+			return next(node)
+		}
+	}
+}
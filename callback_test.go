@@ -0,0 +1,30 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithOnRemove(t *testing.T) {
+	var events []RemovalEvent
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"mask_properties": [ "Test" ],
+		"property_simplifiers": { "Data": { "remove_properties": [ "DataDebug" ] } }
+	}`, WithOnRemove(func(e RemovalEvent) {
+		events = append(events, e)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := simplifier.Simplify(ExampleStruct0{Debug: "d", Data: DataStruct{DataDebug: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []RemovalEvent{
+		{Rule: "Debug", Path: "Debug", Type: reflect.TypeOf("")},
+		{Rule: "Data.DataDebug", Path: "Data.DataDebug", Type: reflect.TypeOf(0)},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, events)
+	}
+}
@@ -0,0 +1,73 @@
+package gosimplifier
+
+import "strings"
+
+// WithCaseInsensitiveMatch matches the property names of the rules against struct fields and
+// map keys regardless of case, so "debug" in a rule matches both the Debug field and a "DEBUG"
+// map key. A rule whose name matches exactly is still preferred.
+func WithCaseInsensitiveMatch() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// foldedRuler is a ruler together with the name of its rule, found by the lower-cased name.
+type foldedRuler struct {
+	name  string
+	ruler ruler
+}
+
+// foldNames indexes the rules of s and the simplifiers below it by their lower-cased names.
+func (s *simplifierImpl) foldNames(visited map[*simplifierImpl]bool) {
+	if visited[s] {
+		return
+	}
+	visited[s] = true
+	s.folded = make(map[string]foldedRuler, len(s.propertySimplifiers))
+	for _, name := range sortedKeys(s.propertySimplifiers) {
+		r := s.propertySimplifiers[name]
+		if _, ok := s.folded[strings.ToLower(name)]; !ok {
+			s.folded[strings.ToLower(name)] = foldedRuler{name: name, ruler: r}
+		}
+		if sub, ok := r.(*simplifierImpl); ok {
+			sub.foldNames(visited)
+		}
+	}
+	for _, rule := range s.indexRules {
+		if sub, ok := rule.ruler.(*simplifierImpl); ok {
+			sub.foldNames(visited)
+		}
+	}
+}
+
+// propertyRuler returns the name of the rule for the property name and its ruler, or nil if no
+// rule names the property.
+func (s *simplifierImpl) propertyRuler(name string) (string, ruler) {
+	if r := s.propertySimplifiers[name]; r != nil {
+		return name, r
+	}
+	if s.folded != nil {
+		if f, ok := s.folded[strings.ToLower(name)]; ok {
+			return f.name, f.ruler
+		}
+	}
+	return "", nil
+}
+
+// sameName reports whether two property names are the same under the options.
+func (o *options) sameName(a string, b string) bool {
+	if o != nil && o.caseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// containsName reports whether names contains name under the options.
+func (o *options) containsName(names []string, name string) bool {
+	for _, n := range names {
+		if o.sameName(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type CasedExample struct {
+	Debug  string
+	Info   *SubStruct
+	Labels map[string]string
+}
+
+func TestWithCaseInsensitiveMatch(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "debug" ],
+		"property_simplifiers": {
+			"info": { "remove_properties": [ "TEST" ] },
+			"labels": { "remove_properties": [ "debug", "trace_*" ] }
+		}
+	}`, WithCaseInsensitiveMatch())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := CasedExample{
+		Debug:  "d",
+		Info:   &SubStruct{Test: "t", Debug: "d"},
+		Labels: map[string]string{"DEBUG": "1", "TRACE_ID": "2", "Env": "prod"},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := CasedExample{Info: &SubStruct{Debug: "d"}, Labels: map[string]string{"Env": "prod"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if err := ValidateForType(simplifier, reflect.TypeOf(CasedExample{})); err != nil {
+		t.Errorf("Expected the rules to validate, got %v", err)
+	}
+
+	exact, err := NewSimplifier(`{ "remove_properties": [ "debug" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = exact.Simplify(CasedExample{Debug: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.(CasedExample).Debug != "d" {
+		t.Errorf("Expected names to match exactly by default, got %+v", simplified)
+	}
+}
@@ -0,0 +1,494 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// generator writes the reflection-free simplifier of a type. Every pair of a type and the rule
+// applied to it becomes a function returning the simplified copy of a value of the type.
+type generator struct {
+	pkg  *types.Package
+	root *gosimplifier.Rule
+	// rules numbers the distinct rules by their JSON form, the root rule being 0
+	rules map[string]int
+	// imports maps the paths of the imported packages to their names
+	imports map[string]string
+	// funcs maps the function keys to the names of the generated functions, which are numbered
+	// in the order of their keys, as different types may share an identifier
+	funcs   map[string]string
+	pending []pendingFunc
+	trivial map[string]bool
+	body    bytes.Buffer
+}
+
+type pendingFunc struct {
+	name string
+	t    types.Type
+	rule *gosimplifier.Rule
+	zero bool
+}
+
+// supportedSections are the sections of a rule the generated code implements.
+var supportedSections = map[string]bool{"RemoveProperties": true, "PropertySimplifiers": true}
+
+// generate returns the source of the simplifier of the named type, in the package of the type.
+func generate(named *types.Named, root *gosimplifier.Rule, rulesFile string) ([]byte, error) {
+	if err := checkRule(root, ""); err != nil {
+		return nil, err
+	}
+	g := &generator{
+		pkg:     named.Obj().Pkg(),
+		root:    root,
+		rules:   make(map[string]int),
+		imports: map[string]string{"github.com/xhinliang/gosimplifier": "gosimplifier"},
+		funcs:   make(map[string]string),
+		trivial: make(map[string]bool),
+	}
+	g.ruleID(root)
+
+	typeName := named.Obj().Name()
+	rootExpr, err := g.copyExpr(named, root, "original", typeName)
+	if err != nil {
+		return nil, err
+	}
+	for len(g.pending) > 0 {
+		f := g.pending[0]
+		g.pending = g.pending[1:]
+		if err := g.writeFunc(f); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by gosimplifier-gen from %s. DO NOT EDIT.\n\n", rulesFile)
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg.Name())
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	out.WriteString("import (\n")
+	for _, path := range paths {
+		if name := g.imports[path]; name != path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&out, "\t%s %q\n", name, path)
+		} else {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+	}
+	out.WriteString(")\n\n")
+	fmt.Fprintf(&out, "// %sSimplifier applies the rules of %s to %s values without reflection.\n", typeName, rulesFile, typeName)
+	fmt.Fprintf(&out, "type %sSimplifier struct{}\n\n", typeName)
+	fmt.Fprintf(&out, "var _ gosimplifier.TypedSimplifier[%s] = %sSimplifier{}\n\n", typeName, typeName)
+	fmt.Fprintf(&out, "// Simplify returns a simplified copy of original.\n")
+	fmt.Fprintf(&out, "func (%sSimplifier) Simplify(original %s) (%s, error) {\n", typeName, typeName, typeName)
+	fmt.Fprintf(&out, "\treturn %s, nil\n}\n", rootExpr)
+	out.Write(g.body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+// checkRule reports the sections of the rule tree the generated code cannot implement.
+func checkRule(rule *gosimplifier.Rule, path string) error {
+	v := reflect.ValueOf(rule).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !supportedSections[field.Name] && !v.Field(i).IsZero() {
+			section, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			return fmt.Errorf("rule %q: %s is not supported by the generated code", path, section)
+		}
+	}
+	names := append([]string{}, rule.RemoveProperties...)
+	for name := range rule.PropertySimplifiers {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if strings.ContainsAny(name, "*?[") {
+			return fmt.Errorf("rule %q: property %q: patterns and index selectors are not supported by the generated code", path, name)
+		}
+	}
+	for name, sub := range rule.PropertySimplifiers {
+		if err := checkRule(sub, joinPath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func (g *generator) ruleID(rule *gosimplifier.Rule) int {
+	key, _ := json.Marshal(rule)
+	id, ok := g.rules[string(key)]
+	if !ok {
+		id = len(g.rules)
+		g.rules[string(key)] = id
+	}
+	return id
+}
+
+// ruleFor returns the rule applied to the property name of a value simplified with rule, or nil
+// if the property is removed.
+func (g *generator) ruleFor(rule *gosimplifier.Rule, name string) *gosimplifier.Rule {
+	for _, removed := range rule.RemoveProperties {
+		if removed == name {
+			return nil
+		}
+	}
+	if sub, ok := rule.PropertySimplifiers[name]; ok {
+		return sub
+	}
+	return g.root
+}
+
+// copyExpr returns the expression of the simplified copy of x, a value of type t the rule is
+// applied to. path locates the value for error messages.
+func (g *generator) copyExpr(t types.Type, rule *gosimplifier.Rule, x string, path string) (string, error) {
+	trivial, err := g.isTrivial(t, rule, path, map[string]bool{})
+	if err != nil || trivial {
+		return x, err
+	}
+	key := fmt.Sprintf("copy %s %d", types.TypeString(t, nil), g.ruleID(rule))
+	name, ok := g.funcs[key]
+	if !ok {
+		name = fmt.Sprintf("simplify%s%d", g.ident(t), len(g.funcs))
+		g.funcs[key] = name
+		g.pending = append(g.pending, pendingFunc{name: name, t: t, rule: rule})
+	}
+	return name + "(" + x + ")", nil
+}
+
+// isTrivial reports whether the simplified copy of a value of type t is the value itself, as it
+// holds no references and the rules do not remove anything from it.
+func (g *generator) isTrivial(t types.Type, rule *gosimplifier.Rule, path string, inProgress map[string]bool) (bool, error) {
+	key := fmt.Sprintf("%s %d", types.TypeString(t, nil), g.ruleID(rule))
+	if trivial, ok := g.trivial[key]; ok {
+		return trivial, nil
+	}
+	if inProgress[key] {
+		return false, nil
+	}
+	inProgress[key] = true
+	trivial, err := g.isTrivial0(t, rule, path, inProgress)
+	if err != nil {
+		return false, err
+	}
+	g.trivial[key] = trivial
+	return trivial, nil
+}
+
+func (g *generator) isTrivial0(t types.Type, rule *gosimplifier.Rule, path string, inProgress map[string]bool) (bool, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic, *types.Signature, *types.Chan:
+		return true, nil
+	case *types.Interface:
+		return false, fmt.Errorf("%s: interface values are not supported by the generated code", path)
+	case *types.Array:
+		return g.isTrivial(u.Elem(), rule, path+"[]", inProgress)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !field.Exported() {
+				continue
+			}
+			fieldRule := g.fieldRule(rule, field)
+			if fieldRule == nil {
+				return false, nil
+			}
+			trivial, err := g.isTrivial(field.Type(), fieldRule, joinPath(path, field.Name()), inProgress)
+			if err != nil || !trivial {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	// pointers, slices and maps are copied, checking the values they refer to
+	var elem types.Type
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		elem = u.Elem()
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Map:
+		elem = u.Elem()
+	default:
+		return false, fmt.Errorf("%s: %s values are not supported by the generated code", path, t)
+	}
+	_, err := g.isTrivial(elem, rule, path+"[]", inProgress)
+	return false, err
+}
+
+// fieldRule is ruleFor for a struct field. The fields of embedded structs are promoted, so an
+// embedded struct no rule names is simplified with the rule of the struct embedding it.
+func (g *generator) fieldRule(rule *gosimplifier.Rule, field *types.Var) *gosimplifier.Rule {
+	fieldRule := g.ruleFor(rule, field.Name())
+	if fieldRule == g.root && field.Embedded() {
+		if _, named := rule.PropertySimplifiers[field.Name()]; !named && isStruct(field.Type()) {
+			return rule
+		}
+	}
+	return fieldRule
+}
+
+func isStruct(t types.Type) bool {
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func (g *generator) writeFunc(f pendingFunc) error {
+	if f.zero {
+		return g.writeZeroFunc(f)
+	}
+	typeName := g.typeString(f.t)
+	b := &g.body
+	fmt.Fprintf(b, "\nfunc %s(v %s) %s {\n", f.name, typeName, typeName)
+	switch u := f.t.Underlying().(type) {
+	case *types.Pointer:
+		elem, err := g.copyExpr(u.Elem(), f.rule, "*v", f.name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "if v == nil {\nreturn nil\n}\nout := new(%s)\n*out = %s\nreturn out\n", g.typeString(u.Elem()), elem)
+	case *types.Slice:
+		elem, err := g.copyExpr(u.Elem(), f.rule, "e", f.name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "if v == nil {\nreturn nil\n}\nout := make(%s, len(v), cap(v))\n", typeName)
+		fmt.Fprintf(b, "for i, e := range v {\nout[i] = %s\n}\nreturn out\n", elem)
+	case *types.Array:
+		elem, err := g.copyExpr(u.Elem(), f.rule, "e", f.name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "var out %s\nfor i, e := range v {\nout[i] = %s\n}\nreturn out\n", typeName, elem)
+	case *types.Map:
+		if err := g.writeMapBody(f, u); err != nil {
+			return err
+		}
+	case *types.Struct:
+		b.WriteString("out := v\n")
+		for i := 0; i < u.NumFields(); i++ {
+			field := u.Field(i)
+			if !field.Exported() {
+				continue
+			}
+			fieldRule := g.fieldRule(f.rule, field)
+			if fieldRule == nil {
+				fmt.Fprintf(b, "out.%s = %s\n", field.Name(), g.zeroLiteral(field.Type()))
+				continue
+			}
+			x := "v." + field.Name()
+			copied, err := g.copyExpr(field.Type(), fieldRule, x, joinPath(typeName, field.Name()))
+			if err != nil {
+				return err
+			}
+			if copied != x {
+				fmt.Fprintf(b, "out.%s = %s\n", field.Name(), copied)
+			}
+		}
+		b.WriteString("return out\n")
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+// writeMapBody copies a map, leaving out the zero values and the removed keys.
+func (g *generator) writeMapBody(f pendingFunc, m *types.Map) error {
+	b := &g.body
+	isZero, err := g.isZeroExpr(m.Elem(), "e")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "if v == nil {\nreturn nil\n}\nout := make(%s, len(v))\n", g.typeString(f.t))
+	fmt.Fprintf(b, "for k, e := range v {\nif %s {\ncontinue\n}\n", isZero)
+	if basic, ok := m.Key().Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+		if len(f.rule.RemoveProperties) > 0 || len(f.rule.PropertySimplifiers) > 0 {
+			b.WriteString("switch string(k) {\n")
+			if len(f.rule.RemoveProperties) > 0 {
+				fmt.Fprintf(b, "case %s:\ncontinue\n", quoteAll(f.rule.RemoveProperties))
+			}
+			for _, name := range sortedNames(f.rule.PropertySimplifiers) {
+				sub := g.ruleFor(f.rule, name)
+				if sub == nil {
+					continue
+				}
+				copied, err := g.copyExpr(m.Elem(), sub, "e", f.name)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(b, "case %s:\nout[k] = %s\ncontinue\n", strconv.Quote(name), copied)
+			}
+			b.WriteString("}\n")
+		}
+	}
+	copied, err := g.copyExpr(m.Elem(), g.root, "e", f.name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(b, "out[k] = %s\n}\nreturn out\n", copied)
+	return nil
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = strconv.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func sortedNames(m map[string]*gosimplifier.Rule) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isZeroExpr returns the expression reporting whether x, a value of type t, is the zero value,
+// as map entries holding it are removed.
+func (g *generator) isZeroExpr(t types.Type, x string) (string, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Map, *types.Signature:
+		return x + " == nil", nil
+	case *types.Struct, *types.Array:
+		if !types.Comparable(t) {
+			if s, ok := u.(*types.Struct); ok {
+				for i := 0; i < s.NumFields(); i++ {
+					if !s.Field(i).Exported() && s.Field(i).Pkg() != g.pkg {
+						return "", fmt.Errorf("%s: map values with unexported fields of another package are not supported by the generated code", t)
+					}
+				}
+			}
+			key := "zero " + types.TypeString(t, nil)
+			name, ok := g.funcs[key]
+			if !ok {
+				name = fmt.Sprintf("isZero%s%d", g.ident(t), len(g.funcs))
+				g.funcs[key] = name
+				g.pending = append(g.pending, pendingFunc{name: name, t: t, zero: true})
+			}
+			return name + "(" + x + ")", nil
+		}
+		return x + " == (" + g.typeString(t) + "{})", nil
+	}
+	return x + " == " + g.zeroLiteral(t), nil
+}
+
+func (g *generator) writeZeroFunc(f pendingFunc) error {
+	b := &g.body
+	fmt.Fprintf(b, "\nfunc %s(v %s) bool {\n", f.name, g.typeString(f.t))
+	switch u := f.t.Underlying().(type) {
+	case *types.Array:
+		isZero, err := g.isZeroExpr(u.Elem(), "e")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, "for _, e := range v {\nif !(%s) {\nreturn false\n}\n}\nreturn true\n", isZero)
+	case *types.Struct:
+		conditions := make([]string, u.NumFields())
+		for i := range conditions {
+			isZero, err := g.isZeroExpr(u.Field(i).Type(), "v."+u.Field(i).Name())
+			if err != nil {
+				return err
+			}
+			conditions[i] = "(" + isZero + ")"
+		}
+		if len(conditions) == 0 {
+			conditions = append(conditions, "true")
+		}
+		fmt.Fprintf(b, "return %s\n", strings.Join(conditions, " &&\n"))
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+// zeroLiteral returns the literal of the zero value of t.
+func (g *generator) zeroLiteral(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Kind() == types.UnsafePointer:
+			return "nil"
+		}
+		return "0"
+	case *types.Struct, *types.Array:
+		return g.typeString(t) + "{}"
+	}
+	return "nil"
+}
+
+// typeString returns the name of t in the generated code, importing the packages it refers to.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == g.pkg {
+			return ""
+		}
+		if name, ok := g.imports[pkg.Path()]; ok {
+			return name
+		}
+		name := pkg.Name()
+		for taken := true; taken; {
+			taken = false
+			for _, imported := range g.imports {
+				if imported == name {
+					name += "_"
+					taken = true
+				}
+			}
+		}
+		g.imports[pkg.Path()] = name
+		return name
+	})
+}
+
+// ident returns an identifier for t to name the generated functions with. It is not unique:
+// unnamed structs, for one, are all "Value".
+func (g *generator) ident(t types.Type) string {
+	switch t := t.(type) {
+	case *types.Named:
+		if t.Obj().Pkg() != nil && t.Obj().Pkg() != g.pkg {
+			return capitalize(t.Obj().Pkg().Name()) + t.Obj().Name()
+		}
+		return capitalize(t.Obj().Name())
+	case *types.Basic:
+		return capitalize(t.Name())
+	case *types.Pointer:
+		return "Ptr" + g.ident(t.Elem())
+	case *types.Slice:
+		return "Slice" + g.ident(t.Elem())
+	case *types.Array:
+		return "Array" + strconv.FormatInt(t.Len(), 10) + g.ident(t.Elem())
+	case *types.Map:
+		return "Map" + g.ident(t.Key()) + g.ident(t.Elem())
+	}
+	return "Value"
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestGenerateIsUpToDate(t *testing.T) {
+	dir := filepath.Join("internal", "sample")
+	output := filepath.Join(dir, "order_simplifier.go")
+	named, err := loadType(dir, "Order", output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := &gosimplifier.Rule{}
+	data, err := os.ReadFile(filepath.Join(dir, "rules.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, rule); err != nil {
+		t.Fatal(err)
+	}
+	source, err := generate(named, rule, "rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkedIn, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(source, checkedIn) {
+		t.Errorf("%s is out of date, run go generate", output)
+	}
+}
+
+func TestGenerateUnsupportedRules(t *testing.T) {
+	named, err := loadType(filepath.Join("internal", "sample"), "Order", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rules, expected := range map[string]string{
+		`{ "mask_properties": [ "Password" ] }`:                                       `rule "": mask_properties is not supported`,
+		`{ "property_simplifiers": { "Items": { "remove_properties": [ "[0]" ] } } }`: `rule "Items": property "[0]"`,
+	} {
+		rule := &gosimplifier.Rule{}
+		if err := json.Unmarshal([]byte(rules), rule); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := generate(named, rule, "rules.json"); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected an error containing %q for %s, got %v", expected, rules, err)
+		}
+	}
+}
+
+func TestGenerateAnonymousStructs(t *testing.T) {
+	dir := filepath.Join("testdata", "anonymous")
+	named, err := loadType(dir, "Pair", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := generate(named, &gosimplifier.Rule{}, "rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]interface{}{filepath.Join(dir, "anonymous.go"): nil, "pair_simplifier.go": source} {
+		file, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := config.Check("anonymous", fset, files, nil); err != nil {
+		t.Errorf("Expected the generated code to compile, got %v\n%s", err, source)
+	}
+}
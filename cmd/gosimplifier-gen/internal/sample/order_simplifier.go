@@ -0,0 +1,131 @@
+// Code generated by gosimplifier-gen from rules.json. DO NOT EDIT.
+
+package sample
+
+import (
+	"github.com/xhinliang/gosimplifier"
+)
+
+// OrderSimplifier applies the rules of rules.json to Order values without reflection.
+type OrderSimplifier struct{}
+
+var _ gosimplifier.TypedSimplifier[Order] = OrderSimplifier{}
+
+// Simplify returns a simplified copy of original.
+func (OrderSimplifier) Simplify(original Order) (Order, error) {
+	return simplifyOrder0(original), nil
+}
+
+func simplifyOrder0(v Order) Order {
+	out := v
+	out.Base = simplifyBase1(v.Base)
+	out.Password = ""
+	out.Items = simplifySlicePtrItem2(v.Items)
+	out.Address = simplifyPtrAddress3(v.Address)
+	out.Notes = simplifyMapStringSliceString4(v.Notes)
+	out.Parent = simplifyPtrOrder5(v.Parent)
+	return out
+}
+
+func simplifyBase1(v Base) Base {
+	out := v
+	out.Internal = ""
+	return out
+}
+
+func simplifySlicePtrItem2(v []*Item) []*Item {
+	if v == nil {
+		return nil
+	}
+	out := make([]*Item, len(v), cap(v))
+	for i, e := range v {
+		out[i] = simplifyPtrItem6(e)
+	}
+	return out
+}
+
+func simplifyPtrAddress3(v *Address) *Address {
+	if v == nil {
+		return nil
+	}
+	out := new(Address)
+	*out = simplifyAddress7(*v)
+	return out
+}
+
+func simplifyMapStringSliceString4(v map[string][]string) map[string][]string {
+	if v == nil {
+		return nil
+	}
+	out := make(map[string][]string, len(v))
+	for k, e := range v {
+		if e == nil {
+			continue
+		}
+		switch string(k) {
+		case "private":
+			continue
+		}
+		out[k] = simplifySliceString8(e)
+	}
+	return out
+}
+
+func simplifyPtrOrder5(v *Order) *Order {
+	if v == nil {
+		return nil
+	}
+	out := new(Order)
+	*out = simplifyOrder0(*v)
+	return out
+}
+
+func simplifyPtrItem6(v *Item) *Item {
+	if v == nil {
+		return nil
+	}
+	out := new(Item)
+	*out = simplifyItem9(*v)
+	return out
+}
+
+func simplifyAddress7(v Address) Address {
+	out := v
+	out.Street = ""
+	return out
+}
+
+func simplifySliceString8(v []string) []string {
+	if v == nil {
+		return nil
+	}
+	out := make([]string, len(v), cap(v))
+	for i, e := range v {
+		out[i] = e
+	}
+	return out
+}
+
+func simplifyItem9(v Item) Item {
+	out := v
+	out.Metadata = simplifyMapStringString10(v.Metadata)
+	return out
+}
+
+func simplifyMapStringString10(v map[string]string) map[string]string {
+	if v == nil {
+		return nil
+	}
+	out := make(map[string]string, len(v))
+	for k, e := range v {
+		if e == "" {
+			continue
+		}
+		switch string(k) {
+		case "trace":
+			continue
+		}
+		out[k] = e
+	}
+	return out
+}
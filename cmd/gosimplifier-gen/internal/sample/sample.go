@@ -0,0 +1,37 @@
+// Package sample holds a type with its generated simplifier, checked against the reflection
+// based Simplifier by the tests of gosimplifier-gen.
+package sample
+
+import "time"
+
+//go:generate go run github.com/xhinliang/gosimplifier/cmd/gosimplifier-gen -type Order -rules rules.json
+
+type Base struct {
+	ID        int
+	CreatedAt time.Time
+	Internal  string
+}
+
+type Item struct {
+	SKU      string
+	Price    float64
+	Debug    string
+	Metadata map[string]string
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Order struct {
+	Base
+	Customer string
+	Password string
+	Items    []*Item
+	Address  *Address
+	Notes    map[string][]string
+	Scores   [3]int
+	Parent   *Order
+	note     string
+}
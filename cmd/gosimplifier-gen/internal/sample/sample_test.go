@@ -0,0 +1,50 @@
+package sample
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestGeneratedSimplifier(t *testing.T) {
+	rules, err := os.ReadFile("rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplifier, err := gosimplifier.NewTypedSimplifier[Order](string(rules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := Order{
+		Base:     Base{ID: 1, CreatedAt: time.Unix(1700000000, 0), Internal: "i"},
+		Customer: "c",
+		Password: "p",
+		Items: []*Item{
+			{SKU: "a", Price: 1.5, Debug: "d", Metadata: map[string]string{"trace": "t", "color": "red", "empty": ""}},
+			nil,
+		},
+		Address: &Address{Street: "s", City: "c"},
+		Notes:   map[string][]string{"private": {"x"}, "public": {"y"}, "nil": nil},
+		Scores:  [3]int{1, 2, 3},
+		Parent:  &Order{Password: "p", Base: Base{Internal: "i"}},
+		note:    "n",
+	}
+	expected, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := OrderSimplifier{}.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(generated, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, generated)
+	}
+	if original.Password != "p" || original.Items[0].Metadata["trace"] != "t" || original.Address.Street != "s" {
+		t.Error("Expected the original to be unchanged")
+	}
+}
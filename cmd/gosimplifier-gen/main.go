@@ -0,0 +1,104 @@
+// Command gosimplifier-gen generates the reflection-free simplifier of a type from a rules file,
+// for latency-critical paths where the reflection of gosimplifier.Simplifier is too slow:
+//
+//	//go:generate gosimplifier-gen -type User -rules user_rules.json
+//
+// writes user_simplifier.go next to the type, declaring UserSimplifier, a
+// gosimplifier.TypedSimplifier[User] returning the same result as the Simplifier created from
+// the rules with no options. Only remove_properties and property_simplifiers are supported, and
+// the values the rules are applied to must not be held in interfaces; the generator fails
+// otherwise, pointing at the rule or type it cannot implement.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the type to generate the simplifier of (required)")
+	rulesFile := flag.String("rules", "", "path of the JSON rules file (required)")
+	dir := flag.String("dir", ".", "directory of the package declaring the type")
+	output := flag.String("o", "", "output file, <type>_simplifier.go in the package directory by default")
+	flag.Parse()
+	if *typeName == "" || *rulesFile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = filepath.Join(*dir, strings.ToLower(*typeName)+"_simplifier.go")
+	}
+	if err := run(*typeName, *rulesFile, *dir, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "gosimplifier-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(typeName string, rulesFile string, dir string, output string) error {
+	data, err := os.ReadFile(rulesFile)
+	if err != nil {
+		return err
+	}
+	rule := &gosimplifier.Rule{}
+	if err := json.Unmarshal(data, rule); err != nil {
+		return fmt.Errorf("%s: %w", rulesFile, err)
+	}
+	named, err := loadType(dir, typeName, output)
+	if err != nil {
+		return err
+	}
+	source, err := generate(named, rule, filepath.Base(rulesFile))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, source, 0o644)
+}
+
+// loadType type-checks the package in dir, leaving out the output file, which may be stale,
+// and returns the named type.
+func loadType(dir string, typeName string, output string) (*types.Named, error) {
+	buildPkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	outputPath, _ := filepath.Abs(output)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range buildPkg.GoFiles {
+		path := filepath.Join(dir, name)
+		if abs, _ := filepath.Abs(path); abs == outputPath {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check(buildPkg.ImportPath, fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a defined type", typeName)
+	}
+	return named, nil
+}
@@ -0,0 +1,8 @@
+package anonymous
+
+// Pair has fields of two different unnamed struct types, the simplifiers of which must not
+// share a name.
+type Pair struct {
+	A struct{ X []int }
+	B struct{ Y []string }
+}
@@ -0,0 +1,247 @@
+// Command gosimplifier applies a rules file to JSON documents, so data dumps can be scrubbed
+// without writing Go:
+//
+//	gosimplifier -rules rules.json < dump.json > scrubbed.json
+//	gosimplifier -rules rules.json -ndjson -w events-*.ndjson
+//	gosimplifier -rules rules.json -dry-run dump.json
+//	gosimplifier -rules rules.json -csv export.csv > scrubbed.csv
+//
+// The documents are read from the files given as arguments, or from stdin if there are none,
+// and the simplified documents are written to stdout, or back to their files with -w. With
+// -ndjson, every line is a document of its own. With -csv, the rules apply to the rows of CSV
+// files by column name, see gosimplifier.SimplifyCSV. With -dry-run, nothing is changed and the
+// changes the rules would make are listed instead, one per line:
+//
+//	dump.json: Users[0].Password: remove by Users.Password
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// config holds the flags of a run.
+type config struct {
+	ndjson  bool
+	csv     bool
+	inPlace bool
+	dryRun  bool
+}
+
+// run runs the command with args, returning its exit code.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gosimplifier", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	rulesFile := flags.String("rules", "", "path of the JSON rules file (required)")
+	var c config
+	flags.BoolVar(&c.ndjson, "ndjson", false, "read newline-delimited JSON, one document per line")
+	flags.BoolVar(&c.csv, "csv", false, "read CSV with a header, applying the rules to the rows by column name")
+	flags.BoolVar(&c.inPlace, "w", false, "write the simplified documents back to their files")
+	flags.BoolVar(&c.dryRun, "dry-run", false, "list the changes the rules would make instead of making them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	files := flags.Args()
+	if *rulesFile == "" || c.inPlace && (c.dryRun || len(files) == 0) || c.ndjson && c.csv {
+		fmt.Fprintln(stderr, "gosimplifier: -rules is required, -w requires files and excludes -dry-run, and -ndjson excludes -csv")
+		flags.Usage()
+		return 2
+	}
+	if err := c.run(*rulesFile, files, stdin, stdout); err != nil {
+		fmt.Fprintln(stderr, "gosimplifier:", err)
+		return 1
+	}
+	return 0
+}
+
+func (c config) run(rulesFile string, files []string, stdin io.Reader, stdout io.Writer) error {
+	rules, err := os.ReadFile(rulesFile)
+	if err != nil {
+		return err
+	}
+	simplifier, err := gosimplifier.NewSimplifier(string(rules))
+	if err != nil {
+		return fmt.Errorf("%s: %w", rulesFile, err)
+	}
+	out := bufio.NewWriter(stdout)
+	if len(files) == 0 {
+		if err := c.process(simplifier, "<stdin>", stdin, out); err != nil {
+			return err
+		}
+		return out.Flush()
+	}
+	for _, file := range files {
+		if c.inPlace {
+			err = c.rewrite(simplifier, file)
+		} else {
+			err = c.processFile(simplifier, file, out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
+
+func (c config) processFile(simplifier gosimplifier.Simplifier, file string, out io.Writer) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.process(simplifier, file, f, out)
+}
+
+// rewrite replaces the file with its simplified documents, through a temporary file renamed
+// over it so the file is never left half written.
+func (c config) rewrite(simplifier gosimplifier.Simplifier, file string) error {
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	var simplified bytes.Buffer
+	if err := c.processFile(simplifier, file, &simplified); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(simplified.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), info.Mode().Perm())
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// process simplifies the documents read from r, named name in errors and reports, to w.
+func (c config) process(simplifier gosimplifier.Simplifier, name string, r io.Reader, w io.Writer) error {
+	switch {
+	case c.dryRun && c.ndjson:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 64<<20)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			if err := report(simplifier, fmt.Sprintf("%s:%d", name, line), scanner.Bytes(), w); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	case c.dryRun && c.csv:
+		return reportCSV(simplifier, name, r, w)
+	case c.dryRun:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return report(simplifier, name, data, w)
+	case c.ndjson:
+		err := gosimplifier.SimplifyNDJSON(simplifier, r, w, gosimplifier.NDJSONConfig{})
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	case c.csv:
+		if err := gosimplifier.SimplifyCSV(simplifier, r, w, gosimplifier.CSVConfig{}); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	simplified, err := gosimplifier.SimplifyJSON(simplifier, data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if _, err := w.Write(simplified); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// report writes the changes the rules would make to the JSON document, one per line.
+func report(simplifier gosimplifier.Simplifier, name string, data []byte, w io.Writer) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return reportValue(simplifier, name, document, w)
+}
+
+// reportCSV writes the changes the rules would make to the rows of the CSV document, one per
+// line, named by their line number.
+func reportCSV(simplifier gosimplifier.Simplifier, name string, r io.Reader, w io.Writer) error {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := make(map[string]string, len(header))
+		for i, cell := range record {
+			if i < len(header) && cell != "" {
+				row[header[i]] = cell
+			}
+		}
+		if err := reportValue(simplifier, fmt.Sprintf("%s:%d", name, line), row, w); err != nil {
+			return err
+		}
+	}
+}
+
+// reportValue writes the changes the rules would make to value, one per line.
+func reportValue(simplifier gosimplifier.Simplifier, name string, value interface{}, w io.Writer) error {
+	dryRun, err := gosimplifier.DryRun(simplifier, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, change := range dryRun.Changes {
+		rule := ""
+		if change.Rule != "" {
+			rule = " by " + change.Rule
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s: %s%s\n", name, change.Path, change.Action, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRules = `{
+	"remove_properties": [ "Password" ],
+	"property_simplifiers": { "Users": { "remove_properties": [ "Token" ] } }
+}`
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunStdin(t *testing.T) {
+	rules := writeFile(t, t.TempDir(), "rules.json", testRules)
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader(`{"Name":"a","Password":"p","Users":[{"Id":1,"Token":"t"}]}`)
+	if code := run([]string{"-rules", rules}, stdin, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if expected := `{"Name":"a","Users":[{"Id":1}]}` + "\n"; stdout.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, stdout.String())
+	}
+}
+
+func TestRunInPlaceNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", testRules)
+	events := writeFile(t, dir, "events.ndjson", "{\"Id\":1,\"Password\":\"p\"}\n\n{\"Id\":2,\"Users\":[{\"Token\":\"t\"}]}\n")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-rules", rules, "-ndjson", "-w", events}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "{\"Id\":1}\n{\"Id\":2,\"Users\":[{}]}\n"; string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, data)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Expected no output with -w, got %s", stdout.String())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("Expected the temporary file to be renamed, got %d files", len(entries))
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", testRules)
+	dump := writeFile(t, dir, "dump.json", `{"Password":"p","Users":[{"Token":"t"}]}`)
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-rules", rules, "-dry-run", dump}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	expected := dump + ": Password: remove by Password\n" + dump + ": Users[0].Token: remove by Users.Token\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	stdin := strings.NewReader("{\"Id\":1}\n{\"Password\":\"p\"}\n")
+	if code := run([]string{"-rules", rules, "-dry-run", "-ndjson"}, stdin, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if expected := "<stdin>:2: Password: remove by Password\n"; stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", testRules)
+	export := writeFile(t, dir, "export.csv", "Id,Password,Note\n1,p,\"two\nlines\"\n2,q,\n")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-rules", rules, "-csv", export}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if expected := "Id,Note\n1,\"two\nlines\"\n2,\n"; stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-rules", rules, "-csv", "-dry-run", export}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	expected := export + ":2: Password: remove by Password\n" + export + ":4: Password: remove by Password\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := t.TempDir()
+	rules := writeFile(t, dir, "rules.json", testRules)
+	broken := writeFile(t, dir, "broken.json", `{"Password":`)
+	for _, c := range []struct {
+		args     []string
+		code     int
+		expected string
+	}{
+		{[]string{}, 2, "-rules is required"},
+		{[]string{"-rules", rules, "-w"}, 2, "-w requires files"},
+		{[]string{"-rules", rules, "-w", "-dry-run", broken}, 2, "excludes -dry-run"},
+		{[]string{"-rules", rules, "-csv", "-ndjson"}, 2, "-ndjson excludes -csv"},
+		{[]string{"-rules", rules, broken}, 1, broken + ":"},
+		{[]string{"-rules", broken}, 1, broken + ":"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(c.args, strings.NewReader("{}"), &stdout, &stderr); code != c.code || !strings.Contains(stderr.String(), c.expected) {
+			t.Errorf("%q: expected exit code %d and %q, got %d and %s", c.args, c.code, c.expected, code, stderr.String())
+		}
+	}
+}
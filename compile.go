@@ -0,0 +1,147 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Plan is the result of Compile: a Simplifier ready to apply the rules, together with what the
+// compilation noticed about them.
+type Plan struct {
+	Simplifier
+	// Warnings lists the rules that will not act as written, e.g. rules shadowed by another
+	// action for the same property, or properties unknown to the compiled type.
+	Warnings []Warning
+	// Actions counts the properties each action, e.g. "remove", applies to across the rule tree.
+	Actions map[string]int
+	// Expansions counts the fields of the compiled type each glob pattern applies to, keyed by
+	// the rule path of the pattern, e.g. "Data.*". Patterns only apply to the fields no rule
+	// names and except does not list, and only "*" applies to struct fields at all, the others
+	// matching map keys, which are not known before the rules are applied. It is nil if Compile
+	// is not given a type.
+	Expansions map[string]int
+}
+
+// Compile compiles the rules into a Plan without applying them, so deploy pipelines can gate on
+// the warnings before the rules go live. If t is not nil, the rules are also checked against
+// it like ValidateForType does. Rules that cannot be compiled at all fail with an error.
+func Compile(rulesJson string, t reflect.Type, opts ...Option) (*Plan, error) {
+	simplifier, err := NewSimplifier(rulesJson, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := simplifier.(*simplifierImpl)
+	plan := &Plan{Simplifier: s, Warnings: s.shadowWarnings(""), Actions: make(map[string]int)}
+	if t != nil {
+		s.validateForType(t, "", s.options, &plan.Warnings)
+		plan.Expansions = make(map[string]int)
+		s.countExpansions(t, "", s.options, plan.Expansions, map[reflect.Type]bool{})
+	}
+	s.countActions(plan.Actions)
+	return plan, nil
+}
+
+// ruleSection is a list of properties of a Rule sharing an action.
+type ruleSection struct {
+	name  string
+	props []string
+}
+
+// ruleSections returns the property sections of rule in increasing precedence, as applied by
+// createPropertySimplifiers.
+func ruleSections(rule *Rule) []ruleSection {
+	return []ruleSection{
+		{"property_simplifiers", sortedKeys(rule.PropertySimplifiers)},
+		{"truncate_properties", sortedKeys(rule.TruncateProperties)},
+		{"redact_properties", rule.RedactProperties},
+		{"mask_properties", rule.MaskProperties},
+		{"hash_properties", rule.HashProperties},
+		{"tokenize_properties", rule.TokenizeProperties},
+		{"replace_properties", sortedKeys(rule.ReplaceProperties)},
+		{"transform_properties", sortedKeys(rule.TransformProperties)},
+		{"inject_properties", sortedKeys(rule.InjectProperties)},
+		{"remove_properties", rule.RemoveProperties},
+	}
+}
+
+// shadowWarnings reports the entries of the rules of s, located at path in the rule tree, that
+// are overridden by an entry of higher precedence for the same property.
+func (s *simplifierImpl) shadowWarnings(path string) []Warning {
+	var warnings []Warning
+	// seen maps the properties to the index of the section with the highest precedence naming them
+	seen := make(map[string]int)
+	sections := ruleSections(s.rule)
+	for i := len(sections) - 1; i >= 0; i-- {
+		for _, propName := range sections[i].props {
+			if j, ok := seen[propName]; ok {
+				if j != i {
+					warnings = append(warnings, Warning{joinRulePath(path, propName),
+						fmt.Sprintf("%s entry is unreachable, the %s action takes precedence",
+							sections[i].name, s.propertySimplifiers[propName].action())})
+				}
+				continue
+			}
+			seen[propName] = i
+		}
+	}
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Path < warnings[j].Path
+	})
+
+	for _, propName := range sortedKeys(s.rule.PropertySimplifiers) {
+		if sub, ok := s.propertySimplifiers[propName].(*simplifierImpl); ok {
+			warnings = append(warnings, sub.shadowWarnings(joinRulePath(path, propName))...)
+		}
+	}
+	return warnings
+}
+
+// countActions adds the number of properties each action of the rule tree applies to.
+func (s *simplifierImpl) countActions(actions map[string]int) {
+	for _, r := range s.propertySimplifiers {
+		if sub, ok := r.(*simplifierImpl); ok {
+			sub.countActions(actions)
+			continue
+		}
+		actions[r.action()]++
+	}
+}
+
+// countExpansions adds the number of fields of t each glob pattern of the rules of s, located at
+// path in the rule tree, applies to, resolving the fields like applyStructRules does.
+func (s *simplifierImpl) countExpansions(t reflect.Type, path string, o *options, expansions map[string]int, inProgress map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	for _, glob := range s.globRules {
+		expansions[joinRulePath(path, glob.pattern)] += 0
+	}
+	if t.Kind() != reflect.Struct || inProgress[t] {
+		return
+	}
+	inProgress[t] = true
+	defer delete(inProgress, t)
+
+	fieldNames := o.fieldNames(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name := fieldNames[i]
+		if _, r := s.propertyRuler(name); r != nil {
+			if sub, ok := r.(*simplifierImpl); ok {
+				sub.countExpansions(field.Type, joinRulePath(path, name), o, expansions, inProgress)
+			}
+			continue
+		}
+		if isEmbeddedStruct(field) {
+			s.countExpansions(field.Type, path, o, expansions, inProgress)
+			continue
+		}
+		if s.removesAll() && !s.excepts(name) {
+			expansions[joinRulePath(path, "*")]++
+		}
+	}
+}
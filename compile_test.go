@@ -0,0 +1,77 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	plan, err := Compile(`{
+		"remove_properties": [ "Debug", "Debug" ],
+		"mask_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest", "Missing" ],
+				"hash_properties": [ "DataTest" ]
+			}
+		}
+	}`, reflect.TypeOf(ExampleStruct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Warning{
+		{"Debug", "mask_properties entry is unreachable, the remove action takes precedence"},
+		{"Data.DataTest", "hash_properties entry is unreachable, the remove action takes precedence"},
+		{"Data.Missing", "unknown property of gosimplifier.DataStruct"},
+	}
+	if !reflect.DeepEqual(plan.Warnings, expected) {
+		t.Errorf("Expected %v, got %v", expected, plan.Warnings)
+	}
+	if expectedActions := map[string]int{"remove": 3}; !reflect.DeepEqual(plan.Actions, expectedActions) {
+		t.Errorf("Expected %v, got %v", expectedActions, plan.Actions)
+	}
+
+	simplified, err := plan.Simplify(ExampleStruct{Debug: "debug", Test: 1})
+	if err != nil || !reflect.DeepEqual(simplified, ExampleStruct{Test: 1}) {
+		t.Errorf("Expected the plan to apply the rules, got %+v, %v", simplified, err)
+	}
+}
+
+func TestCompileWithoutType(t *testing.T) {
+	plan, err := Compile(`{ "remove_properties": [ "Anything" ] }`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Warnings) != 0 {
+		t.Errorf("Unexpected warnings %v", plan.Warnings)
+	}
+	if _, err := Compile(`{ "remove_properties": `, nil); err == nil {
+		t.Error("Expected invalid rules to fail")
+	}
+}
+
+func TestCompileExpansions(t *testing.T) {
+	plan, err := Compile(`{
+		"remove_properties": [ "*", "Test*" ],
+		"except": [ "Data" ],
+		"property_simplifiers": {
+			"Nest": { "remove_properties": [ "*" ], "except": [ "Test" ] }
+		}
+	}`, reflect.TypeOf(&ExampleStruct{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"*": 3, "Test*": 0, "Nest.*": 3}
+	if !reflect.DeepEqual(plan.Expansions, expected) {
+		t.Errorf("Expected %v, got %v", expected, plan.Expansions)
+	}
+
+	plan, err = Compile(`{ "remove_properties": [ "*" ] }`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Expansions != nil {
+		t.Errorf("Expected no expansions without a type, got %v", plan.Expansions)
+	}
+}
@@ -0,0 +1,227 @@
+package gosimplifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// composed applies several simplifiers in sequence.
+type composed struct {
+	simplifiers []Simplifier
+}
+
+// Compose returns a Simplifier applying the simplifiers in sequence, each to the result of the
+// previous one, e.g. a PII scrubber then a size trimmer:
+//
+//	simplifier := gosimplifier.Compose(scrubber, trimmer)
+//
+// A call fails on the first simplifier failing. Partial results, see WithBestEffort, are passed
+// on to the next simplifier, and the first *PartialError is returned with the final result.
+func Compose(simplifiers ...Simplifier) Simplifier {
+	return &composed{simplifiers: append([]Simplifier(nil), simplifiers...)}
+}
+
+// Simplify applies the simplifiers in sequence.
+func (c *composed) Simplify(original interface{}) (interface{}, error) {
+	return c.SimplifyContext(context.Background(), original)
+}
+
+// SimplifyContext applies the simplifiers in sequence with ctx.
+func (c *composed) SimplifyContext(ctx context.Context, original interface{}) (interface{}, error) {
+	var partial error
+	value := original
+	for _, s := range c.simplifiers {
+		var err error
+		if value, err = SimplifyContext(ctx, s, value); err != nil {
+			if !isPartial(err) {
+				return nil, err
+			}
+			if partial == nil {
+				partial = err
+			}
+		}
+	}
+	return value, partial
+}
+
+// SimplifyInPlace applies the simplifiers in sequence to the value behind ptr.
+func (c *composed) SimplifyInPlace(ptr interface{}) error {
+	var partial error
+	for _, s := range c.simplifiers {
+		if err := SimplifyInPlace(s, ptr); err != nil {
+			if !isPartial(err) {
+				return err
+			}
+			if partial == nil {
+				partial = err
+			}
+		}
+	}
+	return partial
+}
+
+// SimplifyInto writes the result of the first simplifier into dst, and applies the others to
+// dst in place.
+func (c *composed) SimplifyInto(original interface{}, dst interface{}) error {
+	if len(c.simplifiers) == 0 {
+		return errors.New("SimplifyInto requires at least one simplifier to compose")
+	}
+	var partial error
+	if err := SimplifyInto(c.simplifiers[0], original, dst); err != nil {
+		if !isPartial(err) {
+			return err
+		}
+		partial = err
+	}
+	if err := SimplifyInPlace(Compose(c.simplifiers[1:]...), dst); err != nil {
+		if !isPartial(err) || partial == nil {
+			return err
+		}
+	}
+	return partial
+}
+
+// SimplifyJSON applies the simplifiers in sequence to the JSON document.
+func (c *composed) SimplifyJSON(data []byte) ([]byte, error) {
+	var partial error
+	for _, s := range c.simplifiers {
+		var err error
+		if data, err = SimplifyJSON(s, data); err != nil {
+			if !isPartial(err) {
+				return nil, err
+			}
+			if partial == nil {
+				partial = err
+			}
+		}
+	}
+	return data, partial
+}
+
+// DryRun reports the changes of every simplifier, each applied to the result of the previous
+// one, in sequence.
+func (c *composed) DryRun(original interface{}) (*Report, error) {
+	_, report, err := c.dryRun(original)
+	return report, err
+}
+
+func (c *composed) dryRun(original interface{}) (interface{}, *Report, error) {
+	report := &Report{dryRun: true}
+	value := original
+	for _, s := range c.simplifiers {
+		runner, ok := s.(interface {
+			dryRun(original interface{}) (interface{}, *Report, error)
+		})
+		if !ok {
+			return nil, nil, fmt.Errorf("DryRun does not support composing %T", s)
+		}
+		simplified, stageReport, err := runner.dryRun(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		value = simplified
+		report.Changes = append(report.Changes, stageReport.Changes...)
+	}
+	return value, report, nil
+}
+
+// SimplifyWithAudit applies the simplifiers in sequence, returning the paths removed by each.
+func (c *composed) SimplifyWithAudit(original interface{}) (interface{}, []string, error) {
+	var partial error
+	var removed []string
+	value := original
+	for _, s := range c.simplifiers {
+		simplified, stageRemoved, err := SimplifyWithAudit(s, value)
+		if err != nil {
+			if !isPartial(err) {
+				return nil, nil, err
+			}
+			if partial == nil {
+				partial = err
+			}
+		}
+		value = simplified
+		removed = append(removed, stageRemoved...)
+	}
+	return value, removed, partial
+}
+
+// SimplifyToMap applies the simplifiers in sequence and returns the result as a map without the
+// properties any of them removed. Fields are named, and flattened, as the last simplifier says.
+func (c *composed) SimplifyToMap(original interface{}) (map[string]interface{}, error) {
+	if err := checkMappable(original); err != nil {
+		return nil, err
+	}
+	simplified, removed, err := c.SimplifyWithAudit(original)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	return toMap(simplified, removed, c.mapRoot()), err
+}
+
+func (c *composed) mapRoot() *simplifierImpl {
+	if len(c.simplifiers) == 0 {
+		return nil
+	}
+	if s, ok := c.simplifiers[len(c.simplifiers)-1].(interface{ mapRoot() *simplifierImpl }); ok {
+		return s.mapRoot()
+	}
+	return nil
+}
+
+// ValidateForType validates every simplifier against t, returning a *ValidationError with the
+// problems of all of them.
+func (c *composed) ValidateForType(t reflect.Type) error {
+	var problems []string
+	for _, s := range c.simplifiers {
+		err := ValidateForType(s, t)
+		if err == nil {
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+		problems = append(problems, validationErr.Problems...)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Type: t, Problems: problems}
+}
+
+// Extend composes the simplifiers with the last one extended by rule, see ExtendSimplifier.
+func (c *composed) Extend(rule *Rule) (Simplifier, error) {
+	if len(c.simplifiers) == 0 {
+		return NewSimplifierByRule(rule)
+	}
+	last := len(c.simplifiers) - 1
+	extended, err := ExtendSimplifierByRule(c.simplifiers[last], rule)
+	if err != nil {
+		return nil, err
+	}
+	return Compose(append(c.simplifiers[:last:last], extended)...), nil
+}
+
+// Clone composes clones of the simplifiers.
+func (c *composed) Clone() Simplifier {
+	clones := make([]Simplifier, len(c.simplifiers))
+	for i, s := range c.simplifiers {
+		clones[i] = Clone(s)
+	}
+	return Compose(clones...)
+}
+
+// Rules returns the rules of the simplifiers merged, which describe what the simplifiers do
+// together as long as their rules do not overlap.
+func (c *composed) Rules() *Rule {
+	rule := &Rule{}
+	for _, s := range c.simplifiers {
+		if stage := Rules(s); stage != nil {
+			rule = mergeRules(rule, stage)
+		}
+	}
+	return rule
+}
@@ -0,0 +1,76 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	scrubber, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trimmer, err := NewSimplifier(`{ "truncate_properties": { "Test": 2 } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplifier := Compose(scrubber, trimmer)
+
+	original := SubStruct{Test: "test", Debug: "debug"}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified != (SubStruct{Test: "te"}) {
+		t.Errorf("Expected both simplifiers to apply, got %+v", simplified)
+	}
+	if original.Debug != "debug" {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	inPlace := SubStruct{Test: "test", Debug: "debug"}
+	if err := SimplifyInPlace(simplifier, &inPlace); err != nil || inPlace != (SubStruct{Test: "te"}) {
+		t.Errorf("Expected SimplifyInPlace to apply both simplifiers, got %+v, %v", inPlace, err)
+	}
+	var dst SubStruct
+	if err := SimplifyInto(simplifier, original, &dst); err != nil || dst != (SubStruct{Test: "te"}) {
+		t.Errorf("Expected SimplifyInto to apply both simplifiers, got %+v, %v", dst, err)
+	}
+	data, err := SimplifyJSON(simplifier, []byte(`{"Test":"test","Debug":"debug"}`))
+	if err != nil || string(data) != `{"Test":"te"}` {
+		t.Errorf("Expected SimplifyJSON to apply both simplifiers, got %s, %v", data, err)
+	}
+
+	report, err := DryRun(simplifier, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{{Path: "Debug", Action: "remove", Rule: "Debug"}, {Path: "Test", Action: "truncate", Rule: "Test"}}
+	if !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, report.Changes)
+	}
+	result, err := SimplifyToMap(simplifier, original)
+	if err != nil || !reflect.DeepEqual(result, map[string]interface{}{"Test": "te"}) {
+		t.Errorf("Expected the map without the removed field, got %v, %v", result, err)
+	}
+	rules := Rules(simplifier)
+	if !reflect.DeepEqual(rules.RemoveProperties, []string{"Debug"}) || rules.TruncateProperties["Test"] != 2 {
+		t.Errorf("Expected the merged rules, got %+v", rules)
+	}
+}
+
+func TestComposeValidateForType(t *testing.T) {
+	first, err := NewSimplifier(`{ "remove_properties": [ "Unknown" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := NewSimplifier(`{ "remove_properties": [ "Missing" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ValidateForType(Compose(first, second), reflect.TypeOf(SubStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "Unknown") || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Expected the problems of both simplifiers, got %v", err)
+	}
+}
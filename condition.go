@@ -0,0 +1,178 @@
+package gosimplifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RemoveIfRule removes a value, or some of its properties, when one of its fields holds a given
+// value. The rule is checked against every struct or map its rule is applied to.
+//
+// Example, dropping the notes of internal tickets:
+//
+//	{
+//	  "property_simplifiers": {
+//	    "Tickets": {
+//	      "remove_if": { "field": "Status", "equals": "internal", "properties": [ "Notes" ] }
+//	    }
+//	  }
+//	}
+//
+// Without properties, the whole ticket is removed instead: matching elements are dropped from
+// the Tickets slice, so the list only holds the remaining ones. The root value is never removed.
+type RemoveIfRule struct {
+	// Field is the field or map key whose value is compared
+	Field string `json:"field"`
+	// Equals is the value Field must hold, compared by its JSON encoding, so 1 matches both
+	// int and float64 fields
+	Equals interface{} `json:"equals"`
+	// Properties are removed when the condition holds; if empty the value itself is removed
+	Properties []string `json:"properties,omitempty"`
+}
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// condition is the compiled form of a RemoveIfRule.
+type condition struct {
+	rule   *RemoveIfRule
+	equals []byte
+}
+
+func newCondition(rule *RemoveIfRule) (*condition, error) {
+	if rule == nil {
+		return nil, nil
+	}
+	if rule.Field == "" {
+		return nil, fmt.Errorf("remove_if requires a field")
+	}
+	equals, err := json.Marshal(rule.Equals)
+	if err != nil {
+		return nil, fmt.Errorf("remove_if %q: %w", rule.Field, err)
+	}
+	return &condition{rule: rule, equals: equals}, nil
+}
+
+// holds reports whether the field of the struct or map value equals the expected value.
+func (c *condition) holds(value reflect.Value, o *options) bool {
+	var field reflect.Value
+	switch value.Kind() {
+	case reflect.Struct:
+		if structField, ok := o.fieldByName(value.Type(), c.rule.Field); ok {
+			field, _ = value.FieldByIndexErr(structField.Index)
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() == reflect.String {
+			field = value.MapIndex(reflect.ValueOf(c.rule.Field).Convert(value.Type().Key()))
+		}
+	}
+	if !field.IsValid() || !field.CanInterface() {
+		return false
+	}
+	encoded, err := json.Marshal(field.Interface())
+	return err == nil && bytes.Equal(encoded, c.equals)
+}
+
+// conditionalRemovals returns the properties of value to remove because of remove_if and
+// remove_if_zero, and whether value itself is to be removed.
+func (s *simplifierImpl) conditionalRemovals(value reflect.Value, o *options) ([]string, bool) {
+	var removals []string
+	if s.removeIf != nil && s.removeIf.holds(value, o) {
+		if len(s.removeIf.rule.Properties) == 0 {
+			return nil, true
+		}
+		removals = s.removeIf.rule.Properties
+	}
+	for _, propName := range s.rule.RemoveIfZero {
+		if holdsZero(value, propName, o) {
+			// the properties of remove_if are clipped, so appending never writes into the rule
+			removals = append(removals[:len(removals):len(removals)], propName)
+		}
+	}
+	return removals, false
+}
+
+// holdsZero reports whether the property of the struct or map value holds its zero value,
+// looking through interfaces, e.g. at the float64 0 of a decoded JSON number. A json.Number,
+// as decoded by SimplifyJSON, is zero if it is numerically.
+func holdsZero(value reflect.Value, propName string, o *options) bool {
+	var property reflect.Value
+	switch value.Kind() {
+	case reflect.Struct:
+		if structField, ok := o.fieldByName(value.Type(), propName); ok {
+			property, _ = value.FieldByIndexErr(structField.Index)
+		}
+	case reflect.Map:
+		if value.Type().Key().Kind() == reflect.String {
+			property = value.MapIndex(reflect.ValueOf(propName).Convert(value.Type().Key()))
+		}
+	}
+	for property.Kind() == reflect.Interface && !property.IsNil() {
+		property = property.Elem()
+	}
+	if !property.IsValid() {
+		return false
+	}
+	if property.Type() == jsonNumberType {
+		f, err := json.Number(property.String()).Float64()
+		return err == nil && f == 0
+	}
+	return property.IsZero()
+}
+
+// filterElements drops the elements of the slice that remove_if removes as a whole, returning
+// the elements to walk and, if any were dropped, the original index of each of them. Arrays
+// cannot shrink, so their elements are left to be reset one by one.
+func (s *simplifierImpl) filterElements(node *Node, slice reflect.Value) (reflect.Value, []int, error) {
+	if s.removeIf == nil || len(s.removeIf.rule.Properties) > 0 || slice.Kind() != reflect.Slice {
+		return slice, nil, nil
+	}
+	kept := make([]int, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		if !s.removeIf.holds(indirect(slice.Index(i)), node.walk.root.options) {
+			kept = append(kept, i)
+		}
+	}
+	if len(kept) == slice.Len() {
+		return slice, nil, nil
+	}
+	// the slice may be read through the node's value, which filtered replaces
+	slice = slice.Slice(0, slice.Len())
+	filtered := reflect.MakeSlice(slice.Type(), len(kept), len(kept))
+	for i, index := range kept {
+		filtered.Index(i).Set(slice.Index(index))
+	}
+	if !node.setIndirect(filtered) {
+		return slice, nil, nil
+	}
+	if node.walk.observesDrops() {
+		next := 0
+		for i := 0; i < slice.Len(); i++ {
+			if next < len(kept) && kept[next] == i {
+				next++
+				continue
+			}
+			if err := s.visitDropped(node, slice.Index(i), i); err != nil {
+				return filtered, kept, err
+			}
+		}
+	}
+	return filtered, kept, nil
+}
+
+// observesDrops reports whether the list elements the rules drop without walking them are to
+// be visited anyway, for the report of DryRun and SimplifyWithAudit or for the middlewares,
+// such as those of WithOnRemove and WithStats.
+func (w *walk) observesDrops() bool {
+	return w.report != nil || len(w.root.options.middlewares) > 0
+}
+
+// visitDropped visits a list element the rules drop as a removed node at its original index in
+// the list. The element is visited in a holder of its own, leaving the list as it is.
+func (s *simplifierImpl) visitDropped(node *Node, element reflect.Value, index int) error {
+	holder := reflect.MakeSlice(reflect.SliceOf(element.Type()), 1, 1)
+	holder.Index(0).Set(element)
+	child := node.child(holder, holder.Index(0), reflect.Value{}, "", index, s, removeRulerSingleton)
+	return node.walk.visitChild(child)
+}
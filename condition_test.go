@@ -0,0 +1,186 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Ticket struct {
+	ID     int
+	Status string
+	Notes  string
+}
+
+type Board struct {
+	Tickets []*Ticket
+	Pinned  map[string]interface{}
+}
+
+func TestRemoveIf(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Tickets": {
+				"remove_if": { "field": "Status", "equals": "internal", "properties": [ "Notes" ] }
+			},
+			"Pinned": {
+				"remove_if": { "field": "Level", "equals": 3 }
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := Board{
+		Tickets: []*Ticket{
+			{ID: 1, Status: "internal", Notes: "secret"},
+			{ID: 2, Status: "public", Notes: "visible"},
+		},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tickets := simplified.(Board).Tickets
+	if *tickets[0] != (Ticket{ID: 1, Status: "internal"}) || *tickets[1] != *original.Tickets[1] {
+		t.Errorf("Unexpected tickets %+v %+v", tickets[0], tickets[1])
+	}
+
+	document := map[string]interface{}{
+		"Pinned": map[string]interface{}{"Level": 3.0, "Text": "x"},
+	}
+	simplified, _ = simplifier.Simplify(document)
+	if expected := map[string]interface{}{}; !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the pinned object to be removed, got %v", simplified)
+	}
+
+	output, err := SimplifyJSON(simplifier, []byte(`{"Pinned":{"Level":2}}`))
+	if err != nil || string(output) != `{"Pinned":{"Level":2}}` {
+		t.Errorf("Expected the pinned object to be kept, got %s, %v", output, err)
+	}
+}
+
+func TestRemoveIfValidation(t *testing.T) {
+	if _, err := NewSimplifier(`{ "remove_if": { "equals": 1 } }`); err == nil {
+		t.Error("Expected remove_if without field to fail")
+	}
+	simplifier, _ := NewSimplifier(`{ "remove_if": { "field": "Stat", "equals": 1, "properties": [ "Notes" ] } }`)
+	err := ValidateForType(simplifier, reflect.TypeOf(Ticket{}))
+	if err == nil || !strings.Contains(err.Error(), "remove_if names unknown property Stat") {
+		t.Errorf("Expected the unknown field to be reported, got %v", err)
+	}
+}
+
+type TypedEntity struct {
+	Type string
+	Name string
+}
+
+func TestRemoveIfFiltersElements(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"property_simplifiers": {
+			"Entities": {
+				"remove_if": { "field": "Type", "equals": "debug" },
+				"remove_properties": [ "Name" ]
+			}
+		}
+	}`)
+
+	original := struct{ Entities []TypedEntity }{
+		Entities: []TypedEntity{{"debug", "a"}, {"user", "b"}, {"debug", "c"}, {"admin", "d"}},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []TypedEntity{{Type: "user"}, {Type: "admin"}}
+	if entities := reflect.ValueOf(simplified).Field(0).Interface(); !reflect.DeepEqual(entities, expected) {
+		t.Errorf("Expected %v, got %v", expected, entities)
+	}
+	if len(original.Entities) != 4 {
+		t.Error("Expected the original to be unchanged")
+	}
+
+	output, err := SimplifyJSON(simplifier, []byte(`{"Entities":[{"Type":"debug"},{"Type":"user","Name":"b"}]}`))
+	if err != nil || string(output) != `{"Entities":[{"Type":"user"}]}` {
+		t.Errorf("Unexpected JSON %s, %v", output, err)
+	}
+
+	rootFilter, _ := NewSimplifier(`{ "remove_if": { "field": "Type", "equals": "debug" } }`)
+	output, err = SimplifyJSON(rootFilter, []byte(`[{"Type":"debug"},{"Type":"user"}]`))
+	if err != nil || string(output) != `[{"Type":"user"}]` {
+		t.Errorf("Unexpected JSON %s, %v", output, err)
+	}
+}
+
+func TestRemoveIfReportsDroppedElements(t *testing.T) {
+	rules := `{
+		"property_simplifiers": {
+			"Entities": { "remove_if": { "field": "Type", "equals": "debug" } }
+		}
+	}`
+	original := struct{ Entities []TypedEntity }{
+		Entities: []TypedEntity{{"debug", "a"}, {"user", "b"}, {"debug", "c"}},
+	}
+	expected := []string{"Entities[0]", "Entities[2]"}
+
+	simplifier, _ := NewSimplifier(rules)
+	_, removed, err := SimplifyWithAudit(simplifier, original)
+	if err != nil || !reflect.DeepEqual(removed, expected) {
+		t.Errorf("Expected the audit to list %v, got %v, %v", expected, removed, err)
+	}
+	report, err := DryRun(simplifier, original)
+	if err != nil || !reflect.DeepEqual(report.Removed(), expected) {
+		t.Errorf("Expected the dry run to list %v, got %v, %v", expected, report, err)
+	}
+
+	var events []string
+	simplifier, _ = NewSimplifier(rules, WithOnRemove(func(event RemovalEvent) {
+		events = append(events, event.Path)
+	}))
+	if _, err := simplifier.Simplify(original); err != nil || !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected OnRemove for %v, got %v, %v", expected, events, err)
+	}
+}
+
+func TestRemoveIfZero(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Tickets": {
+				"remove_if": { "field": "Status", "equals": "internal", "properties": [ "Notes" ] },
+				"remove_if_zero": [ "ID" ]
+			},
+			"Pinned": { "remove_if_zero": [ "Level", "Note" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := Board{
+		Tickets: []*Ticket{{ID: 0, Status: "internal", Notes: "n"}, {ID: 2, Status: "open", Notes: "m"}},
+		Pinned:  map[string]interface{}{"Level": float64(0), "Note": "kept", "Other": float64(0)},
+	}
+	simplified, removed, err := SimplifyWithAudit(simplifier, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Board{
+		Tickets: []*Ticket{{Status: "internal"}, {ID: 2, Status: "open", Notes: "m"}},
+		Pinned:  map[string]interface{}{"Note": "kept", "Other": float64(0)},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if want := []string{"Tickets[0].ID", "Tickets[0].Notes", "Pinned.Level"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("Expected the zero values to be reported as removed, got %v", removed)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Pinned":{"Level":0,"Note":""},"Tickets":[{"ID":0,"Status":"open"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Pinned":{},"Tickets":[{"Status":"open"}]}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
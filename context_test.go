@@ -0,0 +1,52 @@
+package gosimplifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSimplifyContext(t *testing.T) {
+	items := make([]ExampleStruct, 1000)
+	for i := range items {
+		items[i] = ExampleStruct{Test: i, Debug: "debug"}
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	visited := 0
+	cancelAfter := func(next Walker) Walker {
+		return func(node *Node) error {
+			if visited++; visited == 100 {
+				cancel()
+			}
+			return next(node)
+		}
+	}
+
+	for _, opts := range [][]Option{{WithMiddleware(cancelAfter)}, {WithMiddleware(cancelAfter), WithBestEffort()}} {
+		visited = 0
+		ctx, cancel = context.WithCancel(context.Background())
+		simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, opts...)
+		simplified, err := SimplifyContext(ctx, simplifier, items)
+		if !errors.Is(err, context.Canceled) || simplified != nil {
+			t.Errorf("Expected the cancellation, got %v", err)
+		}
+		if visited > 100+contextCheckInterval {
+			t.Errorf("Expected the walk to stop soon after the cancellation, visited %d nodes", visited)
+		}
+		cancel()
+	}
+
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+	simplified, err := SimplifyContext(context.Background(), simplifier, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.([]ExampleStruct)[999].Debug != "" {
+		t.Error("Expected Debug to be removed")
+	}
+	if _, err := SimplifyContext(ctx, simplifier, items); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a done context to fail right away, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Copier is implemented by types copying themselves for simplifiers, e.g. types holding a
+// sync.Mutex, a connection or other state that must not be copied field by field.
+// CopyForSimplify returns a copy of the receiver, either of its type T or a *T, to which the
+// rules are then applied as usual. Simplify fails if it returns anything else.
+type Copier interface {
+	CopyForSimplify() interface{}
+}
+
+var copierType = reflect.TypeOf((*Copier)(nil)).Elem()
+
+var (
+	copiersMu sync.RWMutex
+	copiers   = make(map[reflect.Type]func(original interface{}) interface{})
+	// copyHooks caches the *copyHook of every type copied so far, so the copy of a value takes
+	// no lock once its type is known
+	copyHooks sync.Map
+)
+
+// RegisterCopier makes simplifiers copy the values of t with fn, for types that cannot
+// implement Copier, such as those of other packages. fn receives a value of type t and returns
+// its copy, either of type t or a pointer to it. A copier registered for a type takes
+// precedence over its CopyForSimplify method and over RegisterLeafType.
+//
+// RegisterCopier panics if fn is nil or t already has a copier, and is meant to be called from
+// init functions.
+func RegisterCopier(t reflect.Type, fn func(original interface{}) interface{}) {
+	copiersMu.Lock()
+	defer copiersMu.Unlock()
+	if t == nil || fn == nil {
+		panic("gosimplifier: RegisterCopier with a nil type or function")
+	}
+	if _, ok := copiers[t]; ok {
+		panic("gosimplifier: RegisterCopier called twice for " + t.String())
+	}
+	copiers[t] = fn
+	copyHooks.Delete(t)
+}
+
+// copyHook tells how the values of a type are copied, if not field by field.
+type copyHook struct {
+	// copier is the function registered with RegisterCopier
+	copier func(original interface{}) interface{}
+	// method is set when the type implements Copier, addrMethod when only its pointer does
+	method, addrMethod bool
+}
+
+// copies reports whether the values of the type have a copier or a CopyForSimplify method.
+func (h *copyHook) copies() bool {
+	return h.copier != nil || h.method || h.addrMethod
+}
+
+func lookupCopyHook(t reflect.Type) *copyHook {
+	if hook, ok := copyHooks.Load(t); ok {
+		return hook.(*copyHook)
+	}
+	copiersMu.RLock()
+	hook := &copyHook{
+		copier:     copiers[t],
+		method:     t.Implements(copierType),
+		addrMethod: t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(copierType),
+	}
+	copiersMu.RUnlock()
+	actual, _ := copyHooks.LoadOrStore(t, hook)
+	return actual.(*copyHook)
+}
+
+// copyByHook copies original with its registered copier or its CopyForSimplify method, and
+// reports false if it has neither. Values of unexported fields are left to the copy of the
+// struct holding them.
+func copyByHook(original reflect.Value) (reflect.Value, bool, error) {
+	if !original.CanInterface() || original.Kind() == reflect.Interface ||
+		original.Kind() == reflect.Ptr && original.IsNil() {
+		return reflect.Value{}, false, nil
+	}
+	t := original.Type()
+	hook := lookupCopyHook(t)
+	switch {
+	case hook.copier != nil:
+		copied, err := hookCopy(t, hook.copier(original.Interface()))
+		return copied, true, err
+	case hook.method:
+		copied, err := hookCopy(t, original.Interface().(Copier).CopyForSimplify())
+		return copied, true, err
+	case hook.addrMethod:
+		ptr := original
+		if original.CanAddr() {
+			ptr = original.Addr()
+		} else {
+			ptr = reflect.New(t)
+			ptr.Elem().Set(original)
+		}
+		copied, err := hookCopy(t, ptr.Interface().(Copier).CopyForSimplify())
+		return copied, true, err
+	}
+	return reflect.Value{}, false, nil
+}
+
+// hookCopy converts the copy returned by a hook to a value of type t. Hooks return either a t
+// or a pointer to one, and a *t copied by a method of its pointer may return a t, which is then
+// stored in a new pointer.
+func hookCopy(t reflect.Type, copied interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(copied)
+	switch {
+	case !value.IsValid():
+	case value.Type() == t:
+		return value, nil
+	case value.Type() == reflect.PointerTo(t) && !value.IsNil():
+		return value.Elem(), nil
+	case t.Kind() == reflect.Ptr && value.Type() == t.Elem():
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(value)
+		return ptr, nil
+	}
+	return reflect.Value{}, fmt.Errorf("copy of %s returned %T", t, copied)
+}
@@ -0,0 +1,149 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type copierConn struct {
+	ID     int
+	Secret string
+	mu     sync.Mutex
+	copies *int
+}
+
+func (c *copierConn) CopyForSimplify() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.copies++
+	return &copierConn{ID: c.ID, Secret: c.Secret, copies: c.copies}
+}
+
+type copierHandle struct {
+	Name   string
+	Token  string
+	handle *int
+}
+
+func init() {
+	RegisterCopier(reflect.TypeOf(copierHandle{}), func(original interface{}) interface{} {
+		h := original.(copierHandle)
+		return copierHandle{Name: h.Name, Token: h.Token}
+	})
+}
+
+type copierHolder struct {
+	Conn    copierConn
+	ConnPtr *copierConn
+	Handles []copierHandle
+}
+
+func TestCopier(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Conn": { "remove_properties": [ "Secret" ] },
+			"ConnPtr": { "remove_properties": [ "Secret" ] },
+			"Handles": { "remove_properties": [ "Token" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copies, handle := 0, 7
+	original := &copierHolder{
+		Conn:    copierConn{ID: 1, Secret: "s1", copies: &copies},
+		ConnPtr: &copierConn{ID: 2, Secret: "s2", copies: &copies},
+		Handles: []copierHandle{{Name: "h", Token: "t", handle: &handle}},
+	}
+	result, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holder := result.(*copierHolder)
+	if copies != 2 {
+		t.Errorf("Expected CopyForSimplify to be called twice, got %d", copies)
+	}
+	if holder.Conn.ID != 1 || holder.Conn.Secret != "" || holder.ConnPtr.ID != 2 || holder.ConnPtr.Secret != "" {
+		t.Errorf("Expected the rules to apply to the copies, got %q and %q", holder.Conn.Secret, holder.ConnPtr.Secret)
+	}
+	if holder.ConnPtr == original.ConnPtr {
+		t.Error("Expected ConnPtr to be copied")
+	}
+	if h := holder.Handles[0]; h.Name != "h" || h.Token != "" || h.handle != nil {
+		t.Errorf("Expected the registered copier to copy the handle, got %+v", h)
+	}
+	if original.Conn.Secret != "s1" || original.ConnPtr.Secret != "s2" || original.Handles[0].Token != "t" {
+		t.Error("Expected the original to be untouched")
+	}
+}
+
+// copierValueConn copies itself from its pointer into a value, which pointers to it then hold.
+type copierValueConn struct {
+	ID     int
+	Secret string
+	mu     sync.Mutex
+}
+
+func (c *copierValueConn) CopyForSimplify() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return copierValueConn{ID: c.ID, Secret: c.Secret}
+}
+
+type copierValueHolder struct {
+	Conn    copierValueConn
+	ConnPtr *copierValueConn
+}
+
+func TestCopierValueOfPointer(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Conn": { "remove_properties": [ "Secret" ] },
+			"ConnPtr": { "remove_properties": [ "Secret" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := &copierValueHolder{
+		Conn:    copierValueConn{ID: 1, Secret: "s1"},
+		ConnPtr: &copierValueConn{ID: 2, Secret: "s2"},
+	}
+	result, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holder := result.(*copierValueHolder)
+	if holder.ConnPtr == original.ConnPtr {
+		t.Error("Expected ConnPtr to point to a copy")
+	}
+	if holder.Conn.ID != 1 || holder.Conn.Secret != "" || holder.ConnPtr.ID != 2 || holder.ConnPtr.Secret != "" {
+		t.Errorf("Expected the rules to apply to the copies, got %q and %q", holder.Conn.Secret, holder.ConnPtr.Secret)
+	}
+	if original.ConnPtr.Secret != "s2" {
+		t.Error("Expected the original to be untouched")
+	}
+}
+
+type copierBroken struct {
+	Name string
+}
+
+func (copierBroken) CopyForSimplify() interface{} {
+	return "not a copierBroken"
+}
+
+func TestCopierWrongType(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Name" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := simplifier.Simplify(&struct{ Broken copierBroken }{})
+	if err == nil || result != nil {
+		t.Fatalf("Expected a copy of the wrong type to fail, got %v and %v", result, err)
+	}
+	if want := "copy of gosimplifier.copierBroken returned string"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err)
+	}
+}
@@ -0,0 +1,111 @@
+package gosimplifier
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// CSVConfig configures SimplifyCSV.
+type CSVConfig struct {
+	// Comma is the field delimiter, ',' if zero.
+	Comma rune
+}
+
+// SimplifyCSV reads CSV records from r, whose first record is the header, and writes them to w
+// with the rules applied to every row as to a map[string]string from the column names to the
+// cells, so the same rules scrub JSON documents and tabular exports. The columns the rules
+// remove by name, e.g. with remove_properties, are dropped from the header and every row, and
+// the cells of the other columns are written as the rules leave them, e.g. masked; a cell
+// removed on a row of its own, e.g. by remove_if, is written empty. Empty cells are left out of
+// the rows, as zero map values are removed anyway. Column names must be unique.
+func SimplifyCSV(s Simplifier, r io.Reader, w io.Writer, config CSVConfig) error {
+	reader := csv.NewReader(r)
+	writer := csv.NewWriter(w)
+	if config.Comma != 0 {
+		reader.Comma, writer.Comma = config.Comma, config.Comma
+	}
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	columns, err := csvColumns(s, header)
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(csvCells(columns, header, nil)); err != nil {
+		return err
+	}
+	// rows are parsed into the same map, cleared between rows, to spare an allocation per row
+	row := make(map[string]string, len(header))
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		clear(row)
+		for i, cell := range record {
+			if i < len(header) && cell != "" {
+				row[header[i]] = cell
+			}
+		}
+		simplified, err := s.Simplify(row)
+		if err != nil && !isPartial(err) {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := writer.Write(csvCells(columns, header, simplified.(map[string]string))); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvColumns returns the indexes of the columns the rules keep, applying them to a row holding
+// the column names to find the columns removed by name. Empty cells would not do, as zero map
+// values are dropped.
+func csvColumns(s Simplifier, header []string) ([]int, error) {
+	row := make(map[string]string, len(header))
+	for _, name := range header {
+		if _, ok := row[name]; ok {
+			return nil, fmt.Errorf("duplicate CSV column %q", name)
+		}
+		row[name] = name
+	}
+	_, removed, err := SimplifyWithAudit(s, row)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	dropped := make(map[string]bool, len(removed))
+	for _, path := range removed {
+		dropped[path] = true
+	}
+	columns := make([]int, 0, len(header))
+	for i, name := range header {
+		if !dropped[name] {
+			columns = append(columns, i)
+		}
+	}
+	return columns, nil
+}
+
+// csvCells returns the cells of the columns, taken from the simplified row, or from the header
+// itself if row is nil.
+func csvCells(columns []int, header []string, row map[string]string) []string {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		if row == nil {
+			cells[i] = header[column]
+		} else {
+			cells[i] = row[header[column]]
+		}
+	}
+	return cells
+}
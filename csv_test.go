@@ -0,0 +1,58 @@
+package gosimplifier
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSimplifyCSV(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "password", "internal_*" ],
+		"mask_properties": [ "email" ],
+		"remove_if": { "field": "note", "equals": "secret", "properties": [ "id" ] }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "id,email,password,internal_score,note\n" +
+		"1,alice@example.com,hunter2,0.5,hello\n" +
+		"2,bob@example.com,qwerty,0.7,\"a, b\"\n" +
+		"3,eve@example.com,,,secret\n"
+	var output bytes.Buffer
+	if err := SimplifyCSV(simplifier, strings.NewReader(input), &output, CSVConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "id,email,note\n" +
+		"1,****,hello\n" +
+		"2,****,\"a, b\"\n" +
+		",****,secret\n"
+	if output.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, output.String())
+	}
+}
+
+func TestSimplifyCSVComma(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "b" ] }`)
+	var output bytes.Buffer
+	if err := SimplifyCSV(simplifier, strings.NewReader("a;b;c\n1;2;3\n"), &output, CSVConfig{Comma: ';'}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "a;c\n1;3\n"; output.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, output.String())
+	}
+}
+
+func TestSimplifyCSVErrors(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "b" ] }`)
+	var output bytes.Buffer
+	if err := SimplifyCSV(simplifier, strings.NewReader(""), &output, CSVConfig{}); err != nil || output.Len() != 0 {
+		t.Errorf("Expected an empty input to give an empty output, got %q and %v", output.String(), err)
+	}
+	if err := SimplifyCSV(simplifier, strings.NewReader("a,a\n1,2\n"), &output, CSVConfig{}); err == nil || !strings.Contains(err.Error(), `duplicate CSV column "a"`) {
+		t.Errorf("Expected a duplicate column error, got %v", err)
+	}
+	if err := SimplifyCSV(simplifier, strings.NewReader("a,b\n1,2,3\n"), &output, CSVConfig{}); err == nil {
+		t.Error("Expected a row with too many cells to fail")
+	}
+}
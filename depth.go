@@ -0,0 +1,38 @@
+package gosimplifier
+
+// DepthPolicy decides what happens to the values nested deeper than allowed by WithMaxDepth.
+type DepthPolicy int
+
+const (
+	// KeepDeeper leaves the values below the maximum depth as they are in the original,
+	// without applying the rules to them.
+	KeepDeeper DepthPolicy = iota
+	// PruneDeeper removes the values below the maximum depth.
+	PruneDeeper
+)
+
+// WithMaxDepth stops the traversal after n levels below the root, guarding against
+// pathologically nested values such as customer supplied map[string]interface{} payloads.
+// The values at depth n are still simplified; the values below them are kept or removed
+// according to the policy.
+func WithMaxDepth(n int, policy DepthPolicy) Option {
+	return func(o *options) {
+		o.maxDepth = n
+		o.depthPolicy = policy
+	}
+}
+
+// depthMiddleware keeps or removes the nodes deeper than maxDepth, without walking into them.
+func depthMiddleware(maxDepth int, policy DepthPolicy) Middleware {
+	return func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Depth <= maxDepth {
+				return next(node)
+			}
+			if policy == PruneDeeper {
+				return removeRulerSingleton.apply(node)
+			}
+			return nil
+		}
+	}
+}
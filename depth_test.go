@@ -0,0 +1,54 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithMaxDepth(t *testing.T) {
+	nested := func() map[string]interface{} {
+		return map[string]interface{}{
+			"debug": "d",
+			"a": map[string]interface{}{
+				"debug": "d",
+				"b": map[string]interface{}{
+					"debug": "d",
+					"keep":  "k",
+				},
+			},
+		}
+	}
+	rules := `{ "remove_properties": [ "debug" ] }`
+
+	keeping, err := NewSimplifier(rules, WithMaxDepth(2, KeepDeeper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := keeping.Simplify(nested())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"debug": "d", "keep": "k"},
+		},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+
+	pruning, err := NewSimplifier(rules, WithMaxDepth(2, PruneDeeper))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = pruning.Simplify(nested())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]interface{}{
+		"a": map[string]interface{}{"b": map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
@@ -0,0 +1,122 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// ValuePattern describes sensitive string values by their content, see WithValueDetection.
+type ValuePattern struct {
+	// Name identifies the pattern, e.g. "email"
+	Name string
+	// Regexp matches the sensitive parts of a value
+	Regexp *regexp.Regexp
+	// Valid filters the matches, e.g. with a checksum, nil to redact every match
+	Valid func(match string) bool
+	// Replacement replaces the matches, DefaultMask if empty
+	Replacement string
+}
+
+// The patterns WithValueDetection detects by default.
+var (
+	EmailPattern = ValuePattern{
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	}
+	// CreditCardPattern matches card numbers of 13 to 19 digits, optionally grouped by spaces
+	// or dashes, that pass the Luhn check
+	CreditCardPattern = ValuePattern{
+		Name:   "credit_card",
+		Regexp: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Valid:  luhnValid,
+	}
+	JWTPattern = ValuePattern{
+		Name:   "jwt",
+		Regexp: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+)
+
+// WithValueDetection scans every string value the rules leave in place against the patterns,
+// EmailPattern, CreditCardPattern and JWTPattern if none are given, and replaces the matches
+// whatever the name of the property, catching sensitive data that slipped into free-form
+// fields such as comments or log messages:
+//
+//	simplifier, err := gosimplifier.NewSimplifier(rules, gosimplifier.WithValueDetection())
+//
+// Every value is visited, so the copy of Simplify shares nothing with the original. Map keys
+// are not scanned.
+func WithValueDetection(patterns ...ValuePattern) Option {
+	if len(patterns) == 0 {
+		patterns = []ValuePattern{EmailPattern, CreditCardPattern, JWTPattern}
+	}
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, detectMiddleware(patterns))
+	}
+}
+
+func detectMiddleware(patterns []ValuePattern) Middleware {
+	return func(next Walker) Walker {
+		return func(node *Node) error {
+			if err := next(node); err != nil || node.Removing() {
+				return err
+			}
+			value := node.Value
+			for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+				if value.IsNil() {
+					return nil
+				}
+				value = value.Elem()
+			}
+			if value.Kind() != reflect.String {
+				return nil
+			}
+			original := value.String()
+			redacted := original
+			for _, pattern := range patterns {
+				redacted = pattern.redact(redacted)
+			}
+			if redacted == original {
+				return nil
+			}
+			replacement := reflect.New(value.Type()).Elem()
+			replacement.SetString(redacted)
+			if !node.setIndirect(replacement) {
+				return node.walk.unexpected(node, ErrUnsettable)
+			}
+			return nil
+		}
+	}
+}
+
+func (p *ValuePattern) redact(value string) string {
+	replacement := p.Replacement
+	if replacement == "" {
+		replacement = DefaultMask
+	}
+	return p.Regexp.ReplaceAllStringFunc(value, func(match string) string {
+		if p.Valid != nil && !p.Valid(match) {
+			return match
+		}
+		return replacement
+	})
+}
+
+// luhnValid reports whether the digits of number pass the Luhn checksum of card numbers.
+func luhnValid(number string) bool {
+	sum, double := 0, false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
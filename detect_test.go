@@ -0,0 +1,73 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+type DetectExample struct {
+	Comment string
+	Note    *string
+	Tags    []string
+	Extra   map[string]interface{}
+	Debug   string
+}
+
+func TestValueDetection(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithValueDetection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := "token eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig"
+	original := DetectExample{
+		Comment: "contact me at jane.doe@example.com",
+		Note:    &note,
+		Tags:    []string{"card 4111 1111 1111 1111", "order 1234567890123"},
+		Extra:   map[string]interface{}{"msg": "mail bob@example.org", "n": 1},
+		Debug:   "alice@example.com",
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redactedNote := "token " + DefaultMask
+	expected := DetectExample{
+		Comment: "contact me at " + DefaultMask,
+		Note:    &redactedNote,
+		Tags:    []string{"card " + DefaultMask, "order 1234567890123"},
+		Extra:   map[string]interface{}{"msg": "mail " + DefaultMask, "n": 1},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if original.Comment != "contact me at jane.doe@example.com" || *original.Note != note {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Comment":"from jane@example.com","Debug":"d"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Comment":"from `+DefaultMask+`"}` {
+		t.Errorf("Expected the JSON values to be scanned, got %s", data)
+	}
+}
+
+type DetectName string
+
+func TestValueDetectionCustomPattern(t *testing.T) {
+	ssn := ValuePattern{Name: "ssn", Regexp: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), Replacement: "[SSN]"}
+	simplifier, err := NewSimplifier(`{}`, WithValueDetection(ssn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify([]DetectName{"ssn 123-45-6789", "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DetectName{"ssn [SSN]", "jane@example.com"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
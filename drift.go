@@ -0,0 +1,105 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Warning describes a potential problem with the rules at a path.
+type Warning struct {
+	// Path is the location of the problem, e.g. "Data.DataTest", or "" for the root.
+	Path    string
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Message
+	}
+	return w.Path + ": " + w.Message
+}
+
+// DriftDetector is a Simplifier reporting the fields its rules miss, see DetectDrift.
+type DriftDetector interface {
+	Simplifier
+	// DetectDrift reports the fields of t that no rule mentions.
+	DetectDrift(t reflect.Type) []Warning
+}
+
+// DetectDrift reports the fields of T that no rule of s mentions, so fields added to T after
+// the rules were written don't ship unscrubbed unnoticed. Fields named by a property_simplifiers
+// entry are followed into their own fields, as are the fields promoted from embedded structs;
+// other unmentioned struct fields are reported as a whole. Rules with keep_properties or
+// "remove_properties": ["*"] cover every field of the struct, and fields in except, named by a
+// globstar or matching a glob pattern count as mentioned. Simplifiers that are not a
+// DriftDetector are checked through their Rules, with the default options.
+func DetectDrift[T any](s Simplifier) []Warning {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if d, ok := s.(DriftDetector); ok {
+		return d.DetectDrift(t)
+	}
+	rule := Rules(s)
+	if rule == nil {
+		return []Warning{{Message: fmt.Sprintf("cannot read the rules of %T", s)}}
+	}
+	compiled, err := newRootSimplifier(rule, newOptions(nil))
+	if err != nil {
+		return []Warning{{Message: fmt.Sprintf("cannot compile the rules of %T: %v", s, err)}}
+	}
+	return compiled.DetectDrift(t)
+}
+
+// DetectDrift reports the fields of t that no rule mentions, see the DetectDrift function.
+func (s *simplifierImpl) DetectDrift(t reflect.Type) []Warning {
+	var warnings []Warning
+	s.detectDrift(t, "", s.options, map[reflect.Type]bool{}, &warnings)
+	return warnings
+}
+
+func (s *simplifierImpl) detectDrift(t reflect.Type, path string, o *options, inProgress map[reflect.Type]bool, warnings *[]Warning) {
+	t = elemStructType(t)
+	if t == nil || inProgress[t] {
+		return
+	}
+	inProgress[t] = true
+	defer delete(inProgress, t)
+
+	fieldNames := o.fieldNames(t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		fieldPath := joinRulePath(path, fieldNames[i])
+		_, r := s.propertyRuler(fieldNames[i])
+		switch r := r.(type) {
+		case nil:
+			switch {
+			case s.covers(fieldNames[i]):
+			case isEmbeddedStruct(field):
+				// the fields promoted from an embedded struct are matched against the rules of
+				// the struct embedding it
+				s.detectDrift(field.Type, path, o, inProgress, warnings)
+			case field.PkgPath == "":
+				*warnings = append(*warnings, Warning{
+					Path:    fieldPath,
+					Message: fmt.Sprintf("field of type %s is not mentioned by any rule", field.Type),
+				})
+			}
+		case *simplifierImpl:
+			r.detectDrift(field.Type, fieldPath, o, inProgress, warnings)
+		}
+	}
+}
+
+// covers reports whether the rule decides the fit of the property without naming it in a
+// section of its own: keep_properties and "*" remove every property they don't keep, except
+// keeps the properties it names, and glob patterns mention the properties they match. Globstar
+// names are already pushed into the sections of s.
+func (s *simplifierImpl) covers(name string) bool {
+	if len(s.rule.KeepProperties) > 0 || s.removesAll() || s.excepts(name) {
+		return true
+	}
+	_, r := s.keyRuler(name)
+	return r != nil
+}
@@ -0,0 +1,121 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectDrift(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug", "Test" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest" ]
+			}
+		}
+	}`)
+
+	warnings := DetectDrift[*ExampleStruct](simplifier)
+	expected := []Warning{
+		{Path: "Data.DataDebug", Message: "field of type int is not mentioned by any rule"},
+		{Path: "EntityList", Message: "field of type []gosimplifier.EntityStruct is not mentioned by any rule"},
+		{Path: "Nest", Message: "field of type gosimplifier.ExampleStruct0 is not mentioned by any rule"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected %v, got %v", expected, warnings)
+	}
+	if got := warnings[0].String(); got != "Data.DataDebug: field of type int is not mentioned by any rule" {
+		t.Errorf("Unexpected warning text %q", got)
+	}
+}
+
+func TestDetectDriftFullyCovered(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "DataTest", "DataDebug" ] }`)
+
+	if warnings := DetectDrift[DataStruct](simplifier); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
+
+func TestDetectDriftCoveredSections(t *testing.T) {
+	for name, rules := range map[string]string{
+		"keep_properties": `{ "keep_properties": [ "DataTest" ] }`,
+		"except":          `{ "remove_properties": [ "DataDebug" ], "except": [ "DataTest" ] }`,
+		"remove all":      `{ "remove_properties": [ "*" ] }`,
+		"globstar":        `{ "remove_properties": [ "**.DataDebug", "DataTest" ] }`,
+	} {
+		simplifier, err := NewSimplifier(rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if warnings := DetectDrift[DataStruct](simplifier); len(warnings) != 0 {
+			t.Errorf("%s: expected no warnings, got %v", name, warnings)
+		}
+	}
+
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "**.DataDebug", "Debug", "Test", "EntityList", "Nest" ],
+		"property_simplifiers": { "Data": { "remove_properties": [ "DataTest" ] } }
+	}`)
+	if warnings := DetectDrift[ExampleStruct](simplifier); len(warnings) != 0 {
+		t.Errorf("Expected the globstar to cover the nested field, got %v", warnings)
+	}
+}
+
+type DriftBase struct {
+	ID        int
+	CreatedAt time.Time
+}
+
+type DriftUser struct {
+	DriftBase
+	Name  string
+	Email string
+}
+
+func TestDetectDriftEmbedded(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "CreatedAt", "ID", "Email" ] }`)
+
+	warnings := DetectDrift[DriftUser](simplifier)
+	expected := []Warning{{Path: "Name", Message: "field of type string is not mentioned by any rule"}}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected the promoted fields to be mentioned, got %v", warnings)
+	}
+
+	simplifier, _ = NewSimplifier(`{ "remove_properties": [ "Name", "Email" ] }`)
+	warnings = DetectDrift[DriftUser](simplifier)
+	expected = []Warning{
+		{Path: "ID", Message: "field of type int is not mentioned by any rule"},
+		{Path: "CreatedAt", Message: "field of type time.Time is not mentioned by any rule"},
+	}
+	if !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected the unmentioned promoted fields, got %v", warnings)
+	}
+}
+
+func TestDetectDriftGlob(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Data*" ] }`)
+
+	if warnings := DetectDrift[DataStruct](simplifier); len(warnings) != 0 {
+		t.Errorf("Expected the glob to mention the fields it matches, got %v", warnings)
+	}
+}
+
+func TestDetectDriftWrapped(t *testing.T) {
+	debug, _ := NewSimplifier(`{ "remove_properties": [ "DataDebug" ] }`)
+	test, _ := NewSimplifier(`{ "remove_properties": [ "DataTest" ] }`)
+
+	if warnings := DetectDrift[DataStruct](Compose(debug, test)); len(warnings) != 0 {
+		t.Errorf("Expected the composed rules to mention every field, got %v", warnings)
+	}
+	expected := []Warning{{Path: "DataTest", Message: "field of type string is not mentioned by any rule"}}
+	if warnings := DetectDrift[DataStruct](&countingSimplifier{Simplifier: debug}); !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("Expected %v, got %v", expected, warnings)
+	}
+	warnings := DetectDrift[DataStruct](struct{ Simplifier }{debug})
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0].Message, "cannot read the rules") {
+		t.Errorf("Expected a warning for a simplifier without rules, got %v", warnings)
+	}
+}
@@ -0,0 +1,107 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type BaseModel struct {
+	ID        int
+	CreatedAt string
+	UpdatedAt string
+}
+
+type Audit struct {
+	Author string
+}
+
+type Article struct {
+	BaseModel
+	*Audit
+	Title string
+}
+
+func TestPromotedFields(t *testing.T) {
+	var rulePaths []string
+	record := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Removing() {
+				rulePaths = append(rulePaths, node.Path()+"="+node.RulePath())
+			}
+			return next(node)
+		}
+	}
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Articles": { "remove_properties": [ "CreatedAt", "Author" ] }
+		}
+	}`, WithMiddleware(record), WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Feed struct {
+		Articles []Article
+	}
+	original := Feed{Articles: []Article{{
+		BaseModel: BaseModel{ID: 1, CreatedAt: "c", UpdatedAt: "u"},
+		Audit:     &Audit{Author: "a"},
+		Title:     "t",
+	}}}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Feed{Articles: []Article{{BaseModel: BaseModel{ID: 1, UpdatedAt: "u"}, Audit: &Audit{}, Title: "t"}}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	expectedPaths := []string{
+		"Articles[0].BaseModel.CreatedAt=Articles.CreatedAt",
+		"Articles[0].Audit.Author=Articles.Author",
+	}
+	if !reflect.DeepEqual(rulePaths, expectedPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, rulePaths)
+	}
+	if original.Articles[0].CreatedAt != "c" {
+		t.Errorf("Expected the original to be unchanged")
+	}
+	if err := ValidateForType(simplifier, reflect.TypeOf(Feed{})); err != nil {
+		t.Errorf("Expected promoted fields to validate, got %v", err)
+	}
+}
+
+func TestEmbeddedStructRules(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"BaseModel": { "remove_properties": [ "ID" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(Article{BaseModel: BaseModel{ID: 1, CreatedAt: "c", UpdatedAt: "u"}, Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Article{BaseModel: BaseModel{CreatedAt: "c", UpdatedAt: "u"}, Title: "t"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the embedded type to be targeted by its name, got %+v", simplified)
+	}
+
+	simplifier, err = NewSimplifier(`{
+		"remove_if": { "field": "ID", "equals": 0, "properties": [ "UpdatedAt" ] }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = simplifier.Simplify(Article{BaseModel: BaseModel{CreatedAt: "c", UpdatedAt: "u"}, Title: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = Article{BaseModel: BaseModel{CreatedAt: "c"}, Title: "t"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected remove_if to see promoted fields, got %+v", simplified)
+	}
+}
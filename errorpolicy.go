@@ -0,0 +1,67 @@
+package gosimplifier
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsettable is the error of a value the rules change but that cannot be set, such as an
+	// element of an array held by a value that is not addressable.
+	ErrUnsettable = errors.New("value cannot be set")
+	// ErrUnsupportedKind is the error of a value of a kind the rules cannot apply to, such as
+	// rules for properties applied to an int.
+	ErrUnsupportedKind = errors.New("rules cannot apply to the kind of the value")
+)
+
+// ErrorPolicy decides how a simplifier handles the values the rules cannot be applied to, see
+// ErrUnsettable and ErrUnsupportedKind.
+type ErrorPolicy int
+
+const (
+	// Ignore leaves such values as they are, the default.
+	Ignore ErrorPolicy = iota
+	// FailFast fails the Simplify call on the first such value.
+	FailFast
+	// BestEffort removes such values and returns the partial result with a *PartialError, as
+	// WithBestEffort does, which it enables.
+	BestEffort
+)
+
+// WithErrorPolicy sets how the values the rules cannot be applied to are handled.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(o *options) {
+		o.errorPolicy = policy
+		if policy == BestEffort {
+			o.bestEffort = true
+		}
+	}
+}
+
+// WithOnError registers fn to be called for every value the rules cannot be applied to,
+// whatever the error policy, e.g. to count them while they are ignored. path is the location of
+// the value, e.g. "EntityList[3].SubProperties", and "" for the root. fn is called during the
+// Simplify call, from several goroutines with WithParallelism.
+func WithOnError(fn func(path string, err error)) Option {
+	return func(o *options) {
+		o.onError = append(o.onError, fn)
+	}
+}
+
+// unexpected handles err, preventing the rules from being applied to the node, according to the
+// error policy: it returns the error to fail the node, or nil to ignore it.
+func (w *walk) unexpected(node *Node, err error) error {
+	o := w.root.options
+	if o.errorPolicy == Ignore && len(o.onError) == 0 {
+		return nil
+	}
+	path := node.Path()
+	err = fmt.Errorf("%s: %w", displayPath(path), err)
+	for _, fn := range o.onError {
+		fn(path, err)
+	}
+	if o.errorPolicy == Ignore {
+		return nil
+	}
+	return err
+}
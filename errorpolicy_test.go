@@ -0,0 +1,57 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestErrorPolicy(t *testing.T) {
+	const rules = `{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": { "Test": { "remove_properties": [ "Unit" ] } }
+	}`
+	original := ExampleStruct0{Test: 1, Debug: "d", Data: DataStruct{DataTest: "t"}}
+
+	var paths []string
+	ignoring, err := NewSimplifier(rules, WithOnError(func(path string, err error) {
+		if !errors.Is(err, ErrUnsupportedKind) {
+			t.Errorf("Expected ErrUnsupportedKind, got %v", err)
+		}
+		paths = append(paths, path)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := ignoring.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, ExampleStruct0{Test: 1, Data: DataStruct{DataTest: "t"}}) {
+		t.Errorf("Expected the value to be kept, got %+v", simplified)
+	}
+	if !reflect.DeepEqual(paths, []string{"Test"}) {
+		t.Errorf("Expected the error to be reported for Test, got %v", paths)
+	}
+
+	failing, err := NewSimplifier(rules, WithErrorPolicy(FailFast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing.Simplify(original); !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("Expected ErrUnsupportedKind, got %v", err)
+	}
+
+	bestEffort, err := NewSimplifier(rules, WithErrorPolicy(BestEffort))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = bestEffort.Simplify(original)
+	var partial *PartialError
+	if !errors.As(err, &partial) || !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("Expected a *PartialError, got %v", err)
+	}
+	if !reflect.DeepEqual(simplified, ExampleStruct0{Data: DataStruct{DataTest: "t"}}) {
+		t.Errorf("Expected the value to be removed, got %+v", simplified)
+	}
+}
@@ -0,0 +1,35 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrInvalidRulesJSON is the error of rules that are not valid JSON, or do not decode into a
+// Rule, e.g. a remove_properties that is not a list. The decoding error is wrapped as well.
+var ErrInvalidRulesJSON = errors.New("invalid rules JSON")
+
+// decodeRules decodes the JSON rules into v, failing with ErrInvalidRulesJSON.
+func decodeRules(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRulesJSON, err)
+	}
+	return nil
+}
+
+// ErrUnknownProperty is the error of a rule naming properties the struct it is applied to does
+// not have, see WithStrictFields.
+type ErrUnknownProperty struct {
+	// Path is the location of the rule in the rule tree, "" for the root rule
+	Path string
+	// Properties are the unknown properties, sorted
+	Properties []string
+	// Type is the struct type the rule is applied to
+	Type reflect.Type
+}
+
+func (e *ErrUnknownProperty) Error() string {
+	return fmt.Sprintf("rule %q names unknown properties %q of %s", e.Path, e.Properties, e.Type)
+}
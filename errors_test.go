@@ -0,0 +1,49 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestErrInvalidRulesJSON(t *testing.T) {
+	base, err := NewSimplifier(`{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, fn := range map[string]func() error{
+		"NewSimplifier": func() error {
+			_, err := NewSimplifier(`{ "remove_properties": "Debug" }`)
+			return err
+		},
+		"ExtendSimplifier": func() error {
+			_, err := ExtendSimplifier(base, `{`)
+			return err
+		},
+		"Register": func() error {
+			return NewRegistry().Register("broken", `[]`)
+		},
+	} {
+		err := fn()
+		if !errors.Is(err, ErrInvalidRulesJSON) {
+			t.Errorf("%s: expected ErrInvalidRulesJSON, got %v", name, err)
+		}
+	}
+}
+
+func TestErrUnknownProperty(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": { "Data": { "remove_properties": [ "DataTset" ] } }
+	}`, WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = simplifier.Simplify(ExampleStruct0{})
+	var unknown *ErrUnknownProperty
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Expected an *ErrUnknownProperty, got %v", err)
+	}
+	if unknown.Path != "Data" || !reflect.DeepEqual(unknown.Properties, []string{"DataTset"}) || unknown.Type != reflect.TypeOf(DataStruct{}) {
+		t.Errorf("Unexpected error %+v", unknown)
+	}
+}
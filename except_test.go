@@ -0,0 +1,140 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type ExceptExample struct {
+	Data *ExceptData
+	Name string
+}
+
+type ExceptData struct {
+	ID     int
+	Secret string
+	Info   SubStruct
+	Labels map[string]string
+}
+
+func TestExcept(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "*" ],
+				"except": [ "ID", "Labels" ],
+				"property_simplifiers": {
+					"Info": { "remove_properties": [ "Debug" ] },
+					"Labels": { "remove_properties": [ "*" ], "except": [ "env" ] }
+				}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := ExceptExample{
+		Data: &ExceptData{
+			ID:     1,
+			Secret: "s",
+			Info:   SubStruct{Test: "t", Debug: "d"},
+			Labels: map[string]string{"env": "prod", "owner": "me"},
+		},
+		Name: "n",
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ExceptExample{
+		Data: &ExceptData{ID: 1, Info: SubStruct{Test: "t"}, Labels: map[string]string{"env": "prod"}},
+		Name: "n",
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if original.Data.Secret != "s" || len(original.Data.Labels) != 2 {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original.Data)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Data":{"ID":1,"Secret":"s","Labels":{"env":"prod","owner":"me"}},"Name":"n"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Data":{"ID":1,"Labels":{"env":"prod"}},"Name":"n"}` {
+		t.Errorf("Expected the JSON to keep the excepted properties, got %s", data)
+	}
+}
+
+func TestExceptRoot(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "*", "Secret" ], "except": [ "Data" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := ExceptExample{
+		Data: &ExceptData{ID: 1, Secret: "s", Info: SubStruct{Test: "t"}, Labels: map[string]string{"env": "prod"}},
+		Name: "n",
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ExceptExample{
+		Data: &ExceptData{ID: 1, Info: SubStruct{Test: "t"}, Labels: map[string]string{"env": "prod"}},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the excepted property to be kept with its contents, got %+v", simplified)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Data":{"ID":1,"Secret":"s","Labels":{"env":"prod"}},"Name":"n"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Data":{"ID":1,"Labels":{"env":"prod"}}}` {
+		t.Errorf("Expected the JSON to keep the contents of the excepted property, got %s", data)
+	}
+}
+
+func TestExceptGlob(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "x-*" ], "except": [ "x-request-id" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(map[string]string{"x-trace": "t", "x-request-id": "r", "host": "h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"x-request-id": "r", "host": "h"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
+
+func TestExceptCaseInsensitive(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "*" ], "except": [ "id" ] }`, WithCaseInsensitiveMatch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(ExceptData{ID: 1, Secret: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, ExceptData{ID: 1}) {
+		t.Errorf("Expected the excepted field to be kept, got %+v", simplified)
+	}
+}
+
+func TestValidateExcept(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "*" ], "except": [ "ID", "Unknown" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ValidateForType(simplifier, reflect.TypeOf(ExceptData{}))
+	if err == nil || !strings.Contains(err.Error(), "Unknown: except names unknown property") {
+		t.Errorf("Expected a warning for the unknown excepted property, got %v", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "glob patterns") {
+		t.Errorf("Expected no glob warning for removing every field, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package gosimplifier
+
+import "fmt"
+
+// WithRegistry sets the registry the rule sets named by "extends" are looked up in, instead of
+// the package registry of RegisterRules.
+func WithRegistry(registry *Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+func (o *options) registryOrDefault() *Registry {
+	if o.registry != nil {
+		return o.registry
+	}
+	return defaultRegistry
+}
+
+// resolveExtends returns the rule with the rule sets its "extends" sections name, at any
+// depth, merged beneath it, so the rule overrides the settings of the rule sets it extends:
+//
+//	{
+//	  "extends": [ "base_audit", "pii_v1" ],
+//	  "remove_properties": [ "Debug" ]
+//	}
+//
+// Registered rule sets are resolved when they are registered, so extending is not recursive.
+// Names no rule set is registered under are looked up in the presets, see PresetRule.
+func (r *Registry) resolveExtends(rule *Rule) (*Rule, error) {
+	if !hasExtends(rule) {
+		return rule, nil
+	}
+	resolved := *rule
+	resolved.Extends = nil
+	if len(rule.PropertySimplifiers) > 0 {
+		resolved.PropertySimplifiers = make(map[string]*Rule, len(rule.PropertySimplifiers))
+		for name, sub := range rule.PropertySimplifiers {
+			resolvedSub, err := r.resolveExtends(sub)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			resolved.PropertySimplifiers[name] = resolvedSub
+		}
+	}
+	merged := &Rule{}
+	for _, name := range rule.Extends {
+		base, ok := r.rule(name)
+		if !ok {
+			base, ok = presetRule(name)
+		}
+		if !ok {
+			return nil, fmt.Errorf("extends unknown rules %s", name)
+		}
+		merged = mergeRules(merged, base)
+	}
+	return mergeRules(merged, &resolved), nil
+}
+
+// hasExtends reports whether the rule or one of its sub-rules extends rule sets.
+func hasExtends(rule *Rule) bool {
+	if rule == nil {
+		return false
+	}
+	if len(rule.Extends) > 0 {
+		return true
+	}
+	for _, sub := range rule.PropertySimplifiers {
+		if hasExtends(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// rule returns the resolved rule registered under name.
+func (r *Registry) rule(name string) (*Rule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.rule, true
+}
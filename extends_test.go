@@ -0,0 +1,59 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtends(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("base_audit", `{ "remove_properties": [ "Debug" ] }`); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("pii_v1", `{
+		"property_simplifiers": { "Data": { "remove_properties": [ "DataTest" ] } }
+	}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("public", `{
+		"extends": [ "base_audit" ],
+		"property_simplifiers": {
+			"EntityList": { "extends": [ "pii_v1" ], "property_simplifiers": { "SubProperties": { "remove_properties": [ "ABC" ] } } }
+		}
+	}`); err != nil {
+		t.Fatal(err)
+	}
+
+	simplifier, err := NewSimplifier(`{
+		"extends": [ "public", "pii_v1" ],
+		"remove_properties": [ "Test" ]
+	}`, WithRegistry(registry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := ExampleStruct0{
+		Test:       1,
+		Debug:      "d",
+		Data:       DataStruct{DataTest: "t", DataDebug: 2},
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{ABC: "a", DEF: "d"}}},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ExampleStruct0{
+		Data:       DataStruct{DataDebug: 2},
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{DEF: "d"}}},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	if _, err := NewSimplifier(`{ "extends": [ "missing" ] }`, WithRegistry(registry)); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected an error naming the unknown rules, got %v", err)
+	}
+	if err := registry.Register("broken", `{ "property_simplifiers": { "Data": { "extends": [ "missing" ] } } }`); err == nil {
+		t.Error("Expected an error registering rules extending unknown rules")
+	}
+}
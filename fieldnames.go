@@ -0,0 +1,108 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// WithJSONFieldNames matches the property names of the rules against the names struct fields
+// have in JSON, taken from their `json` tags, instead of their Go names, so rules can be written
+// against the wire format:
+//
+//	type Data struct {
+//		DataTest string `json:"data_test"`
+//	}
+//
+//	{ "remove_properties": [ "data_test" ] }
+//
+// Fields without a json tag keep their Go name, as in encoding/json. Node paths use the JSON
+// names as well.
+func WithJSONFieldNames() Option {
+	return func(o *options) {
+		o.jsonFieldNames = true
+	}
+}
+
+// jsonNames caches the JSON names of the fields of struct types.
+var jsonNames sync.Map
+
+// fieldNames returns the names rules use for the fields of the struct type t.
+func (o *options) fieldNames(t reflect.Type) []string {
+	if o == nil || !o.jsonFieldNames {
+		return goFieldNames(t)
+	}
+	if names, ok := jsonNames.Load(t); ok {
+		return names.([]string)
+	}
+	names := make([]string, t.NumField())
+	for i := range names {
+		field := t.Field(i)
+		names[i] = field.Name
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			names[i] = name
+		}
+	}
+	jsonNames.Store(t, names)
+	return names
+}
+
+// goFieldNames caches the Go names of the fields of struct types, so the traversal does not
+// allocate a reflect.StructField per field.
+var goNames sync.Map
+
+func goFieldNames(t reflect.Type) []string {
+	if names, ok := goNames.Load(t); ok {
+		return names.([]string)
+	}
+	names := make([]string, t.NumField())
+	for i := range names {
+		names[i] = t.Field(i).Name
+	}
+	goNames.Store(t, names)
+	return names
+}
+
+// fieldByName returns the field of the struct type t the rules name name, see WithJSONFieldNames,
+// including the fields promoted from embedded structs. The Index of a promoted field is the
+// index sequence to reach it from t.
+func (o *options) fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	return o.promotedField(t, name, map[reflect.Type]bool{})
+}
+
+func (o *options) promotedField(t reflect.Type, name string, visited map[reflect.Type]bool) (reflect.StructField, bool) {
+	visited[t] = true
+	for i, fieldName := range o.fieldNames(t) {
+		if o.sameName(fieldName, name) {
+			return t.Field(i), true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		embedded := t.Field(i)
+		if !isEmbeddedStruct(embedded) {
+			continue
+		}
+		embeddedType := embedded.Type
+		if embeddedType.Kind() == reflect.Ptr {
+			embeddedType = embeddedType.Elem()
+		}
+		if visited[embeddedType] {
+			continue
+		}
+		if field, ok := o.promotedField(embeddedType, name, visited); ok {
+			field.Index = append([]int{i}, field.Index...)
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// isEmbeddedStruct reports whether the field is an embedded struct or pointer to a struct, whose
+// fields are promoted to the struct holding it.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return field.Anonymous && t.Kind() == reflect.Struct
+}
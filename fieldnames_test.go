@@ -0,0 +1,70 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type WireData struct {
+	DataTest  string `json:"data_test"`
+	DataDebug int    `json:"data_debug,omitempty"`
+	Plain     string
+	Hidden    string `json:"-"`
+	Secret    string `json:"secret" simplify:"remove"`
+}
+
+type WireExample struct {
+	Data  WireData `json:"data"`
+	Debug string   `json:"debug"`
+}
+
+func TestWithJSONFieldNames(t *testing.T) {
+	var paths []string
+	record := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Removing() {
+				paths = append(paths, node.Path())
+			}
+			return next(node)
+		}
+	}
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "debug" ],
+		"property_simplifiers": {
+			"data": { "remove_properties": [ "data_test", "DataDebug", "Plain" ] }
+		}
+	}`, WithJSONFieldNames(), WithMiddleware(record))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := WireExample{Data: WireData{DataTest: "t", DataDebug: 1, Plain: "p", Hidden: "h"}, Debug: "d"}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := WireExample{Data: WireData{DataDebug: 1, Hidden: "h"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if expectedPaths := []string{"data.data_test", "data.Plain", "debug"}; !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, paths)
+	}
+
+	err = ValidateForType(simplifier, reflect.TypeOf(WireExample{}))
+	validationErr, ok := err.(*ValidationError)
+	if !ok || !reflect.DeepEqual(validationErr.Problems, []string{"data.DataDebug: unknown property of gosimplifier.WireData"}) {
+		t.Errorf("Expected the Go name to be reported, got %v", err)
+	}
+}
+
+func TestNewSimplifierFromTypeWithJSONFieldNames(t *testing.T) {
+	simplifier, err := NewSimplifierFromType(reflect.TypeOf(WireExample{}), WithJSONFieldNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, _ := SimplifyJSON(simplifier, []byte(`{"data":{"secret":"s","data_test":"t"}}`))
+	if string(simplified) != `{"data":{"data_test":"t"}}` {
+		t.Errorf("Unexpected JSON %s", simplified)
+	}
+}
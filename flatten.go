@@ -0,0 +1,63 @@
+package gosimplifier
+
+import "sort"
+
+// FlattenRule lifts the properties of the value its rule applies to into the value holding it,
+// in the output of SimplifyToMap and SimplifyJSON, reshaping nested internal models into flat
+// public views. Simplify and the other methods returning Go values leave their shape as it is.
+//
+// Example, turning {"Name": ..., "Address": {"City": ...}} into {"Name": ..., "address_City": ...}:
+//
+//	{
+//	  "property_simplifiers": {
+//	    "Address": { "remove_properties": [ "Street" ], "flatten": { "prefix": "address_" } }
+//	  }
+//	}
+//
+// Only the properties the rules keep are lifted. The own properties of the holding value take
+// precedence over lifted properties of the same name, and lifted properties over each other in
+// the order of their names. Values that are not objects once converted, e.g. lists, are left
+// in place.
+type FlattenRule struct {
+	// Prefix is prepended to the names of the lifted properties
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// liftedProperty holds the properties of a flattened property, to be lifted into its parent.
+type liftedProperty struct {
+	name       string
+	prefix     string
+	properties map[string]interface{}
+}
+
+// lift adds the lifted properties to object, in the order of the names of the flattened
+// properties, without replacing those object already has.
+func lift(object map[string]interface{}, lifted []liftedProperty) {
+	sort.SliceStable(lifted, func(i, j int) bool {
+		return lifted[i].name < lifted[j].name
+	})
+	for _, l := range lifted {
+		for _, key := range sortedKeys(l.properties) {
+			if _, ok := object[l.prefix+key]; !ok {
+				object[l.prefix+key] = l.properties[key]
+			}
+		}
+	}
+}
+
+// flattens reports whether the rule or any of its nested rules has a flatten directive.
+func (s *simplifierImpl) flattens(visited map[*simplifierImpl]bool) bool {
+	if visited[s] {
+		return false
+	}
+	visited[s] = true
+	if s.rule.Flatten != nil {
+		return true
+	}
+	for _, r := range s.propertySimplifiers {
+		if sub, ok := r.(*simplifierImpl); ok && sub.flattens(visited) {
+			return true
+		}
+	}
+	return false
+}
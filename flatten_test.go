@@ -0,0 +1,81 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type FlattenAddress struct {
+	Street string
+	City   string
+}
+
+type FlattenCustomer struct {
+	Name    string
+	City    string
+	Address FlattenAddress
+	Billing *FlattenAddress
+	Tags    []string
+}
+
+func TestFlatten(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Address": { "remove_properties": [ "Street" ], "flatten": {} },
+			"Billing": { "flatten": { "prefix": "billing_" } },
+			"Tags": { "flatten": {} }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := FlattenCustomer{
+		Name:    "n",
+		City:    "own",
+		Address: FlattenAddress{Street: "s", City: "lifted"},
+		Billing: &FlattenAddress{Street: "b", City: "c"},
+		Tags:    []string{"t"},
+	}
+	result, err := SimplifyToMap(simplifier, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"Name":           "n",
+		"City":           "own",
+		"billing_Street": "b",
+		"billing_City":   "c",
+		"Tags":           []interface{}{"t"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.(FlattenCustomer).Address.City != "lifted" {
+		t.Errorf("Expected Simplify to keep the shape of the value, got %+v", simplified)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Name":"n","Address":{"Street":"s","City":"c","Geo":{"Lat":1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"City":"c","Geo":{"Lat":1},"Name":"n"}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	scrubber, err := NewSimplifier(`{ "remove_properties": [ "Name" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = SimplifyToMap(Compose(scrubber, simplifier), original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["billing_City"]; !ok || result["Name"] != nil {
+		t.Errorf("Expected the last simplifier to flatten the composed result, got %v", result)
+	}
+}
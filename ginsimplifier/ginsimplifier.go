@@ -0,0 +1,194 @@
+// Package ginsimplifier connects gosimplifier to Gin, simplifying the JSON responses of
+// handlers with the rules bound to their routes:
+//
+//	router.GET("/users/:id", ginsimplifier.Rules("public_user"), func(c *gin.Context) {
+//		ginsimplifier.JSON(c, http.StatusOK, user)
+//	})
+package ginsimplifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xhinliang/gosimplifier"
+)
+
+// simplifierKey is the key of the Simplifier bound to the request in the gin.Context.
+const simplifierKey = "gosimplifier.simplifier"
+
+// Selector returns the Simplifier of the response to a request, or nil to leave it as it is.
+type Selector func(c *gin.Context) gosimplifier.Simplifier
+
+// ByRoute selects the Simplifier by the route matching the request, either with its method,
+// e.g. "GET /users/:id", or without it, e.g. "/users/:id". Requests matching no listed route
+// use the Simplifier of "", if any.
+func ByRoute(simplifiers map[string]gosimplifier.Simplifier) Selector {
+	return func(c *gin.Context) gosimplifier.Simplifier {
+		if s, ok := simplifiers[c.Request.Method+" "+c.FullPath()]; ok {
+			return s
+		}
+		if s, ok := simplifiers[c.FullPath()]; ok {
+			return s
+		}
+		return simplifiers[""]
+	}
+}
+
+// Rules returns the middleware of Middleware binding the rules registered under name in the
+// package registry of gosimplifier to the routes it is added to. It panics if no rules are
+// registered under name, so a typo fails when the routes are set up.
+func Rules(name string) gin.HandlerFunc {
+	s, ok := gosimplifier.Get(name)
+	if !ok {
+		panic(fmt.Sprintf("ginsimplifier: no rules registered under %q", name))
+	}
+	return Middleware(func(*gin.Context) gosimplifier.Simplifier {
+		return s
+	})
+}
+
+// Middleware returns a middleware binding the Simplifier selected for every request to it, see
+// JSON, and applying it to the JSON response body, with a Content-Type of application/json or a
+// +json suffix, so internal fields never leave the service even if a handler renders an entity
+// with c.JSON. Other responses are written through as they are. When the middleware is used
+// both globally and on a route, the Simplifier selected by the innermost one applies.
+//
+// JSON responses are buffered until the handlers return. A body that cannot be simplified is not
+// written: the client gets a 500 Internal Server Error instead.
+func Middleware(selector Selector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s := selector(c)
+		if s == nil {
+			c.Next()
+			return
+		}
+		c.Set(simplifierKey, s)
+		if w, ok := c.Writer.(*responseWriter); ok {
+			w.simplifier = s
+			c.Next()
+			return
+		}
+		w := &responseWriter{ResponseWriter: c.Writer, simplifier: s}
+		c.Writer = w
+		c.Next()
+		w.finish()
+		c.Writer = w.ResponseWriter
+	}
+}
+
+// Simplifier returns the Simplifier bound to the request by Middleware, or nil if there is none.
+func Simplifier(c *gin.Context) gosimplifier.Simplifier {
+	s, _ := c.Get(simplifierKey)
+	simplifier, _ := s.(gosimplifier.Simplifier)
+	return simplifier
+}
+
+// JSON renders the copy of obj simplified by the Simplifier bound to the request as JSON, as
+// c.JSON does, or obj itself if no Simplifier is bound. The response is not simplified again by
+// the middleware. An obj that cannot be simplified aborts the request with a 500 Internal Server
+// Error, and the error is added to c.Errors.
+func JSON(c *gin.Context, code int, obj interface{}) {
+	if s := Simplifier(c); s != nil {
+		simplified, err := gosimplifier.SimplifyContext(c.Request.Context(), s, obj)
+		var partial *gosimplifier.PartialError
+		if err != nil && !errors.As(err, &partial) {
+			c.Error(err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		obj = simplified
+	}
+	if w, ok := c.Writer.(*responseWriter); ok {
+		w.simplified = true
+	}
+	c.JSON(code, obj)
+}
+
+// responseWriter buffers the body of a JSON response, and writes any other response through.
+type responseWriter struct {
+	gin.ResponseWriter
+	simplifier gosimplifier.Simplifier
+	// simplified is set by JSON, whose response is written through
+	simplified bool
+	decided    bool
+	buffering  bool
+	body       bytes.Buffer
+}
+
+// decide decides whether the response is buffered, once its headers are final.
+func (w *responseWriter) decide() {
+	if !w.decided {
+		w.decided = true
+		w.buffering = !w.simplified && isJSON(w.Header().Get("Content-Type"))
+	}
+}
+
+func (w *responseWriter) WriteHeaderNow() {
+	if w.decide(); !w.buffering {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if w.decide(); w.buffering {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	if w.decide(); w.buffering {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseWriter) Written() bool {
+	return w.decided || w.ResponseWriter.Written()
+}
+
+func (w *responseWriter) Size() int {
+	if w.buffering {
+		return w.body.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+// Flush flushes the responses written through; a buffered JSON response is only written once
+// the handlers return.
+func (w *responseWriter) Flush() {
+	if !w.buffering {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// finish writes the simplified JSON response.
+func (w *responseWriter) finish() {
+	if !w.buffering {
+		return
+	}
+	body := w.body.Bytes()
+	if len(bytes.TrimSpace(body)) > 0 {
+		simplified, err := gosimplifier.SimplifyJSON(w.simplifier, body)
+		var partial *gosimplifier.PartialError
+		if err != nil && !errors.As(err, &partial) {
+			w.Header().Del("Content-Length")
+			http.Error(w.ResponseWriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		body = simplified
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.Write(body)
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
@@ -0,0 +1,124 @@
+package ginsimplifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xhinliang/gosimplifier"
+)
+
+type user struct {
+	ID           int
+	Name         string
+	PasswordHash string
+	Token        string
+}
+
+var alice = user{ID: 1, Name: "alice", PasswordHash: "h", Token: "t"}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	gosimplifier.MustRegisterRules("ginsimplifier_test_public_user", `{ "remove_properties": [ "PasswordHash", "Token" ] }`)
+}
+
+func serve(router *gin.Engine, method string, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+	return recorder
+}
+
+func TestRules(t *testing.T) {
+	router := gin.New()
+	router.GET("/helper", Rules("ginsimplifier_test_public_user"), func(c *gin.Context) {
+		JSON(c, http.StatusCreated, alice)
+	})
+	router.GET("/forgotten", Rules("ginsimplifier_test_public_user"), func(c *gin.Context) {
+		c.JSON(http.StatusOK, alice)
+	})
+	router.GET("/text", Rules("ginsimplifier_test_public_user"), func(c *gin.Context) {
+		c.String(http.StatusOK, `{"Token":"t"}`)
+	})
+	router.GET("/raw", func(c *gin.Context) {
+		JSON(c, http.StatusOK, alice)
+	})
+
+	for path, expected := range map[string]string{
+		"/helper":    `{"ID":1,"Name":"alice","PasswordHash":"","Token":""}`,
+		"/forgotten": `{"ID":1,"Name":"alice"}`,
+		"/text":      `{"Token":"t"}`,
+		"/raw":       `{"ID":1,"Name":"alice","PasswordHash":"h","Token":"t"}`,
+	} {
+		recorder := serve(router, http.MethodGet, path)
+		if recorder.Body.String() != expected {
+			t.Errorf("%s: expected %s, got %s", path, expected, recorder.Body.String())
+		}
+	}
+	if recorder := serve(router, http.MethodGet, "/helper"); recorder.Code != http.StatusCreated {
+		t.Errorf("Expected the status of JSON to be kept, got %d", recorder.Code)
+	}
+}
+
+func TestRulesUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Rules to panic for unknown rules")
+		}
+	}()
+	Rules("ginsimplifier_test_unknown")
+}
+
+func TestByRoute(t *testing.T) {
+	removeToken, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Token" ] }`)
+	removeHash, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "PasswordHash" ] }`)
+	removeName, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Name" ] }`)
+	router := gin.New()
+	router.Use(Middleware(ByRoute(map[string]gosimplifier.Simplifier{
+		"GET /users/:id": removeToken,
+		"/users/:id":     removeHash,
+		"":               removeName,
+	})))
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, alice)
+	}
+	router.GET("/users/:id", handler)
+	router.POST("/users/:id", handler)
+	router.GET("/other", handler)
+	router.GET("/override", Middleware(func(*gin.Context) gosimplifier.Simplifier { return removeToken }), handler)
+
+	for request, expected := range map[[2]string]string{
+		{http.MethodGet, "/users/1"}:  `{"ID":1,"Name":"alice","PasswordHash":"h"}`,
+		{http.MethodPost, "/users/1"}: `{"ID":1,"Name":"alice","Token":"t"}`,
+		{http.MethodGet, "/other"}:    `{"ID":1,"PasswordHash":"h","Token":"t"}`,
+		{http.MethodGet, "/override"}: `{"ID":1,"Name":"alice","PasswordHash":"h"}`,
+	} {
+		recorder := serve(router, request[0], request[1])
+		if recorder.Body.String() != expected {
+			t.Errorf("%s: expected %s, got %s", request, expected, recorder.Body.String())
+		}
+	}
+}
+
+func TestMiddlewareError(t *testing.T) {
+	failing, _ := gosimplifier.NewSimplifier(`{}`, gosimplifier.WithMiddleware(func(next gosimplifier.Walker) gosimplifier.Walker {
+		return func(node *gosimplifier.Node) error {
+			return context.Canceled
+		}
+	}))
+	router := gin.New()
+	bind := Middleware(func(*gin.Context) gosimplifier.Simplifier { return failing })
+	router.GET("/forgotten", bind, func(c *gin.Context) {
+		c.JSON(http.StatusOK, alice)
+	})
+	router.GET("/helper", bind, func(c *gin.Context) {
+		JSON(c, http.StatusOK, alice)
+	})
+	for _, path := range []string{"/forgotten", "/helper"} {
+		recorder := serve(router, http.MethodGet, path)
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("%s: expected a 500 Internal Server Error, got %d %s", path, recorder.Code, recorder.Body.String())
+		}
+	}
+}
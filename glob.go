@@ -0,0 +1,111 @@
+package gosimplifier
+
+import (
+	"sort"
+	"strings"
+)
+
+// globRule applies a ruler to the map keys matching a glob pattern.
+type globRule struct {
+	pattern string
+	ruler   ruler
+}
+
+// isGlob reports whether a property name is a glob pattern. In patterns, '*' matches any
+// sequence of characters and '?' any single character.
+func isGlob(name string) bool {
+	return strings.ContainsAny(name, "*?") && !isIndexSelector(name)
+}
+
+// newGlobRules collects the glob patterns among the property rulers. Longer patterns, being
+// more specific, are tried first; patterns of equal length in lexical order.
+func newGlobRules(propertySimplifiers map[string]ruler) []*globRule {
+	var rules []*globRule
+	for name, r := range propertySimplifiers {
+		if isGlob(name) {
+			rules = append(rules, &globRule{pattern: name, ruler: r})
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if len(rules[i].pattern) != len(rules[j].pattern) {
+			return len(rules[i].pattern) > len(rules[j].pattern)
+		}
+		return rules[i].pattern < rules[j].pattern
+	})
+	return rules
+}
+
+// matchGlob reports whether name matches the glob pattern.
+func matchGlob(pattern string, name string) bool {
+	// star is the position in pattern after the last '*', and retry the position in name it
+	// was last tried at
+	star, retry := -1, 0
+	p, n := 0, 0
+	for n < len(name) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == name[n]):
+			p++
+			n++
+		case p < len(pattern) && pattern[p] == '*':
+			p++
+			star, retry = p, n
+		case star >= 0:
+			retry++
+			p, n = star, retry
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// keyRuler returns the name of the rule matching a map key and its ruler: the rule named by the
+// key itself, or else the first glob pattern matching it, unless the key is excepted from them.
+// It returns nil if no rule matches.
+func (s *simplifierImpl) keyRuler(key string) (string, ruler) {
+	if name, r := s.propertyRuler(key); r != nil {
+		return name, r
+	}
+	if s.excepts(key) {
+		return "", nil
+	}
+	for _, rule := range s.globRules {
+		if matchGlob(rule.pattern, key) || s.folded != nil && matchGlob(strings.ToLower(rule.pattern), strings.ToLower(key)) {
+			return rule.pattern, rule.ruler
+		}
+	}
+	return "", nil
+}
+
+// removesAll reports whether the rule removes every property with "remove_properties": ["*"],
+// which unlike other glob patterns matches struct fields too.
+func (s *simplifierImpl) removesAll() bool {
+	return s.propertySimplifiers["*"] == removeRulerSingleton
+}
+
+// excepts reports whether the property is exempt from the glob patterns of the rule.
+func (s *simplifierImpl) excepts(name string) bool {
+	for _, except := range s.rule.Except {
+		if except == name || s.folded != nil && strings.EqualFold(except, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleName returns the name of the rule of s that selected r for the property name, which is a
+// glob pattern for map keys matched by one, and whether there is such a rule.
+func (s *simplifierImpl) ruleName(name string, r ruler) (string, bool) {
+	if s.propertySimplifiers[name] == r {
+		return name, true
+	}
+	if len(s.globRules) > 0 || s.folded != nil {
+		if ruleName, keyRuler := s.keyRuler(name); keyRuler == r {
+			return ruleName, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,86 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, name string
+		matched       bool
+	}{
+		{"x-internal-*", "x-internal-id", true},
+		{"x-internal-*", "x-internal-", true},
+		{"x-internal-*", "x-public-id", false},
+		{"*-token", "access-token", true},
+		{"*token*", "my-token-id", true},
+		{"a?c", "abc", true},
+		{"a?c", "ac", false},
+		{"*a*b", "xaybzb", true},
+		{"*a*b", "xaybzc", false},
+		{"**", "", true},
+	} {
+		if matched := matchGlob(c.pattern, c.name); matched != c.matched {
+			t.Errorf("matchGlob(%q, %q): expected %v", c.pattern, c.name, c.matched)
+		}
+	}
+}
+
+func TestGlobRules(t *testing.T) {
+	var rulePaths []string
+	record := func(next Walker) Walker {
+		return func(node *Node) error {
+			if rulePath := node.RulePath(); rulePath != "" {
+				rulePaths = append(rulePaths, node.Path()+"="+rulePath)
+			}
+			return next(node)
+		}
+	}
+	rules := `{
+		"remove_properties": [ "x-internal-*" ],
+		"mask_properties": [ "x-internal-trace-*" ],
+		"property_simplifiers": {
+			"meta-*": { "remove_properties": [ "secret" ] }
+		}
+	}`
+	simplifier, err := NewSimplifier(rules, WithMiddleware(record))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := map[string]interface{}{
+		"x-internal-id":       "1",
+		"x-internal-trace-id": "abc",
+		"x-request-id":        "2",
+		"meta-user":           map[string]interface{}{"secret": "s", "name": "n"},
+	}
+	simplified, err := simplifier.Simplify(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"x-internal-trace-id": DefaultMask,
+		"x-request-id":        "2",
+		"meta-user":           map[string]interface{}{"name": "n"},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	expectedPaths := []string{"meta-user.secret=meta-*.secret", "x-internal-id=x-internal-*", "x-internal-trace-id=x-internal-trace-*"}
+	if !reflect.DeepEqual(rulePaths, expectedPaths) {
+		t.Errorf("Expected %v, got %v", expectedPaths, rulePaths)
+	}
+
+	streamed, _ := NewSimplifier(`{ "remove_properties": [ "x-internal-*" ] }`)
+	output, err := SimplifyJSON(streamed, []byte(`{"x-internal-id":1,"x-request-id":2}`))
+	if err != nil || string(output) != `{"x-request-id":2}` {
+		t.Errorf("Unexpected JSON %s, %v", output, err)
+	}
+
+	err = ValidateForType(streamed, reflect.TypeOf(ExampleStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "glob patterns only match map keys") {
+		t.Errorf("Expected the glob on a struct to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// globstarPrefix marks the names of the root rule that match a property at any depth, e.g.
+// "**.Password" in remove_properties, so a single entry keeps the property out of the result
+// wherever it is nested, including below properties other rules name.
+const globstarPrefix = "**."
+
+// splitGlobstars returns the globstar names of the sections of rule, without their prefix, as a
+// rule of their own, and a copy of rule without them, or nil and rule itself if it has none.
+// The sections are found by reflection like in expandIndexedNames, so every []string or map
+// section naming properties is covered; sub-rules cannot be named by a globstar.
+func splitGlobstars(rule *Rule) (*Rule, *Rule) {
+	var globstars *Rule
+	stripped := *rule
+	strippedValue := reflect.ValueOf(&stripped).Elem()
+	for i := 0; i < strippedValue.NumField(); i++ {
+		section := strippedValue.Field(i)
+		switch {
+		case section.Kind() == reflect.Slice && section.Type().Elem().Kind() == reflect.String:
+			var kept, found []string
+			for _, name := range section.Interface().([]string) {
+				if property, ok := strings.CutPrefix(name, globstarPrefix); ok {
+					found = append(found, property)
+				} else {
+					kept = append(kept, name)
+				}
+			}
+			if len(found) == 0 {
+				continue
+			}
+			if globstars == nil {
+				globstars = &Rule{}
+			}
+			reflect.ValueOf(globstars).Elem().Field(i).Set(reflect.ValueOf(found))
+			section.Set(reflect.ValueOf(kept))
+		case section.Kind() == reflect.Map && section.Type().Key().Kind() == reflect.String &&
+			section.Type().Elem() != reflect.TypeOf((*Rule)(nil)):
+			var kept, found reflect.Value
+			for _, key := range section.MapKeys() {
+				property, ok := strings.CutPrefix(key.String(), globstarPrefix)
+				if !ok {
+					continue
+				}
+				if !found.IsValid() {
+					found = reflect.MakeMap(section.Type())
+					kept = reflect.MakeMap(section.Type())
+					for _, k := range section.MapKeys() {
+						kept.SetMapIndex(k, section.MapIndex(k))
+					}
+				}
+				found.SetMapIndex(reflect.ValueOf(property), section.MapIndex(key))
+				kept.SetMapIndex(key, reflect.Value{})
+			}
+			if !found.IsValid() {
+				continue
+			}
+			if globstars == nil {
+				globstars = &Rule{}
+			}
+			reflect.ValueOf(globstars).Elem().Field(i).Set(found)
+			section.Set(kept)
+		}
+	}
+	if globstars == nil {
+		return nil, rule
+	}
+	return globstars, &stripped
+}
+
+// pushGlobstars returns a copy of rule with the sections of globstars merged into it and into
+// every rule nested in it, at path in the rule tree.
+func pushGlobstars(rule *Rule, globstars *Rule, path string) (*Rule, error) {
+	if rule == nil {
+		rule = &Rule{}
+	}
+	if found, _ := splitGlobstars(rule); found != nil {
+		return nil, fmt.Errorf("%s: globstar names are only supported in the root rule", path)
+	}
+	pushed := mergeRules(rule, globstars)
+	var err error
+	for _, name := range sortedKeys(pushed.PropertySimplifiers) {
+		sub := pushed.PropertySimplifiers[name]
+		if pushed.PropertySimplifiers[name], err = pushGlobstars(sub, globstars, joinRulePath(path, name)); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedKeys(pushed.TypeSimplifiers) {
+		sub := pushed.TypeSimplifiers[name]
+		if pushed.TypeSimplifiers[name], err = pushGlobstars(sub, globstars, "type_simplifiers "+name); err != nil {
+			return nil, err
+		}
+	}
+	return pushed, nil
+}
+
+// expandGlobstars returns the root rule with its globstar names pushed into every rule of the
+// tree, or rule itself if it has none.
+func expandGlobstars(rule *Rule) (*Rule, error) {
+	globstars, stripped := splitGlobstars(rule)
+	if globstars == nil {
+		return rule, checkGlobstars(rule, "")
+	}
+	return pushGlobstars(stripped, globstars, "")
+}
+
+// globstarNames returns the set of the property names in the sections of globstars, as split
+// by splitGlobstars, or nil if globstars is nil.
+func globstarNames(globstars *Rule) map[string]bool {
+	if globstars == nil {
+		return nil
+	}
+	names := make(map[string]bool)
+	sections := reflect.ValueOf(globstars).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		switch {
+		case section.Kind() == reflect.Slice && section.Type().Elem().Kind() == reflect.String:
+			for j := 0; j < section.Len(); j++ {
+				names[section.Index(j).String()] = true
+			}
+		case section.Kind() == reflect.Map && section.Type().Key().Kind() == reflect.String:
+			for _, key := range section.MapKeys() {
+				names[key.String()] = true
+			}
+		}
+	}
+	return names
+}
+
+// fullRule returns the rule of the root simplifier s with its globstar names, so they are
+// pushed into the rules merged onto it as well.
+func (s *simplifierImpl) fullRule() *Rule {
+	if s.globstars == nil {
+		return s.rule
+	}
+	return mergeRules(s.rule, s.globstars)
+}
+
+// checkGlobstars returns an error if a rule nested in rule, at path in the rule tree, has
+// globstar names.
+func checkGlobstars(rule *Rule, path string) error {
+	for _, name := range sortedKeys(rule.PropertySimplifiers) {
+		sub := rule.PropertySimplifiers[name]
+		if sub == nil {
+			continue
+		}
+		if found, _ := splitGlobstars(sub); found != nil {
+			return fmt.Errorf("%s: globstar names are only supported in the root rule", joinRulePath(path, name))
+		}
+		if err := checkGlobstars(sub, joinRulePath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type GlobstarAccount struct {
+	Name     string
+	Password string
+	Owner    *GlobstarAccount
+	Extra    map[string]interface{}
+}
+
+func TestGlobstar(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "**.Password" ],
+		"mask_properties": [ "**.Token" ],
+		"property_simplifiers": {
+			"Owner": { "remove_properties": [ "Name" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := GlobstarAccount{
+		Name:     "n",
+		Password: "p",
+		Owner: &GlobstarAccount{
+			Name:     "o",
+			Password: "q",
+			Extra:    map[string]interface{}{"Password": "r", "Token": "secret", "Nested": map[string]interface{}{"Password": "s"}},
+		},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := GlobstarAccount{
+		Name: "n",
+		Owner: &GlobstarAccount{
+			Extra: map[string]interface{}{"Token": DefaultMask, "Nested": map[string]interface{}{}},
+		},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Owner":{"Name":"o","Password":"q","Owner":{"Password":"r"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Owner":{"Owner":{}}}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	rules := Rules(simplifier)
+	if !contains(rules.RemoveProperties, "**.Password") || !contains(rules.PropertySimplifiers["Owner"].RemoveProperties, "Password") {
+		t.Errorf("Expected the rules to keep the globstar names, got %+v", rules)
+	}
+	extended, err := ExtendSimplifier(simplifier, `{ "property_simplifiers": { "Extra": { "remove_properties": [ "Name" ] } } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = extended.Simplify(GlobstarAccount{Extra: map[string]interface{}{"Password": "p"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := simplified.(GlobstarAccount).Extra["Password"]; ok {
+		t.Errorf("Expected the globstar names to apply to extended rules, got %+v", simplified)
+	}
+
+	_, err = NewSimplifier(`{ "property_simplifiers": { "Owner": { "remove_properties": [ "**.Password" ] } } }`)
+	if err == nil || !strings.Contains(err.Error(), "Owner: globstar names are only supported in the root rule") {
+		t.Errorf("Expected a nested globstar to be rejected, got %v", err)
+	}
+}
+
+type GlobstarUser struct {
+	Password string
+	Profile  GlobstarProfile
+}
+
+type GlobstarProfile struct {
+	Bio string
+}
+
+func TestGlobstarStrictFields(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "**.Password" ],
+		"property_simplifiers": { "Profile": { "remove_properties": [ "Bio" ] } }
+	}`, WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(GlobstarUser{Password: "p", Profile: GlobstarProfile{Bio: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified != (GlobstarUser{}) {
+		t.Errorf("Unexpected result %+v", simplified)
+	}
+	if err := ValidateForType(simplifier, reflect.TypeOf(GlobstarUser{})); err != nil {
+		t.Errorf("Expected the globstar names to pass validation, got %v", err)
+	}
+}
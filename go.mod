@@ -1,3 +1,7 @@
 module github.com/xhinliang/gosimplifier
 
-go 1.17
+go 1.23.0
+
+require google.golang.org/protobuf v1.36.9
+
+require github.com/google/go-cmp v0.7.0 // indirect
@@ -0,0 +1,15 @@
+module github.com/xhinliang/gosimplifier/gormsimplifier
+
+go 1.23.0
+
+require (
+	github.com/xhinliang/gosimplifier v0.1.0
+	gorm.io/gorm v1.31.1
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.26.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
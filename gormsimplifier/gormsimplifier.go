@@ -0,0 +1,95 @@
+// Package gormsimplifier connects gosimplifier to GORM, so raw database entities do not leak
+// internal columns through the ORM: the results of queries in a Public scope are simplified in
+// place, and columns of a Serializer are stored as the JSON of simplified values.
+package gormsimplifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/xhinliang/gosimplifier"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// simplifierKey is the setting holding the Simplifier of a Public scope.
+const simplifierKey = "gosimplifier:simplifier"
+
+// Plugin is the gorm.Plugin applying the Simplifier of Public scopes to the query results:
+//
+//	db.Use(gormsimplifier.Plugin{})
+type Plugin struct{}
+
+// Name returns the name of the plugin.
+func (Plugin) Name() string {
+	return "gosimplifier"
+}
+
+// Initialize registers the callback simplifying the query results after they are scanned.
+func (Plugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().After("gorm:after_query").Register("gosimplifier:simplify", simplifyResult)
+}
+
+// Public returns a scope in which the results of queries are simplified by s, e.g. for the
+// queries serving a public API:
+//
+//	db.Scopes(gormsimplifier.Public(publicUser)).Find(&users)
+//
+// The results are simplified in place, so the destination only ever holds the simplified
+// entities. A destination that cannot be simplified fails the query. Public requires the Plugin.
+func Public(s gosimplifier.Simplifier) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Set(simplifierKey, s)
+	}
+}
+
+// simplifyResult simplifies the destination of a query in a Public scope.
+func simplifyResult(db *gorm.DB) {
+	value, ok := db.Get(simplifierKey)
+	if !ok || db.Error != nil || db.Statement.Dest == nil {
+		return
+	}
+	s := value.(gosimplifier.Simplifier)
+	if reflect.ValueOf(db.Statement.Dest).Kind() != reflect.Ptr {
+		db.AddError(fmt.Errorf("gosimplifier: the destination of a Public query must be a pointer, got %T", db.Statement.Dest))
+		return
+	}
+	err := gosimplifier.SimplifyInPlace(s, db.Statement.Dest)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		db.AddError(fmt.Errorf("gosimplifier: %w", err))
+	}
+}
+
+// Serializer is the GORM serializer storing values as the JSON of their copies simplified by
+// the Simplifier, e.g. to keep the payloads of an audit log free of secrets. Register it under
+// a name used by the serializer tag of the columns:
+//
+//	schema.RegisterSerializer("public_json", gormsimplifier.Serializer{Simplifier: s})
+//
+//	type AuditEntry struct {
+//		ID   uint
+//		User User `gorm:"serializer:public_json"`
+//	}
+//
+// Values are read back as with the json serializer of GORM.
+type Serializer struct {
+	Simplifier gosimplifier.Simplifier
+}
+
+// Scan reads the JSON of the column into the field.
+func (s Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	return schema.JSONSerializer{}.Scan(ctx, field, dst, dbValue)
+}
+
+// Value returns the JSON of the simplified copy of the field value.
+func (s Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	simplified, err := gosimplifier.SimplifyContext(ctx, s.Simplifier, fieldValue)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, fmt.Errorf("gosimplifier: %s: %w", field.Name, err)
+	}
+	return schema.JSONSerializer{}.Value(ctx, field, dst, simplified)
+}
@@ -0,0 +1,129 @@
+package gormsimplifier
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type User struct {
+	ID           uint
+	Name         string
+	PasswordHash string
+}
+
+// fakeDialector opens a database whose queries return the users of the test, without a driver.
+type fakeDialector struct {
+	users []User
+}
+
+func (d fakeDialector) Name() string {
+	return "fake"
+}
+
+func (d fakeDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return db.Callback().Query().Replace("gorm:query", func(db *gorm.DB) {
+		dest := reflect.ValueOf(db.Statement.Dest).Elem()
+		if dest.Kind() == reflect.Slice {
+			dest.Set(reflect.ValueOf(append([]User(nil), d.users...)))
+		} else {
+			dest.Set(reflect.ValueOf(d.users[0]))
+		}
+	})
+}
+
+func (d fakeDialector) Migrator(*gorm.DB) gorm.Migrator                             { return nil }
+func (d fakeDialector) DataTypeOf(*schema.Field) string                             { return "" }
+func (d fakeDialector) DefaultValueOf(*schema.Field) clause.Expression              { return nil }
+func (d fakeDialector) BindVarTo(w clause.Writer, _ *gorm.Statement, _ interface{}) { w.WriteByte('?') }
+func (d fakeDialector) QuoteTo(w clause.Writer, s string)                           { w.WriteString(s) }
+func (d fakeDialector) Explain(sql string, _ ...interface{}) string                 { return sql }
+
+func TestPublic(t *testing.T) {
+	db, err := gorm.Open(fakeDialector{users: []User{{ID: 1, Name: "alice", PasswordHash: "h1"}, {ID: 2, Name: "bob", PasswordHash: "h2"}}}, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(Plugin{}); err != nil {
+		t.Fatal(err)
+	}
+	public, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "PasswordHash" ] }`)
+
+	var users []User
+	if err := db.Scopes(Public(public)).Find(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].PasswordHash != "" || users[1].PasswordHash != "" || users[1].Name != "bob" {
+		t.Errorf("Expected the users to be simplified, got %+v", users)
+	}
+
+	var user User
+	if err := db.Scopes(Public(public)).First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "alice" || user.PasswordHash != "" {
+		t.Errorf("Expected the user to be simplified, got %+v", user)
+	}
+
+	var raw []User
+	if err := db.Find(&raw).Error; err != nil {
+		t.Fatal(err)
+	}
+	if raw[0].PasswordHash != "h1" {
+		t.Errorf("Expected queries outside the scope to be left as they are, got %+v", raw)
+	}
+}
+
+func TestPublicError(t *testing.T) {
+	db, _ := gorm.Open(fakeDialector{users: []User{{ID: 1}}}, &gorm.Config{})
+	db.Use(Plugin{})
+	failing, _ := gosimplifier.NewSimplifier(`{}`, gosimplifier.WithMiddleware(func(next gosimplifier.Walker) gosimplifier.Walker {
+		return func(node *gosimplifier.Node) error {
+			return context.Canceled
+		}
+	}))
+	var users []User
+	if err := db.Scopes(Public(failing)).Find(&users).Error; err == nil || !strings.Contains(err.Error(), "gosimplifier") {
+		t.Errorf("Expected the query to fail, got %v", err)
+	}
+}
+
+type AuditEntry struct {
+	ID   uint
+	User User `gorm:"serializer:test_public_json"`
+}
+
+func TestSerializer(t *testing.T) {
+	public, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "PasswordHash" ] }`)
+	schema.RegisterSerializer("test_public_json", Serializer{Simplifier: public})
+	entrySchema, err := schema.Parse(&AuditEntry{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := entrySchema.LookUpField("User")
+	entry := AuditEntry{ID: 1, User: User{ID: 7, Name: "alice", PasswordHash: "h"}}
+	value, err := field.Serializer.Value(context.Background(), field, reflect.ValueOf(&entry).Elem(), entry.User)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"ID":7,"Name":"alice","PasswordHash":""}`; value != expected {
+		t.Errorf("Expected %s, got %v", expected, value)
+	}
+
+	var scanned AuditEntry
+	if err := field.Serializer.Scan(context.Background(), field, reflect.ValueOf(&scanned).Elem(), value); err != nil {
+		t.Fatal(err)
+	}
+	if scanned.User.Name != "alice" {
+		t.Errorf("Expected the user to be read back, got %+v", scanned.User)
+	}
+}
@@ -0,0 +1,187 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// RuleFromSelection returns the rule keeping exactly the fields of a GraphQL selection set, so
+// a REST response built from the same models as a GraphQL API can be trimmed to what the client
+// asked for. The selection is either a bare selection set, "{ id name friends { id } }", or an
+// operation, "query User($id: ID!) { user(id: $id) { id } }".
+//
+// Arguments, directives and variable definitions are ignored, aliased fields select the field
+// they alias, and the fields of fragments spread inline ("... on User { id }") are merged into
+// the enclosing selection. Named fragment spreads cannot be resolved and are an error. GraphQL
+// names are usually the JSON names of the model fields, see WithJSONFieldNames.
+func RuleFromSelection(selection string) (*Rule, error) {
+	p := &selectionParser{input: selection}
+	p.skipOperation()
+	if !p.consume('{') {
+		return nil, p.errorf("expected '{'")
+	}
+	rule, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipIgnored(); p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q after the selection set", p.input[p.pos])
+	}
+	return rule, nil
+}
+
+// selectionParser parses the subset of the GraphQL grammar RuleFromSelection needs.
+type selectionParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("selection at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// skipIgnored skips white space, commas and comments, which GraphQL ignores.
+func (p *selectionParser) skipIgnored() {
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == ',' || unicode.IsSpace(rune(c)):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume skips c, reporting whether it is the next token.
+func (p *selectionParser) consume(c byte) bool {
+	p.skipIgnored()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *selectionParser) name() string {
+	p.skipIgnored()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c != '_' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !(p.pos > start && '0' <= c && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// skipOperation skips the operation type, name and variable definitions before the selection set.
+func (p *selectionParser) skipOperation() {
+	p.skipIgnored()
+	for p.pos < len(p.input) && p.input[p.pos] != '{' {
+		if p.input[p.pos] == '(' {
+			p.skipGroup('(', ')')
+			continue
+		}
+		p.pos++
+	}
+}
+
+// skipGroup skips the balanced group starting at the current position, such as arguments,
+// leaving strings alone.
+func (p *selectionParser) skipGroup(open byte, close byte) {
+	depth := 0
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '"':
+			p.pos++
+			for p.pos < len(p.input) && p.input[p.pos] != '"' {
+				if p.input[p.pos] == '\\' {
+					p.pos++
+				}
+				p.pos++
+			}
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				p.pos++
+				return
+			}
+		}
+		p.pos++
+	}
+}
+
+// skipDirectives skips the arguments and directives following a name.
+func (p *selectionParser) skipDirectives() {
+	for {
+		p.skipIgnored()
+		if p.pos >= len(p.input) {
+			return
+		}
+		switch p.input[p.pos] {
+		case '(':
+			p.skipGroup('(', ')')
+		case '@':
+			p.pos++
+			p.name()
+		default:
+			return
+		}
+	}
+}
+
+// selectionSet parses the selections up to the closing brace, the opening one being consumed.
+func (p *selectionParser) selectionSet() (*Rule, error) {
+	rule := &Rule{}
+	for !p.consume('}') {
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("unterminated selection set")
+		}
+		if strings.HasPrefix(p.input[p.pos:], "...") {
+			p.pos += len("...")
+			if typeCondition := p.name(); typeCondition != "on" && typeCondition != "" {
+				return nil, p.errorf("named fragment %s cannot be resolved", typeCondition)
+			} else if typeCondition == "on" {
+				p.name()
+			}
+			p.skipDirectives()
+			if !p.consume('{') {
+				return nil, p.errorf("expected the selection set of the inline fragment")
+			}
+			fragment, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			rule = mergeRules(rule, fragment)
+			continue
+		}
+		name := p.name()
+		if name == "" {
+			return nil, p.errorf("unexpected %q", p.input[p.pos])
+		}
+		if p.consume(':') {
+			if name = p.name(); name == "" {
+				return nil, p.errorf("expected the field of alias")
+			}
+		}
+		p.skipDirectives()
+		field := &Rule{KeepProperties: []string{name}}
+		if p.consume('{') {
+			sub, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			field.PropertySimplifiers = map[string]*Rule{name: sub}
+		}
+		rule = mergeRules(rule, field)
+	}
+	return rule, nil
+}
@@ -0,0 +1,54 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type GraphQLUser struct {
+	ID      string         `json:"id"`
+	Name    string         `json:"name"`
+	Email   string         `json:"email"`
+	Friends []*GraphQLUser `json:"friends"`
+}
+
+func TestRuleFromSelection(t *testing.T) {
+	rule, err := RuleFromSelection(`
+		query User($id: ID!) {
+			# the profile
+			id, name: name @include(if: true)
+			friends(first: "{10}") { id ... on User { name } }
+		}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rule.KeepProperties, []string{"id", "name", "friends"}) {
+		t.Errorf("Expected the selected fields to be kept, got %v", rule.KeepProperties)
+	}
+	if friends := rule.PropertySimplifiers["friends"]; friends == nil || !reflect.DeepEqual(friends.KeepProperties, []string{"id", "name"}) {
+		t.Errorf("Expected the selection of friends to be kept, got %+v", friends)
+	}
+
+	simplifier, err := NewSimplifierByRule(rule, WithJSONFieldNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := GraphQLUser{ID: "1", Name: "a", Email: "a@example.com",
+		Friends: []*GraphQLUser{{ID: "2", Name: "b", Email: "b@example.com", Friends: []*GraphQLUser{{ID: "3"}}}}}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := GraphQLUser{ID: "1", Name: "a", Friends: []*GraphQLUser{{ID: "2", Name: "b"}}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+}
+
+func TestRuleFromSelectionErrors(t *testing.T) {
+	for _, selection := range []string{"", "{ id", "{ id } }", "{ ...UserFields }", "{ a: }"} {
+		if _, err := RuleFromSelection(selection); err == nil {
+			t.Errorf("Expected an error for %q", selection)
+		}
+	}
+}
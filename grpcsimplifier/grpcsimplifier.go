@@ -0,0 +1,81 @@
+// Package grpcsimplifier connects gosimplifier to gRPC servers, simplifying the response
+// messages of the RPC methods centrally in interceptors.
+package grpcsimplifier
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xhinliang/gosimplifier"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Selector returns the Simplifier of the responses of a method, given its full name such as
+// "/package.Service/Method", or nil to leave them as they are.
+type Selector func(fullMethod string) gosimplifier.Simplifier
+
+// ByMethod selects the Simplifier by full method name. Methods not listed use the Simplifier
+// of "", if any.
+func ByMethod(simplifiers map[string]gosimplifier.Simplifier) Selector {
+	return func(fullMethod string) gosimplifier.Simplifier {
+		if s, ok := simplifiers[fullMethod]; ok {
+			return s
+		}
+		return simplifiers[""]
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor applying the Simplifier selected for the method
+// to the response. A response that cannot be simplified is not sent: the call fails with
+// codes.Internal instead.
+func UnaryServerInterceptor(selector Selector) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+		s := selector(info.FullMethod)
+		if s == nil {
+			return resp, nil
+		}
+		return simplify(ctx, s, resp)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor applying the Simplifier selected for the method
+// to every message the server sends, see UnaryServerInterceptor.
+func StreamServerInterceptor(selector Selector) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		s := selector(info.FullMethod)
+		if s == nil {
+			return handler(srv, stream)
+		}
+		return handler(srv, &serverStream{ServerStream: stream, simplifier: s})
+	}
+}
+
+// serverStream simplifies the messages sent on the stream.
+type serverStream struct {
+	grpc.ServerStream
+	simplifier gosimplifier.Simplifier
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	simplified, err := simplify(s.Context(), s.simplifier, m)
+	if err != nil {
+		return err
+	}
+	return s.ServerStream.SendMsg(simplified)
+}
+
+// simplify returns the simplified copy of the message.
+func simplify(ctx context.Context, s gosimplifier.Simplifier, m interface{}) (interface{}, error) {
+	simplified, err := gosimplifier.SimplifyContext(ctx, s, m)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, status.Error(codes.Internal, "the response could not be simplified")
+	}
+	return simplified, nil
+}
@@ -0,0 +1,92 @@
+package grpcsimplifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func newSelector(t *testing.T) Selector {
+	removeName, err := gosimplifier.NewSimplifier(`{ "property_simplifiers": { "Fields": { "remove_properties": [ "JsonName" ] } } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing, err := gosimplifier.NewSimplifier(`{ "redact_properties": [ "name" ] }`,
+		gosimplifier.WithVault(func(token string, value interface{}) error { return errors.New("unavailable") }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ByMethod(map[string]gosimplifier.Simplifier{
+		"/test.Types/Get":  removeName,
+		"/test.Types/Fail": failing,
+	})
+}
+
+func newType() *typepb.Type {
+	return &typepb.Type{Name: "T", Fields: []*typepb.Field{{Name: "f", JsonName: "jsonF"}}}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(newSelector(t))
+	original := newType()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return original, nil
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Types/Get"}, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &typepb.Type{Name: "T", Fields: []*typepb.Field{{Name: "f"}}}
+	if !proto.Equal(resp.(proto.Message), expected) {
+		t.Errorf("Expected %v, got %v", expected, resp)
+	}
+	if !proto.Equal(original, newType()) {
+		t.Error("Expected the response of the handler to be unchanged")
+	}
+
+	resp, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Types/List"}, handler)
+	if err != nil || resp != original {
+		t.Errorf("Expected methods without a Simplifier to be left alone, got %v, %v", resp, err)
+	}
+
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Types/Fail"}, handler)
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Expected codes.Internal, got %v", err)
+	}
+}
+
+type recordingStream struct {
+	grpc.ServerStream
+	sent []interface{}
+}
+
+func (s *recordingStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *recordingStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(newSelector(t))
+	stream := &recordingStream{}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.SendMsg(newType())
+	}
+	if err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.Types/Get"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 1 || stream.sent[0].(*typepb.Type).Fields[0].JsonName != "" {
+		t.Errorf("Expected the sent message to be simplified, got %v", stream.sent)
+	}
+}
@@ -0,0 +1,78 @@
+package gosimplifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// hashRuler replaces values with a stable hash, so analytics can still join on them.
+type hashRuler struct {
+}
+
+var hashRulerSingleton = &hashRuler{}
+
+// WithHashSalt keys the hashes of hash_properties with salt, using HMAC-SHA256 instead of plain
+// SHA-256, so the hashes of guessable values such as emails cannot be looked up.
+func WithHashSalt(salt []byte) Option {
+	return func(o *options) {
+		o.hashSalt = salt
+	}
+}
+
+// hash returns the hex encoded SHA-256, or HMAC-SHA256 with a salt, of value.
+func (o *options) hash(value []byte) string {
+	if o.hashSalt == nil {
+		sum := sha256.Sum256(value)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, o.hashSalt)
+	mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashInput returns the bytes hashed for value: strings are hashed as they are, other scalars
+// in their fmt representation and anything else as JSON.
+func hashInput(value reflect.Value) ([]byte, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return []byte(value.String()), nil
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return json.Marshal(value.Interface())
+	}
+	return []byte(fmt.Sprint(value.Interface())), nil
+}
+
+// apply replaces the node with its hash. Empty strings stay empty so they do not join, and
+// values that cannot hold the hash are removed.
+func (r *hashRuler) apply(node *Node) error {
+	value := indirect(node.Value)
+	if !value.IsValid() || node.Parent.Kind() == reflect.Invalid || !value.CanInterface() ||
+		value.Kind() == reflect.String && value.Len() == 0 {
+		return nil
+	}
+	input, err := hashInput(value)
+	if err != nil {
+		return fmt.Errorf("hash %s: %w", node.Path(), err)
+	}
+	hashed := node.walk.root.options.hash(input)
+	if value.Kind() == reflect.String && value.CanSet() {
+		value.SetString(hashed)
+		return nil
+	}
+	replacement := reflect.ValueOf(hashed)
+	if node.Value.Kind() == reflect.String {
+		replacement = replacement.Convert(node.Value.Type())
+	}
+	if !node.set(replacement) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *hashRuler) action() string {
+	return "hash"
+}
@@ -0,0 +1,48 @@
+package gosimplifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+type Signup struct {
+	Email  string
+	UserID int
+	Ref    interface{}
+	Empty  string
+}
+
+func TestHashProperties(t *testing.T) {
+	rules := `{ "hash_properties": [ "Email", "UserID", "Ref", "Empty" ] }`
+	original := Signup{Email: "john@example.com", UserID: 42, Ref: 42}
+
+	simplifier, _ := NewSimplifier(rules)
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signup := simplified.(Signup)
+	sum := sha256.Sum256([]byte("john@example.com"))
+	if signup.Email != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected hash %q", signup.Email)
+	}
+	sum = sha256.Sum256([]byte("42"))
+	if signup.Ref != hex.EncodeToString(sum[:]) || signup.UserID != 0 || signup.Empty != "" {
+		t.Errorf("Unexpected result %+v", signup)
+	}
+
+	again, _ := simplifier.Simplify(original)
+	if again.(Signup).Email != signup.Email {
+		t.Error("Expected the hash to be stable")
+	}
+
+	salted, _ := NewSimplifier(rules, WithHashSalt([]byte("salt")))
+	simplified, _ = salted.Simplify(original)
+	mac := hmac.New(sha256.New, []byte("salt"))
+	mac.Write([]byte("john@example.com"))
+	if simplified.(Signup).Email != hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("Unexpected salted hash %q", simplified.(Signup).Email)
+	}
+}
@@ -0,0 +1,66 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"time"
+)
+
+// CallInfo describes a finished simplification, see WithAfterSimplify.
+type CallInfo struct {
+	// Duration is the time spent applying the rules, after the value was copied
+	Duration time.Duration
+	// Err is the error of the call, nil if it succeeded
+	Err error
+}
+
+// WithBeforeSimplify registers fn to be called before the rules are applied to a value, e.g.
+// to sample the values passing through. v is the value the rules are applied to: the copy made
+// by Simplify, the pointer given to SimplifyInPlace, or a pointer to the decoded document of
+// SimplifyJSON. fn is called from several goroutines when the simplifier is used concurrently,
+// and must not keep v.
+func WithBeforeSimplify(fn func(v interface{})) Option {
+	return func(o *options) {
+		o.beforeHooks = append(o.beforeHooks, fn)
+	}
+}
+
+// WithAfterSimplify registers fn to be called once the rules were applied to a value, with the
+// simplified value, see WithBeforeSimplify, and the duration and error of the call, e.g. to
+// record timings:
+//
+//	gosimplifier.WithAfterSimplify(func(v interface{}, info gosimplifier.CallInfo) {
+//		latency.Observe(info.Duration.Seconds())
+//	})
+func WithAfterSimplify(fn func(v interface{}, info CallInfo)) Option {
+	return func(o *options) {
+		o.afterHooks = append(o.afterHooks, fn)
+	}
+}
+
+// hasHooks reports whether hooks are registered, which need the values to be decoded.
+func (o *options) hasHooks() bool {
+	return len(o.beforeHooks) > 0 || len(o.afterHooks) > 0
+}
+
+// beforeSimplify calls the hooks registered with WithBeforeSimplify, and returns the start time
+// of the call if hooks are to be called after it.
+func (o *options) beforeSimplify(value reflect.Value) time.Time {
+	for _, fn := range o.beforeHooks {
+		fn(value.Interface())
+	}
+	if len(o.afterHooks) == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// afterSimplify calls the hooks registered with WithAfterSimplify.
+func (o *options) afterSimplify(value reflect.Value, start time.Time, err error) {
+	if len(o.afterHooks) == 0 {
+		return
+	}
+	info := CallInfo{Duration: time.Since(start), Err: err}
+	for _, fn := range o.afterHooks {
+		fn(value.Interface(), info)
+	}
+}
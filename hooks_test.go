@@ -0,0 +1,67 @@
+package gosimplifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	var before, after []interface{}
+	var infos []CallInfo
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`,
+		WithBeforeSimplify(func(v interface{}) {
+			before = append(before, *v.(*SubStruct))
+		}),
+		WithAfterSimplify(func(v interface{}, info CallInfo) {
+			after = append(after, *v.(*SubStruct))
+			infos = append(infos, info)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := simplifier.Simplify(&SubStruct{Test: "t", Debug: "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(before) != 1 || before[0] != (SubStruct{Test: "t", Debug: "d"}) {
+		t.Errorf("Expected the hook to see the value before the rules, got %v", before)
+	}
+	if len(after) != 1 || after[0] != (SubStruct{Test: "t"}) {
+		t.Errorf("Expected the hook to see the simplified value, got %v", after)
+	}
+	if len(infos) != 1 || infos[0].Err != nil || infos[0].Duration < 0 {
+		t.Errorf("Expected the call info, got %+v", infos)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SimplifyContext(ctx, simplifier, &SubStruct{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the call to be cancelled, got %v", err)
+	}
+	if len(before) != 1 {
+		t.Errorf("Expected no hook for a call cancelled before it started, got %v", before)
+	}
+}
+
+func TestHooksJSON(t *testing.T) {
+	calls := 0
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`,
+		WithAfterSimplify(func(v interface{}, info CallInfo) {
+			calls++
+			if _, ok := v.(*interface{}); !ok {
+				t.Errorf("Expected a pointer to the decoded document, got %T", v)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := SimplifyJSON(simplifier, []byte(`{"Test":"t","Debug":"d"}`))
+	if err != nil || string(data) != `{"Test":"t"}` {
+		t.Fatalf("Unexpected result %s, %v", data, err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the hook to be called once, got %d", calls)
+	}
+}
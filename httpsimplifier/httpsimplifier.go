@@ -0,0 +1,172 @@
+// Package httpsimplifier connects gosimplifier to net/http, simplifying the JSON responses of
+// handlers before they are written to the client, and the headers and query parameters of
+// requests before they are recorded by access logs.
+package httpsimplifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// Selector returns the Simplifier of the response to a request, or nil to leave it as it is.
+type Selector func(r *http.Request) gosimplifier.Simplifier
+
+// ByPattern selects the Simplifier by the http.ServeMux pattern matching the request, e.g.
+// "GET /users/{id}", so the middleware is to wrap the handlers registered on a ServeMux rather
+// than the ServeMux itself. Requests matching no listed pattern use the Simplifier of "", if any.
+func ByPattern(simplifiers map[string]gosimplifier.Simplifier) Selector {
+	return func(r *http.Request) gosimplifier.Simplifier {
+		if s, ok := simplifiers[r.Pattern]; ok {
+			return s
+		}
+		return simplifiers[""]
+	}
+}
+
+// Middleware returns a middleware applying the Simplifier selected for every request to the
+// JSON response body, with a Content-Type of application/json or a +json suffix, so internal
+// fields never leave the service even if a handler forgets to strip them. Other responses are
+// written through as they are.
+//
+// JSON responses are buffered until the handler returns. A body that cannot be simplified is not
+// written: the client gets a 500 Internal Server Error instead.
+func Middleware(selector Selector) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s := selector(r)
+			if s == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+			rw.finish(s)
+		})
+	}
+}
+
+// responseWriter buffers the body of a JSON response, and writes any other response through.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	body        bytes.Buffer
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = status
+	w.buffering = isJSON(w.Header().Get("Content-Type"))
+	if !w.buffering {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(data))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Flush flushes the responses written through; a buffered JSON response is only written once
+// the handler returns.
+func (w *responseWriter) Flush() {
+	if !w.buffering {
+		http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish writes the simplified JSON response.
+func (w *responseWriter) finish(s gosimplifier.Simplifier) {
+	if !w.buffering {
+		return
+	}
+	body := w.body.Bytes()
+	if len(bytes.TrimSpace(body)) > 0 {
+		simplified, err := gosimplifier.SimplifyJSON(s, body)
+		var partial *gosimplifier.PartialError
+		if err != nil && !errors.As(err, &partial) {
+			w.Header().Del("Content-Length")
+			http.Error(w.ResponseWriter, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		body = simplified
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(body)
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// ruleClient fetches the rules of the RuleProviders given no client. Unlike http.DefaultClient it
+// times out, so a stalled config server cannot hold up the reloads of a ManagedSimplifier.
+var ruleClient = &http.Client{Timeout: 30 * time.Second}
+
+// RuleProvider returns the gosimplifier.RuleProvider fetching the JSON rules from url with
+// client, or a client timing out after 30 seconds if client is nil, for a
+// gosimplifier.ManagedSimplifier. The ETag of the response is the version of the rules, so the
+// rules are only transferred again once the server has a new version:
+//
+//	simplifier, err := gosimplifier.NewManagedSimplifier(
+//		httpsimplifier.RuleProvider("https://config.internal/rules/public_api", nil), time.Minute)
+func RuleProvider(url string, client *http.Client) gosimplifier.RuleProvider {
+	if client == nil {
+		client = ruleClient
+	}
+	return gosimplifier.RuleProviderFunc(func(ctx context.Context, version string) ([]byte, string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		if version != "" {
+			req.Header.Set("If-None-Match", version)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotModified:
+			return nil, "", gosimplifier.ErrNotModified
+		default:
+			return nil, "", fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		rules, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, "", err
+		}
+		return rules, resp.Header.Get("ETag"), nil
+	})
+}
@@ -0,0 +1,100 @@
+package httpsimplifier
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestMiddleware(t *testing.T) {
+	users, err := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strict, err := gosimplifier.NewSimplifier(`{}`, gosimplifier.WithNodeBudget(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	middleware := Middleware(ByPattern(map[string]gosimplifier.Simplifier{
+		"GET /users/{id}": users,
+		"GET /large":      strict,
+	}))
+	jsonHandler := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("Content-Length", "1000")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, body)
+		})
+	}
+	mux.Handle("GET /users/{id}", middleware(jsonHandler(`{"name":"n","password":"p"}`)))
+	mux.Handle("GET /large", middleware(jsonHandler(`{"a":{"b":{"c":1}}}`)))
+	mux.Handle("GET /text", middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"password":"p"}`)
+	})))
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/users/1", http.StatusCreated, `{"name":"n"}`},
+		{"/large", http.StatusInternalServerError, "Internal Server Error\n"},
+		{"/text", http.StatusOK, `{"password":"p"}`},
+	} {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if recorder.Code != tc.status || recorder.Body.String() != tc.body {
+			t.Errorf("%s: expected %d %q, got %d %q", tc.path, tc.status, tc.body, recorder.Code, recorder.Body.String())
+		}
+		if tc.status != http.StatusInternalServerError && recorder.Header().Get("Content-Length") == "1000" {
+			t.Errorf("%s: expected the Content-Length to be updated", tc.path)
+		}
+	}
+}
+
+func TestRuleProvider(t *testing.T) {
+	rules, etag := `{ "remove_properties": [ "password" ] }`, `"v1"`
+	var fetches, transfers int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		transfers++
+		w.Header().Set("ETag", etag)
+		io.WriteString(w, rules)
+	}))
+	defer server.Close()
+
+	simplifier, err := gosimplifier.NewManagedSimplifier(RuleProvider(server.URL, nil), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer simplifier.Close()
+	if err := simplifier.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 2 || transfers != 1 || simplifier.Version() != `"v1"` {
+		t.Errorf("Expected the rules to be transferred once, got %d fetches, %d transfers, version %s", fetches, transfers, simplifier.Version())
+	}
+	simplified, err := simplifier.Simplify(map[string]string{"name": "a", "password": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := simplified.(map[string]string)["password"]; ok {
+		t.Errorf("Expected the password to be removed, got %v", simplified)
+	}
+
+	rules, etag = `{ "remove_properties": [ "name" ] }`, `"v2"`
+	if err := simplifier.Reload(); err != nil || simplifier.Version() != `"v2"` {
+		t.Errorf("Expected the new rules to be loaded, got version %s, %v", simplifier.Version(), err)
+	}
+}
@@ -0,0 +1,128 @@
+package httpsimplifier
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// SimplifyHeader returns the copy of h simplified by s, applying the rules to the header as to
+// a map from the canonical header names, e.g. "Authorization", to their values, so access logs
+// can reuse the rules of the bodies. Rules written for single values, such as masks, apply to
+// every value of a multi-valued header. Empty values are dropped, as zero map values are.
+// Partial results of WithBestEffort simplifiers are returned with their
+// *gosimplifier.PartialError.
+func SimplifyHeader(s gosimplifier.Simplifier, h http.Header) (http.Header, error) {
+	simplified, err := simplifyValues(s, h)
+	return http.Header(simplified), err
+}
+
+// SimplifyQuery returns the copy of v simplified by s, applying the rules to the query as to a
+// map from the parameter names to their values, see SimplifyHeader.
+func SimplifyQuery(s gosimplifier.Simplifier, v url.Values) (url.Values, error) {
+	simplified, err := simplifyValues(s, v)
+	return url.Values(simplified), err
+}
+
+// simplifyValues applies s to the i-th values of all the keys of m at once, for every i.
+func simplifyValues(s gosimplifier.Simplifier, m map[string][]string) (map[string][]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	var partial error
+	result := make(map[string][]string, len(m))
+	for i := 0; ; i++ {
+		row := nthValues(m, i)
+		if len(row) == 0 {
+			return result, partial
+		}
+		simplified, err := s.Simplify(row)
+		if err != nil && !isPartial(err) {
+			return nil, err
+		}
+		if partial == nil {
+			partial = err
+		}
+		appendValues(result, simplified.(map[string]string))
+	}
+}
+
+// RequestMetadata is the metadata of a request recorded by access logs, see SimplifyRequest.
+type RequestMetadata struct {
+	Method     string
+	Path       string
+	RemoteAddr string
+	Header     http.Header
+	Query      url.Values
+}
+
+// requestRow holds the metadata of a request with the i-th values of its headers and query
+// parameters, for the rules to apply to single values.
+type requestRow struct {
+	Method     string
+	Path       string
+	RemoteAddr string
+	Header     map[string]string
+	Query      map[string]string
+}
+
+// SimplifyRequest returns the metadata of r simplified by s, applying the rules to it as to a
+// RequestMetadata whose Header and Query are maps, see SimplifyHeader, e.g.:
+//
+//	{
+//	  "property_simplifiers": {
+//	    "Header": { "remove_properties": [ "Authorization", "Cookie" ] },
+//	    "Query": { "mask_properties": [ "token" ] }
+//	  }
+//	}
+func SimplifyRequest(s gosimplifier.Simplifier, r *http.Request) (*RequestMetadata, error) {
+	query := r.URL.Query()
+	metadata := &RequestMetadata{Header: make(http.Header), Query: make(url.Values)}
+	var partial error
+	for i := 0; ; i++ {
+		row := &requestRow{Header: nthValues(r.Header, i), Query: nthValues(query, i)}
+		if i == 0 {
+			row.Method, row.Path, row.RemoteAddr = r.Method, r.URL.Path, r.RemoteAddr
+		} else if len(row.Header) == 0 && len(row.Query) == 0 {
+			return metadata, partial
+		}
+		simplified, err := s.Simplify(row)
+		if err != nil && !isPartial(err) {
+			return nil, err
+		}
+		if partial == nil {
+			partial = err
+		}
+		row = simplified.(*requestRow)
+		if i == 0 {
+			metadata.Method, metadata.Path, metadata.RemoteAddr = row.Method, row.Path, row.RemoteAddr
+		}
+		appendValues(metadata.Header, row.Header)
+		appendValues(metadata.Query, row.Query)
+	}
+}
+
+// nthValues returns the i-th values of the keys of m having one.
+func nthValues(m map[string][]string, i int) map[string]string {
+	row := make(map[string]string, len(m))
+	for key, values := range m {
+		if i < len(values) {
+			row[key] = values[i]
+		}
+	}
+	return row
+}
+
+// appendValues appends the values of row to those of the same keys in m.
+func appendValues(m map[string][]string, row map[string]string) {
+	for key, value := range row {
+		m[key] = append(m[key], value)
+	}
+}
+
+func isPartial(err error) bool {
+	var partial *gosimplifier.PartialError
+	return errors.As(err, &partial)
+}
@@ -0,0 +1,77 @@
+package httpsimplifier
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestSimplifyHeader(t *testing.T) {
+	s, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Authorization" ], "mask_properties": [ "Cookie" ] }`)
+	header := http.Header{
+		"Authorization": {"Bearer secret"},
+		"Cookie":        {"session=1", "theme=dark"},
+		"Accept":        {"text/html", "application/json"},
+	}
+	simplified, err := SimplifyHeader(s, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := http.Header{
+		"Cookie": {"****", "****"},
+		"Accept": {"text/html", "application/json"},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	if header.Get("Authorization") != "Bearer secret" {
+		t.Error("Expected the original header to be untouched")
+	}
+	if simplified, err := SimplifyHeader(s, nil); simplified != nil || err != nil {
+		t.Errorf("Expected a nil header to stay nil, got %v and %v", simplified, err)
+	}
+}
+
+func TestSimplifyQuery(t *testing.T) {
+	s, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "token" ] }`)
+	simplified, err := SimplifyQuery(s, url.Values{"token": {"t"}, "page": {"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (url.Values{"page": {"2"}}); !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
+
+func TestSimplifyRequest(t *testing.T) {
+	s, err := gosimplifier.NewSimplifier(`{
+		"remove_properties": [ "RemoteAddr" ],
+		"property_simplifiers": {
+			"Header": { "remove_properties": [ "Authorization" ] },
+			"Query": { "mask_properties": [ "token" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/users?token=a&token=b&page=2", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Add("Accept", "text/html")
+	metadata, err := SimplifyRequest(s, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &RequestMetadata{
+		Method: http.MethodGet,
+		Path:   "/users",
+		Header: http.Header{"Accept": {"text/html"}},
+		Query:  url.Values{"token": {"****", "****"}, "page": {"2"}},
+	}
+	if !reflect.DeepEqual(metadata, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, metadata)
+	}
+}
@@ -0,0 +1,201 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// indexRule applies a ruler to the list elements selected by an index selector:
+// "[2]", "[-1]" or "[last]" select a single element, "[1:3]", "[:2]" or "[-2:]" a half-open range.
+// Negative indexes count from the end of the list.
+type indexRule struct {
+	selector string
+	from, to int
+	hasFrom  bool
+	hasTo    bool
+	single   bool
+	ruler    ruler
+}
+
+// isIndexSelector reports whether a property name is an index selector such as "[0]".
+func isIndexSelector(name string) bool {
+	return strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]")
+}
+
+func parseIndexRule(selector string, r ruler) (*indexRule, error) {
+	inner := selector[1 : len(selector)-1]
+	rule := &indexRule{selector: selector, ruler: r}
+	parse := func(s string) (int, error) {
+		if s == "last" {
+			return -1, nil
+		}
+		return strconv.Atoi(s)
+	}
+	var err error
+	if from, to, isRange := strings.Cut(inner, ":"); isRange {
+		if rule.hasFrom = from != ""; rule.hasFrom {
+			if rule.from, err = parse(from); err != nil {
+				return nil, fmt.Errorf("invalid index selector %q", selector)
+			}
+		}
+		if rule.hasTo = to != ""; rule.hasTo {
+			if rule.to, err = parse(to); err != nil {
+				return nil, fmt.Errorf("invalid index selector %q", selector)
+			}
+		}
+		return rule, nil
+	}
+	if rule.from, err = parse(inner); err != nil {
+		return nil, fmt.Errorf("invalid index selector %q", selector)
+	}
+	rule.single = true
+	return rule, nil
+}
+
+// matches reports whether the rule selects index i of a list of length n.
+func (r *indexRule) matches(i int, n int) bool {
+	resolve := func(index int) int {
+		if index < 0 {
+			return n + index
+		}
+		return index
+	}
+	if r.single {
+		return resolve(r.from) == i
+	}
+	from, to := 0, n
+	if r.hasFrom {
+		from = resolve(r.from)
+	}
+	if r.hasTo {
+		to = resolve(r.to)
+	}
+	return i >= from && i < to
+}
+
+// newIndexRules collects the index selectors among the property rulers. Single indexes take
+// precedence over ranges; otherwise the selectors are tried in lexical order.
+func newIndexRules(propertySimplifiers map[string]ruler) ([]*indexRule, error) {
+	var rules []*indexRule
+	for name, r := range propertySimplifiers {
+		if !isIndexSelector(name) {
+			continue
+		}
+		rule, err := parseIndexRule(name, r)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].single != rules[j].single {
+			return rules[i].single
+		}
+		return rules[i].selector < rules[j].selector
+	})
+	return rules, nil
+}
+
+// elementRuler returns the selector and ruler for element i of a list of length n, or the
+// rules of s themselves if no selector matches.
+func (s *simplifierImpl) elementRuler(i int, n int) (string, ruler) {
+	for _, rule := range s.indexRules {
+		if rule.matches(i, n) {
+			return rule.selector, rule.ruler
+		}
+	}
+	return "", s
+}
+
+// splitIndexedName splits "EntityList[0]" into "EntityList" and "[0]".
+func splitIndexedName(name string) (string, string, bool) {
+	i := strings.IndexByte(name, '[')
+	if i <= 0 || !strings.HasSuffix(name, "]") {
+		return "", "", false
+	}
+	return name[:i], name[i:], true
+}
+
+// expandIndexedNames returns rule with every property name such as "EntityList[0]" moved into
+// the rule of "EntityList" as "[0]", so the selector applies to the elements of that list.
+// The sections of the rule are found by reflection, so every []string or map section is covered.
+// rule itself is not modified.
+func expandIndexedNames(rule *Rule) *Rule {
+	expanded := *rule
+	ruleValue := reflect.ValueOf(&expanded).Elem()
+	moved := make(map[string]*Rule)
+	subRule := func(base string) reflect.Value {
+		sub, ok := moved[base]
+		if !ok {
+			sub = &Rule{}
+			if existing := rule.PropertySimplifiers[base]; existing != nil {
+				copied := *existing
+				sub = &copied
+			}
+			moved[base] = sub
+		}
+		return reflect.ValueOf(sub).Elem()
+	}
+
+	for i := 0; i < ruleValue.NumField(); i++ {
+		section := ruleValue.Field(i)
+		switch {
+		case section.Kind() == reflect.Slice && section.Type().Elem().Kind() == reflect.String:
+			var kept []string
+			for _, name := range section.Interface().([]string) {
+				base, selector, ok := splitIndexedName(name)
+				if !ok {
+					kept = append(kept, name)
+					continue
+				}
+				target := subRule(base).Field(i)
+				target.Set(reflect.Append(reflect.ValueOf(append([]string(nil), target.Interface().([]string)...)), reflect.ValueOf(selector)))
+			}
+			if len(kept) != section.Len() {
+				section.Set(reflect.ValueOf(kept))
+			}
+		case section.Kind() == reflect.Map && section.Type().Key().Kind() == reflect.String:
+			var keep reflect.Value
+			for _, key := range section.MapKeys() {
+				base, selector, ok := splitIndexedName(key.String())
+				if !ok {
+					continue
+				}
+				if !keep.IsValid() {
+					keep = reflect.MakeMap(section.Type())
+					for _, k := range section.MapKeys() {
+						keep.SetMapIndex(k, section.MapIndex(k))
+					}
+				}
+				keep.SetMapIndex(key, reflect.Value{})
+				target := subRule(base).Field(i)
+				copied := reflect.MakeMap(section.Type())
+				if !target.IsNil() {
+					for _, k := range target.MapKeys() {
+						copied.SetMapIndex(k, target.MapIndex(k))
+					}
+				}
+				copied.SetMapIndex(reflect.ValueOf(selector), section.MapIndex(key))
+				target.Set(copied)
+			}
+			if keep.IsValid() {
+				section.Set(keep)
+			}
+		}
+	}
+	if len(moved) == 0 {
+		return rule
+	}
+	propertySimplifiers := make(map[string]*Rule, len(expanded.PropertySimplifiers)+len(moved))
+	for name, sub := range expanded.PropertySimplifiers {
+		propertySimplifiers[name] = sub
+	}
+	for base, sub := range moved {
+		propertySimplifiers[base] = sub
+	}
+	expanded.PropertySimplifiers = propertySimplifiers
+	return &expanded
+}
@@ -0,0 +1,119 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Track struct {
+	Name   string
+	Points [][]float64
+	Stops  []*Stop
+}
+
+type Stop struct {
+	Name string
+	Note string
+}
+
+func TestIndexSelectors(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Stops[last]" ],
+		"property_simplifiers": {
+			"Points": {
+				"property_simplifiers": {
+					"[:]": { "remove_properties": [ "[2]" ] }
+				}
+			},
+			"Stops[0]": { "remove_properties": [ "Note" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := Track{
+		Name:   "loop",
+		Points: [][]float64{{1, 2, 100}, {3, 4, 200}},
+		Stops:  []*Stop{{"a", "x"}, {"b", "y"}, {"c", "z"}},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Track{
+		Name:   "loop",
+		Points: [][]float64{{1, 2, 0}, {3, 4, 0}},
+		Stops:  []*Stop{{"a", ""}, {"b", "y"}, nil},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if err := ValidateForType(simplifier, reflect.TypeOf(Track{})); err != nil {
+		t.Errorf("Unexpected validation error %v", err)
+	}
+}
+
+func TestIndexRuleMatches(t *testing.T) {
+	for _, c := range []struct {
+		selector string
+		matched  []int
+	}{
+		{"[0]", []int{0}},
+		{"[last]", []int{4}},
+		{"[-2]", []int{3}},
+		{"[1:3]", []int{1, 2}},
+		{"[:2]", []int{0, 1}},
+		{"[-2:]", []int{3, 4}},
+		{"[7]", nil},
+	} {
+		rule, err := parseIndexRule(c.selector, removeRulerSingleton)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var matched []int
+		for i := 0; i < 5; i++ {
+			if rule.matches(i, 5) {
+				matched = append(matched, i)
+			}
+		}
+		if !reflect.DeepEqual(matched, c.matched) {
+			t.Errorf("%s: expected %v, got %v", c.selector, c.matched, matched)
+		}
+	}
+	if _, err := NewSimplifier(`{ "remove_properties": [ "[x]" ] }`); err == nil {
+		t.Error("Expected an invalid selector to fail")
+	}
+}
+
+func TestIndexSelectorRulePath(t *testing.T) {
+	var paths []string
+	record := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Action() != "" {
+				paths = append(paths, node.Path()+"="+node.RulePath())
+			}
+			return next(node)
+		}
+	}
+	simplifier, _ := NewSimplifier(`{ "property_simplifiers": { "Stops[1]": { "remove_properties": [ "Note" ] } } }`, WithMiddleware(record))
+	simplifier.Simplify(Track{Stops: []*Stop{{"a", "x"}, {"b", "y"}}})
+	if expected := []string{"Stops[1].Note=Stops[1].Note"}; !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestExpandIndexedNamesKeepsRule(t *testing.T) {
+	rule := &Rule{
+		RemoveProperties:    []string{"Stops[0]", "Name"},
+		PropertySimplifiers: map[string]*Rule{"Stops": {RemoveProperties: []string{"[1]"}}},
+	}
+	expanded := expandIndexedNames(rule)
+	if !reflect.DeepEqual(rule.RemoveProperties, []string{"Stops[0]", "Name"}) || len(rule.PropertySimplifiers["Stops"].RemoveProperties) != 1 {
+		t.Errorf("Expected the rule to be unchanged, got %+v", rule)
+	}
+	if !reflect.DeepEqual(expanded.RemoveProperties, []string{"Name"}) ||
+		!reflect.DeepEqual(expanded.PropertySimplifiers["Stops"].RemoveProperties, []string{"[1]", "[0]"}) {
+		t.Errorf("Unexpected expansion %+v %+v", expanded, expanded.PropertySimplifiers["Stops"])
+	}
+}
@@ -0,0 +1,110 @@
+package gosimplifier
+
+import (
+	"context"
+	"reflect"
+	"sort"
+)
+
+type metadataKey struct{}
+
+// ContextWithMetadata returns a context carrying metadata for SimplifyContext, on top of the
+// metadata ctx already carries. inject_properties rules reference it by key, so simplified
+// records can be stamped with e.g. the request ID or the policy version:
+//
+//	{ "inject_properties": { "RequestID": "request_id", "Policy": "policy_version" } }
+//
+//	ctx = gosimplifier.ContextWithMetadata(ctx, map[string]string{"request_id": id, "policy_version": "v3"})
+//	simplified, err := gosimplifier.SimplifyContext(ctx, simplifier, record)
+func ContextWithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	merged := make(map[string]string)
+	for k, v := range metadataFrom(ctx) {
+		merged[k] = v
+	}
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, metadataKey{}, merged)
+}
+
+func metadataFrom(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+// injectRuler replaces a value with the metadata value of key.
+type injectRuler struct {
+	key string
+}
+
+// apply sets the node to the metadata value. Without metadata for the key, or if the node
+// cannot hold a string, the value is removed instead, as it was meant to be replaced.
+func (r *injectRuler) apply(node *Node) error {
+	if node.Parent.Kind() == reflect.Invalid {
+		return nil
+	}
+	metadata, ok := node.walk.metadata[r.key]
+	if !ok {
+		return removeRulerSingleton.apply(node)
+	}
+	if value := indirect(node.Value); value.Kind() == reflect.String && value.CanSet() {
+		value.SetString(metadata)
+		return nil
+	}
+	replacement := reflect.ValueOf(metadata)
+	if node.Value.Kind() == reflect.String {
+		replacement = replacement.Convert(node.Value.Type())
+	}
+	if !node.set(replacement) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *injectRuler) action() string {
+	return "inject"
+}
+
+// injectMissing visits the inject_properties the map does not have yet, so they are added.
+func (s *simplifierImpl) injectMissing(node *Node, mapValue reflect.Value) error {
+	mapType := mapValue.Type()
+	if len(s.rule.InjectProperties) == 0 || mapType.Key().Kind() != reflect.String ||
+		!reflect.TypeOf("").ConvertibleTo(mapType.Elem()) && mapType.Elem().Kind() != reflect.Interface {
+		return nil
+	}
+	propNames := make([]string, 0, len(s.rule.InjectProperties))
+	for propName := range s.rule.InjectProperties {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+	for _, propName := range propNames {
+		r, ok := s.propertySimplifiers[propName].(*injectRuler)
+		if !ok {
+			continue
+		}
+		mapKey := reflect.ValueOf(propName).Convert(mapType.Key())
+		if _, ok := node.walk.metadata[r.key]; !ok || mapValue.MapIndex(mapKey).IsValid() {
+			continue
+		}
+		child := node.child(mapValue, reflect.Zero(mapType.Elem()), mapKey, propName, -1, s, r)
+		if err := node.walk.visitChild(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeMaps returns the entries of both maps, preferring those of newMap.
+func mergeMaps[V any](m map[string]V, newMap map[string]V) map[string]V {
+	if m == nil && newMap == nil {
+		return nil
+	}
+	merged := make(map[string]V, len(m)+len(newMap))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range newMap {
+		merged[k] = v
+	}
+	return merged
+}
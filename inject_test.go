@@ -0,0 +1,45 @@
+package gosimplifier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type AccessLog struct {
+	Path      string
+	RequestID string
+	Session   string
+}
+
+func TestInjectProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "inject_properties": { "RequestID": "request_id", "Session": "session", "policy": "policy_version" } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := ContextWithMetadata(context.Background(), map[string]string{"request_id": "req-1"})
+	ctx = ContextWithMetadata(ctx, map[string]string{"policy_version": "v3"})
+
+	simplified, err := SimplifyContext(ctx, simplifier, AccessLog{Path: "/", RequestID: "client-supplied", Session: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := AccessLog{Path: "/", RequestID: "req-1"}
+	if simplified != expected {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	simplified, err = SimplifyContext(ctx, simplifier, map[string]interface{}{"Path": "/", "Session": "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedMap := map[string]interface{}{"Path": "/", "RequestID": "req-1", "policy": "v3"}
+	if !reflect.DeepEqual(simplified, expectedMap) {
+		t.Errorf("Expected %v, got %v", expectedMap, simplified)
+	}
+
+	simplified, _ = simplifier.Simplify(AccessLog{Path: "/", RequestID: "client-supplied"})
+	if simplified != (AccessLog{Path: "/"}) {
+		t.Errorf("Expected the properties to be removed without metadata, got %+v", simplified)
+	}
+}
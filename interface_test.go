@@ -0,0 +1,88 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type InterfaceHolder struct {
+	Any      interface{}
+	Stringer fmt.Stringer
+	List     []interface{}
+	Values   map[string]interface{}
+}
+
+type StringerStruct struct {
+	Test  string
+	Debug string
+	count int
+}
+
+func (s StringerStruct) String() string {
+	return s.Test
+}
+
+func TestInterfaceDispatch(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Any": { "remove_properties": [ "Debug" ] },
+			"Stringer": { "remove_properties": [ "Debug" ] },
+			"List": { "remove_properties": [ "Debug" ] },
+			"Values": { "property_simplifiers": { "sub": { "remove_properties": [ "Debug" ] } } }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := InterfaceHolder{
+		Any:      SubStruct{Test: "t", Debug: "d"},
+		Stringer: &StringerStruct{Test: "t", Debug: "d", count: 1},
+		List:     []interface{}{SubStruct{Test: "t", Debug: "d"}, &SubStruct{Test: "t", Debug: "d"}},
+		Values:   map[string]interface{}{"sub": StringerStruct{Test: "t", Debug: "d", count: 2}},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := InterfaceHolder{
+		Any:      SubStruct{Test: "t"},
+		Stringer: &StringerStruct{Test: "t", count: 1},
+		List:     []interface{}{SubStruct{Test: "t"}, &SubStruct{Test: "t"}},
+		Values:   map[string]interface{}{"sub": StringerStruct{Test: "t", count: 2}},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if original.Any.(SubStruct).Debug != "d" || original.Stringer.(*StringerStruct).Debug != "d" ||
+		original.List[1].(*SubStruct).Debug != "d" || original.Values["sub"].(StringerStruct).Debug != "d" {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	inPlace := InterfaceHolder{Any: SubStruct{Test: "t", Debug: "d"}, Values: map[string]interface{}{"sub": SubStruct{Test: "t", Debug: "d"}}}
+	if err := SimplifyInPlace(simplifier, &inPlace); err != nil {
+		t.Fatal(err)
+	}
+	if inPlace.Any != (SubStruct{Test: "t"}) || inPlace.Values["sub"] != (SubStruct{Test: "t"}) {
+		t.Errorf("Expected the boxed structs to be replaced in place, got %+v", inPlace)
+	}
+}
+
+func TestInterfaceDispatchNested(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Any": { "property_simplifiers": { "Any": { "remove_properties": [ "Debug" ] } } }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := DryRun(simplifier, InterfaceHolder{Any: InterfaceHolder{Any: [1]SubStruct{{Test: "t", Debug: "d"}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{{Path: "Any.Any[0].Debug", Action: "remove", Rule: "Any.Any.Debug"}}
+	if !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, report.Changes)
+	}
+}
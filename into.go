@@ -0,0 +1,157 @@
+package gosimplifier
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// IntoSimplifier is a Simplifier writing its results into a given value, see SimplifyInto.
+type IntoSimplifier interface {
+	Simplifier
+	// SimplifyInto writes the simplified copy of original into the value dst points to,
+	// reusing the allocations dst already holds.
+	SimplifyInto(original interface{}, dst interface{}) error
+}
+
+// SimplifyInto writes the simplified copy of original into the value dst points to, which must
+// be a non-nil pointer to the type of original, or to the type original points to. An
+// IntoSimplifier reuses the allocations dst already holds, e.g. when dst comes from a pool;
+// other simplifiers return a new copy, which then replaces the value of dst.
+func SimplifyInto(s Simplifier, original interface{}, dst interface{}) error {
+	if is, ok := s.(IntoSimplifier); ok {
+		return is.SimplifyInto(original, dst)
+	}
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return fmt.Errorf("SimplifyInto requires a non-nil pointer destination, got %T", dst)
+	}
+	simplified, err := s.Simplify(original)
+	if err != nil && !isPartial(err) {
+		return err
+	}
+	result := reflect.ValueOf(simplified)
+	target := dstValue.Elem()
+	if result.IsValid() && result.Type() != target.Type() &&
+		result.Kind() == reflect.Ptr && result.Type().Elem() == target.Type() && !result.IsNil() {
+		result = result.Elem()
+	}
+	switch {
+	case !result.IsValid():
+		target.Set(reflect.Zero(target.Type()))
+	case result.Type() != target.Type():
+		return fmt.Errorf("SimplifyInto cannot write %T into %T", simplified, dst)
+	default:
+		target.Set(result)
+	}
+	return err
+}
+
+// SimplifyInto writes the simplified copy of original into the value dst points to, reusing the
+// slices, maps and pointers already held by dst where possible, e.g. when dst comes from a pool.
+// dst must be a non-nil pointer to the type of original, or to the type original points to.
+func (s *simplifierImpl) SimplifyInto(original interface{}, dst interface{}) error {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return fmt.Errorf("SimplifyInto requires a non-nil pointer destination, got %T", dst)
+	}
+	originalValue := reflect.ValueOf(original)
+	target := dstValue.Elem()
+	if originalValue.IsValid() && originalValue.Type() != target.Type() &&
+		originalValue.Kind() == reflect.Ptr && originalValue.Type().Elem() == target.Type() {
+		originalValue = originalValue.Elem()
+	}
+	switch {
+	case !originalValue.IsValid():
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	case originalValue.Type() != target.Type():
+		return fmt.Errorf("SimplifyInto cannot write %T into %T", original, dst)
+	}
+
+	if err := copyInto(target, originalValue); err != nil {
+		return err
+	}
+	return s.simplify(context.Background(), dstValue, nil)
+}
+
+// copyInto makes dst a deep copy of original, reusing the storage dst already holds.
+func copyInto(dst reflect.Value, original reflect.Value) error {
+	if copiesWhole(original.Type()) {
+		return setCopy(dst, original)
+	}
+	switch original.Kind() {
+	case reflect.Ptr:
+		if original.IsNil() {
+			dst.Set(original)
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(original.Type().Elem()))
+		}
+		return copyInto(dst.Elem(), original.Elem())
+	case reflect.Slice:
+		if original.IsNil() {
+			dst.Set(original)
+			return nil
+		}
+		if dst.IsNil() || dst.Cap() < original.Len() {
+			dst.Set(reflect.MakeSlice(original.Type(), original.Len(), original.Len()))
+		} else {
+			dst.SetLen(original.Len())
+		}
+		for i := 0; i < original.Len(); i++ {
+			if err := copyInto(dst.Index(i), original.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if original.IsNil() || dst.IsNil() {
+			return setCopy(dst, original)
+		}
+		for _, mapKey := range dst.MapKeys() {
+			dst.SetMapIndex(mapKey, reflect.Value{})
+		}
+		for _, mapKey := range original.MapKeys() {
+			mapValue := original.MapIndex(mapKey)
+			copied, err := deepCopy(reflect.New(mapValue.Type()).Elem(), mapValue)
+			if err != nil {
+				return err
+			}
+			dst.SetMapIndex(mapKey, copied)
+		}
+	case reflect.Struct:
+		for i := 0; i < original.NumField(); i++ {
+			if err := copyInto(dst.Field(i), original.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < original.Len(); i++ {
+			if err := copyInto(dst.Index(i), original.Index(i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return setCopy(dst, original)
+	}
+	return nil
+}
+
+// setCopy sets dst to a deep copy of original.
+func setCopy(dst reflect.Value, original reflect.Value) error {
+	copied, err := deepCopy(reflect.New(original.Type()).Elem(), original)
+	if err != nil {
+		return err
+	}
+	dst.Set(copied)
+	return nil
+}
+
+// copiesWhole reports whether values of type t are copied as a whole, as deepCopy does, rather
+// than into the storage of dst: leaf types, types with a copier, and structs with unexported
+// fields, which reflection cannot set one by one.
+func copiesWhole(t reflect.Type) bool {
+	return isLeafType(t) || lookupCopyHook(t).copies() ||
+		t.Kind() == reflect.Struct && hasUnexportedFields(t)
+}
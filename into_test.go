@@ -0,0 +1,90 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimplifyInto(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"EntityList": {
+				"property_simplifiers": {
+					"SubProperties": {
+						"remove_properties": [ "ABC" ]
+					}
+				}
+			}
+		}
+	}`)
+
+	original := &ExampleStruct{
+		Test:       5,
+		Debug:      "debug",
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{ABC: "abc", DEF: "def"}}},
+	}
+
+	dst := &ExampleStruct{EntityList: make([]EntityStruct, 3, 8)}
+	backing := &dst.EntityList[:1][0]
+	if err := SimplifyInto(simplifier, original, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ExampleStruct{
+		Test:       5,
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{DEF: "def"}}},
+	}
+	if !reflect.DeepEqual(*dst, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, *dst)
+	}
+	if &dst.EntityList[0] != backing {
+		t.Error("Expected the destination slice to be reused")
+	}
+	if original.Debug != "debug" || original.EntityList[0].SubProperties.ABC != "abc" {
+		t.Error("Expected the original to be unchanged")
+	}
+}
+
+func TestSimplifyIntoMap(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "debug" ] }`)
+
+	dst := map[string]interface{}{"stale": true}
+	if err := SimplifyInto(simplifier, map[string]interface{}{"id": 1, "debug": "x"}, &dst); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dst, map[string]interface{}{"id": 1}) {
+		t.Errorf("Unexpected result %v", dst)
+	}
+}
+
+type IntoEvent struct {
+	Name  string
+	At    time.Time
+	Debug string
+}
+
+func TestSimplifyIntoTime(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	dst := &IntoEvent{}
+	if err := SimplifyInto(simplifier, &IntoEvent{Name: "login", At: at, Debug: "x"}, dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Name != "login" || !dst.At.Equal(at) || dst.Debug != "" {
+		t.Errorf("Unexpected result %+v", *dst)
+	}
+}
+
+func TestSimplifyIntoInvalidDestination(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{}`)
+
+	if err := SimplifyInto(simplifier, ExampleStruct{}, ExampleStruct{}); err == nil {
+		t.Error("Expected an error for a non-pointer destination")
+	}
+	if err := SimplifyInto(simplifier, ExampleStruct{}, &DataStruct{}); err == nil {
+		t.Error("Expected an error for a mismatched destination")
+	}
+}
@@ -0,0 +1,245 @@
+package gosimplifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// JSONSimplifier is a Simplifier for JSON documents, see SimplifyJSON.
+type JSONSimplifier interface {
+	Simplifier
+	// SimplifyJSON applies the rules to a JSON document, matching rule names against object
+	// keys.
+	SimplifyJSON(data []byte) ([]byte, error)
+}
+
+// SimplifyJSON applies the rules of s to a JSON document, matching the rule names against the
+// object keys. Simplifiers that are not a JSONSimplifier simplify the decoded document, a
+// map[string]interface{} or []interface{} holding json.Number numbers, which is then encoded.
+func SimplifyJSON(s Simplifier, data []byte) ([]byte, error) {
+	if js, ok := s.(JSONSimplifier); ok {
+		return js.SimplifyJSON(data)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if err := expectJSONEnd(decoder); err != nil {
+		return nil, err
+	}
+	simplified, err := s.Simplify(document)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	output, marshalErr := json.Marshal(simplified)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	return output, err
+}
+
+// SimplifyJSON applies the rules to a JSON document and returns the simplified document,
+// matching the rule names against the object keys. Numbers are passed through unchanged.
+//
+// Documents are streamed token by token when the rules only remove properties, without
+// building an intermediate map[string]interface{}; rules needing the decoded values, and
+// middlewares, make the whole document decode first. Either way, object members whose value
+// is null are dropped like zero map values, and the member order of the output is not
+// significant. Objects are flattened as the rules say, see FlattenRule.
+func (s *simplifierImpl) SimplifyJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if !s.canStreamJSON() {
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			return nil, err
+		}
+		if err := expectJSONEnd(decoder); err != nil {
+			return nil, err
+		}
+		err := s.simplify(context.Background(), reflect.ValueOf(&document), nil)
+		if err != nil && !isPartial(err) {
+			return nil, err
+		}
+		if s.flattens(make(map[*simplifierImpl]bool)) {
+			document = (&mapper{root: s, options: s.options}).value(reflect.ValueOf(document), "", s)
+		}
+		simplified, marshalErr := json.Marshal(document)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		return simplified, err
+	}
+
+	rewriter := &jsonRewriter{decoder: decoder, root: s}
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := rewriter.value(token, s); err != nil {
+		return nil, err
+	}
+	if err := expectJSONEnd(decoder); err != nil {
+		return nil, err
+	}
+	return rewriter.out.Bytes(), nil
+}
+
+func expectJSONEnd(decoder *json.Decoder) error {
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+	}
+	return nil
+}
+
+// canStreamJSON reports whether the rules can be applied to the JSON tokens directly, which
+// is the case when no middleware or hook observes the traversal and every rule only removes,
+// deleting the removed members.
+func (s *simplifierImpl) canStreamJSON() bool {
+	return !s.options.observesTraversal() && !s.options.hasHooks() && s.onlyRemoves() &&
+		(s.options.removal == nil || s.options.removal.mode == RemoveDelete) && !s.usesRemovalModes(make(map[*simplifierImpl]bool))
+}
+
+func (s *simplifierImpl) onlyRemoves() bool {
+	if s.rule.KeyValue != nil || len(s.rule.RenameProperties) > 0 || s.rule.RemoveIf != nil ||
+		len(s.rule.RemoveIfZero) > 0 || len(s.indexRules) > 0 || s.rule.PruneBelowDepth > 0 || s.rule.MaxItems > 0 ||
+		s.rule.Flatten != nil {
+		return false
+	}
+	for _, r := range s.propertySimplifiers {
+		switch r := r.(type) {
+		case *removeRuler:
+		case *simplifierImpl:
+			if !r.onlyRemoves() {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// jsonRewriter copies JSON tokens to out, leaving out the removed members.
+type jsonRewriter struct {
+	decoder *json.Decoder
+	out     bytes.Buffer
+	root    *simplifierImpl
+}
+
+// value writes the value starting with token, applying the rules of s to its members.
+func (r *jsonRewriter) value(token json.Token, s *simplifierImpl) error {
+	switch token {
+	case json.Delim('{'):
+		return r.object(s)
+	case json.Delim('['):
+		return r.array(s)
+	}
+	if number, ok := token.(json.Number); ok {
+		r.out.WriteString(number.String())
+		return nil
+	}
+	encoded, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	r.out.Write(encoded)
+	return nil
+}
+
+func (r *jsonRewriter) object(s *simplifierImpl) error {
+	r.out.WriteByte('{')
+	first := true
+	for r.decoder.More() {
+		keyToken, err := r.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key := keyToken.(string)
+		subSimplifier := r.root
+		_, keyRuler := s.keyRuler(key)
+		if keyRuler == nil {
+			keyRuler = s.unmatchedRuler(key, r.root)
+		}
+		switch propertySimplifier := keyRuler.(type) {
+		case *removeRuler:
+			if err := r.skip(); err != nil {
+				return err
+			}
+			continue
+		case *simplifierImpl:
+			subSimplifier = propertySimplifier
+		}
+
+		token, err := r.decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			continue
+		}
+		if !first {
+			r.out.WriteByte(',')
+		}
+		first = false
+		encodedKey, _ := json.Marshal(key)
+		r.out.Write(encodedKey)
+		r.out.WriteByte(':')
+		if err := r.value(token, subSimplifier); err != nil {
+			return err
+		}
+	}
+	if _, err := r.decoder.Token(); err != nil {
+		return err
+	}
+	r.out.WriteByte('}')
+	return nil
+}
+
+func (r *jsonRewriter) array(s *simplifierImpl) error {
+	r.out.WriteByte('[')
+	for i := 0; r.decoder.More(); i++ {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			r.out.WriteByte(',')
+		}
+		if err := r.value(token, s); err != nil {
+			return err
+		}
+	}
+	if _, err := r.decoder.Token(); err != nil {
+		return err
+	}
+	r.out.WriteByte(']')
+	return nil
+}
+
+// skip consumes the next value without writing it.
+func (r *jsonRewriter) skip() error {
+	depth := 0
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
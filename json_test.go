@@ -0,0 +1,110 @@
+package gosimplifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const jsonDocument = `{
+	"id": 12345678901234567890,
+	"debug": { "trace": [1, 2, 3] },
+	"password": null,
+	"data": { "data_test": "x", "data_debug": 1, "kept": true },
+	"entity_list": [
+		{ "sub_properties": { "abc": "a", "def": "d", "ghi": "g" } },
+		null,
+		"text"
+	],
+	"nest": { "debug": "nested", "name": "é" }
+}`
+
+const jsonRules = `{
+	"remove_properties": [ "debug" ],
+	"property_simplifiers": {
+		"data": {
+			"remove_properties": [ "data_test", "data_debug" ]
+		},
+		"entity_list": {
+			"property_simplifiers": {
+				"sub_properties": {
+					"remove_properties": [ "abc", "def" ]
+				}
+			}
+		}
+	}
+}`
+
+const jsonExpected = `{
+	"id": 12345678901234567890,
+	"data": { "kept": true },
+	"entity_list": [ { "sub_properties": { "ghi": "g" } }, null, "text" ],
+	"nest": { "name": "é" }
+}`
+
+func TestSimplifyJSON(t *testing.T) {
+	simplifier, _ := NewSimplifier(jsonRules)
+	if !simplifier.(*simplifierImpl).canStreamJSON() {
+		t.Fatal("Expected removal rules to be streamed")
+	}
+
+	simplified, err := SimplifyJSON(simplifier, []byte(jsonDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, simplified, jsonExpected)
+	if !bytes.Contains(simplified, []byte(`"id":12345678901234567890`)) {
+		t.Errorf("Expected numbers to be passed through unchanged, got %s", simplified)
+	}
+}
+
+func TestSimplifyJSONDecoded(t *testing.T) {
+	visited := 0
+	counter := func(next Walker) Walker {
+		return func(node *Node) error {
+			visited++
+			return next(node)
+		}
+	}
+	simplifier, _ := NewSimplifier(jsonRules, WithMiddleware(counter))
+	if simplifier.(*simplifierImpl).canStreamJSON() {
+		t.Fatal("Expected middlewares to require decoding")
+	}
+
+	simplified, err := SimplifyJSON(simplifier, []byte(jsonDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, simplified, jsonExpected)
+	if !bytes.Contains(simplified, []byte(`"id":12345678901234567890`)) {
+		t.Errorf("Expected numbers to be passed through unchanged, got %s", simplified)
+	}
+	if visited == 0 {
+		t.Error("Expected the middleware to see the decoded document")
+	}
+}
+
+func TestSimplifyJSONInvalid(t *testing.T) {
+	simplifier, _ := NewSimplifier(jsonRules)
+
+	for _, document := range []string{`{"a": }`, `{"a": 1} {"b": 2}`, `[1, 2`, ``} {
+		if _, err := SimplifyJSON(simplifier, []byte(document)); err == nil {
+			t.Errorf("Expected an error for %q", document)
+		}
+	}
+}
+
+func assertJSONEqual(t *testing.T, actual []byte, expected string) {
+	t.Helper()
+	var actualValue, expectedValue interface{}
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", actual, err)
+	}
+	if err := json.Unmarshal([]byte(expected), &expectedValue); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("Expected %s, got %s", expected, actual)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/xhinliang/gosimplifier/kafkasimplifier
+
+go 1.23.0
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	github.com/xhinliang/gosimplifier v0.1.0
+	github.com/xhinliang/gosimplifier/msgpacksimplifier v0.1.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,124 @@
+// Package kafkasimplifier connects gosimplifier to Kafka clients, simplifying the payloads of
+// messages by topic before they are produced or after they are consumed, so scrubbing is
+// enforced at the transport layer.
+//
+// The Interceptor works on topics and payloads only, so it fits the hooks of any client. With
+// sarama, as producer and consumer interceptors:
+//
+//	type scrubber struct{ *kafkasimplifier.Interceptor }
+//
+//	func (s scrubber) OnSend(msg *sarama.ProducerMessage) {
+//		value, _ := msg.Value.Encode()
+//		msg.Value = sarama.ByteEncoder(s.Scrub(msg.Topic, value))
+//	}
+//
+//	func (s scrubber) OnConsume(msg *sarama.ConsumerMessage) {
+//		msg.Value = s.Scrub(msg.Topic, msg.Value)
+//	}
+//
+//	config.Producer.Interceptors = []sarama.ProducerInterceptor{scrubber{interceptor}}
+//	config.Consumer.Interceptors = []sarama.ConsumerInterceptor{scrubber{interceptor}}
+//
+// With franz-go, whose hooks must not change records, before producing and after fetching:
+//
+//	record.Value, err = interceptor.Simplify(record.Topic, record.Value)
+//	client.Produce(ctx, record, promise)
+//
+//	fetches.EachRecord(func(record *kgo.Record) {
+//		record.Value = interceptor.Scrub(record.Topic, record.Value)
+//	})
+package kafkasimplifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xhinliang/gosimplifier"
+	"github.com/xhinliang/gosimplifier/msgpacksimplifier"
+)
+
+// Selector returns the Simplifier of the messages of a topic, or nil to leave them as they are.
+type Selector func(topic string) gosimplifier.Simplifier
+
+// ByTopic selects the Simplifier by topic. Topics not listed use the Simplifier of "", if any.
+func ByTopic(simplifiers map[string]gosimplifier.Simplifier) Selector {
+	return func(topic string) gosimplifier.Simplifier {
+		if s, ok := simplifiers[topic]; ok {
+			return s
+		}
+		return simplifiers[""]
+	}
+}
+
+// Codec applies a Simplifier to an encoded payload.
+type Codec func(s gosimplifier.Simplifier, payload []byte) ([]byte, error)
+
+// JSON is the Codec of JSON payloads, see gosimplifier.SimplifyJSON.
+func JSON(s gosimplifier.Simplifier, payload []byte) ([]byte, error) {
+	return gosimplifier.SimplifyJSON(s, payload)
+}
+
+// MessagePack is the Codec of MessagePack payloads, see msgpacksimplifier.SimplifyMsgpack.
+func MessagePack(s gosimplifier.Simplifier, payload []byte) ([]byte, error) {
+	return msgpacksimplifier.SimplifyMsgpack(s, payload)
+}
+
+// Interceptor simplifies the payloads of messages with the Simplifier selected for their topic.
+// It is safe for concurrent use.
+type Interceptor struct {
+	selector Selector
+	codec    Codec
+	onError  func(topic string, err error)
+}
+
+// Option configures an Interceptor.
+type Option func(*Interceptor)
+
+// WithOnError sets the function called with the payloads Scrub fails to simplify, e.g. to log
+// or count them. It is not called by Simplify, which returns the error.
+func WithOnError(fn func(topic string, err error)) Option {
+	return func(i *Interceptor) {
+		i.onError = fn
+	}
+}
+
+// NewInterceptor creates an Interceptor decoding the payloads with codec, JSON if nil.
+func NewInterceptor(selector Selector, codec Codec, opts ...Option) *Interceptor {
+	if codec == nil {
+		codec = JSON
+	}
+	i := &Interceptor{selector: selector, codec: codec}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// Simplify returns the payload simplified with the Simplifier selected for the topic, or as it
+// is if none is selected or the payload is empty, such as a tombstone. Partial results of
+// WithBestEffort simplifiers are returned without error.
+func (i *Interceptor) Simplify(topic string, payload []byte) ([]byte, error) {
+	s := i.selector(topic)
+	if s == nil || len(payload) == 0 {
+		return payload, nil
+	}
+	simplified, err := i.codec(s, payload)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, fmt.Errorf("topic %s: %w", topic, err)
+	}
+	return simplified, nil
+}
+
+// Scrub is Simplify for hooks that cannot fail, such as the interceptors of sarama: a payload
+// that cannot be simplified is replaced with nil, so it never passes unscrubbed.
+func (i *Interceptor) Scrub(topic string, payload []byte) []byte {
+	simplified, err := i.Simplify(topic, payload)
+	if err != nil {
+		if i.onError != nil {
+			i.onError(topic, err)
+		}
+		return nil
+	}
+	return simplified
+}
@@ -0,0 +1,79 @@
+package kafkasimplifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestInterceptor(t *testing.T) {
+	users, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+	fallback, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "token" ] }`)
+	interceptor := NewInterceptor(ByTopic(map[string]gosimplifier.Simplifier{"users": users, "": fallback}), nil)
+
+	for _, c := range []struct {
+		topic    string
+		payload  string
+		expected string
+	}{
+		{"users", `{"id":1,"password":"p","token":"t"}`, `{"id":1,"token":"t"}`},
+		{"events", `{"id":1,"password":"p","token":"t"}`, `{"id":1,"password":"p"}`},
+		{"users", ``, ``},
+	} {
+		simplified, err := interceptor.Simplify(c.topic, []byte(c.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(simplified) != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.topic, c.expected, simplified)
+		}
+	}
+
+	unselected := NewInterceptor(ByTopic(map[string]gosimplifier.Simplifier{"users": users}), nil)
+	if simplified, _ := unselected.Simplify("events", []byte(`not json`)); string(simplified) != "not json" {
+		t.Errorf("Expected the payload of an unselected topic to be left as it is, got %s", simplified)
+	}
+}
+
+func TestInterceptorMessagePack(t *testing.T) {
+	users, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+	interceptor := NewInterceptor(ByTopic(map[string]gosimplifier.Simplifier{"users": users}), MessagePack)
+	payload, _ := msgpack.Marshal(map[string]interface{}{"id": "u1", "password": "p"})
+	simplified, err := interceptor.Simplify("users", payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := msgpack.Unmarshal(simplified, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 1 || result["id"] != "u1" {
+		t.Errorf("Expected only the id to be kept, got %v", result)
+	}
+}
+
+func TestInterceptorScrub(t *testing.T) {
+	users, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+	var failedTopic string
+	interceptor := NewInterceptor(ByTopic(map[string]gosimplifier.Simplifier{"users": users}), JSON,
+		WithOnError(func(topic string, err error) { failedTopic = topic }))
+
+	if _, err := interceptor.Simplify("users", []byte(`{"password":`)); err == nil || !strings.Contains(err.Error(), "topic users") {
+		t.Errorf("Expected an error naming the topic, got %v", err)
+	}
+	if failedTopic != "" {
+		t.Error("Expected Simplify not to call the error function")
+	}
+	if scrubbed := interceptor.Scrub("users", []byte(`{"password":`)); scrubbed != nil {
+		t.Errorf("Expected a payload failing to simplify to be dropped, got %s", scrubbed)
+	}
+	if failedTopic != "users" {
+		t.Errorf("Expected the error function to be called for users, got %q", failedTopic)
+	}
+	if scrubbed := interceptor.Scrub("users", []byte(`{"password":"p"}`)); string(scrubbed) != `{}` {
+		t.Errorf("Expected {}, got %s", scrubbed)
+	}
+}
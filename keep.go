@@ -0,0 +1,68 @@
+package gosimplifier
+
+// unmatchedRuler returns the ruler of a property no rule names: a removal if the rules remove
+// every property but those of except, or keep the properties of keep_properties only and name is
+// not one of them, the root rules otherwise.
+// keep_properties and the removal of every property of the root rule only apply to the root
+// value, so properties falling back to the root rules are not emptied by them.
+func (s *simplifierImpl) unmatchedRuler(name string, root *simplifierImpl) ruler {
+	if s.removesAll() && !s.excepts(name) {
+		return removeRulerSingleton
+	}
+	if len(s.rule.KeepProperties) > 0 && !root.options.containsName(s.rule.KeepProperties, name) {
+		return removeRulerSingleton
+	}
+	if root.fallback != nil {
+		return root.fallback
+	}
+	return root
+}
+
+// fallbackRules returns a copy of the root simplifier ignoring its keep_properties and its
+// removal of every property, or nil if it has neither.
+func (s *simplifierImpl) fallbackRules() *simplifierImpl {
+	removesAll := s.removesAll()
+	if len(s.rule.KeepProperties) == 0 && !removesAll {
+		return nil
+	}
+	fallback := *s
+	rule := *s.rule
+	rule.KeepProperties = nil
+	if removesAll {
+		rule.RemoveProperties = withoutName(rule.RemoveProperties, "*")
+		fallback.propertySimplifiers = make(map[string]ruler, len(s.propertySimplifiers))
+		for name, r := range s.propertySimplifiers {
+			if name != "*" {
+				fallback.propertySimplifiers[name] = r
+			}
+		}
+		fallback.globRules = nil
+		for _, glob := range s.globRules {
+			if glob.pattern != "*" {
+				fallback.globRules = append(fallback.globRules, glob)
+			}
+		}
+		if s.folded != nil {
+			fallback.folded = make(map[string]foldedRuler, len(s.folded))
+			for name, f := range s.folded {
+				if name != "*" {
+					fallback.folded[name] = f
+				}
+			}
+		}
+	}
+	fallback.rule = &rule
+	fallback.fallback = nil
+	return &fallback
+}
+
+// withoutName returns a copy of names without name.
+func withoutName(names []string, name string) []string {
+	var kept []string
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
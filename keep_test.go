@@ -0,0 +1,124 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type KeepExample struct {
+	ID     int
+	Name   string
+	Secret string
+	Info   *SubStruct
+	Labels map[string]string
+}
+
+func TestKeepProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"keep_properties": [ "ID", "Info", "Labels" ],
+		"property_simplifiers": {
+			"Info": { "keep_properties": [ "Test" ] },
+			"Labels": { "keep_properties": [ "env" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := KeepExample{
+		ID:     1,
+		Name:   "n",
+		Secret: "s",
+		Info:   &SubStruct{Test: "t", Debug: "d"},
+		Labels: map[string]string{"env": "prod", "owner": "me"},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := KeepExample{ID: 1, Info: &SubStruct{Test: "t"}, Labels: map[string]string{"env": "prod"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if original.Info.Debug != "d" || len(original.Labels) != 2 {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"ID":1,"Name":"n","Labels":{"env":"prod","owner":"me"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"ID":1,"Labels":{"env":"prod"}}` {
+		t.Errorf("Expected the JSON to keep the listed properties, got %s", data)
+	}
+
+	simplifier, err = NewSimplifier(`{ "keep_properties": [ "Info" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = KeepExample{Info: &SubStruct{Test: "t", Debug: "d"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the kept properties to be kept whole, got %+v", simplified)
+	}
+}
+
+type KeepAccount struct {
+	ID    int
+	Name  string
+	Email string
+	Info  *SubStruct
+}
+
+type KeepTeam struct {
+	Lead     KeepAccount
+	Members  []KeepAccount
+	Password string
+}
+
+func TestNestedKeepProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Password", "Debug" ],
+		"property_simplifiers": {
+			"Lead": {
+				"keep_properties": [ "ID", "Name", "Email", "Info" ],
+				"remove_properties": [ "Email" ]
+			},
+			"Members": {
+				"keep_properties": [ "ID" ],
+				"property_simplifiers": { "Info": { "remove_properties": [ "Test" ] } }
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := KeepTeam{
+		Lead:     KeepAccount{ID: 1, Name: "n", Email: "e", Info: &SubStruct{Test: "t", Debug: "d"}},
+		Members:  []KeepAccount{{ID: 2, Name: "m", Email: "f", Info: &SubStruct{Test: "u", Debug: "v"}}},
+		Password: "p",
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := KeepTeam{
+		Lead:    KeepAccount{ID: 1, Name: "n", Info: &SubStruct{Test: "t"}},
+		Members: []KeepAccount{{ID: 2, Info: &SubStruct{Debug: "v"}}},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Lead":{"ID":1,"Name":"n","Email":"e","Info":{"Test":"t","Debug":"d"}},`+
+		`"Members":[{"ID":2,"Name":"m","Info":{"Test":"u","Debug":"v"}}],"Password":"p"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Lead":{"ID":1,"Name":"n","Info":{"Test":"t"}},"Members":[{"ID":2,"Info":{"Debug":"v"}}]}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
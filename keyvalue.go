@@ -0,0 +1,92 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// KeyValueRule describes a list of key-value pairs, such as []struct{ Key, Value string } or
+// []map[string]interface{}, whose elements are matched like map entries: the rules name the
+// values of KeyField, and act on ValueField of the matching elements.
+//
+// Example, removing the value of the "password" attribute:
+//
+//	{
+//	  "property_simplifiers": {
+//	    "Attributes": {
+//	      "key_value": { "key_field": "Key", "value_field": "Value" },
+//	      "remove_properties": [ "password" ]
+//	    }
+//	  }
+//	}
+//
+// Removing a pair resets its value, like any other struct field, and keeps the key.
+type KeyValueRule struct {
+	// KeyField is the field or map key holding the key, "Key" if empty
+	KeyField string `json:"key_field"`
+	// ValueField is the field or map key holding the value, "Value" if empty
+	ValueField string `json:"value_field"`
+}
+
+func (r *KeyValueRule) fields() (string, string) {
+	keyField, valueField := r.KeyField, r.ValueField
+	if keyField == "" {
+		keyField = "Key"
+	}
+	if valueField == "" {
+		valueField = "Value"
+	}
+	return keyField, valueField
+}
+
+// applyKeyValueRules applies the rules of s to the values of the key-value pairs in list.
+func (s *simplifierImpl) applyKeyValueRules(node *Node, list reflect.Value) error {
+	keyField, valueField := s.rule.KeyValue.fields()
+	w := node.walk
+	for i := 0; i < list.Len(); i++ {
+		element := indirect(list.Index(i))
+		key, value, mapKey, ok := keyValueOf(element, keyField, valueField)
+		if !ok {
+			continue
+		}
+		subSimplifier := s.unmatchedRuler(key, w.root)
+		if _, propertySimplifier := s.keyRuler(key); propertySimplifier != nil {
+			subSimplifier = propertySimplifier
+		}
+		elementNode := node.child(list, list.Index(i), reflect.Value{}, "", i, s, s)
+		err := w.visitChild(elementNode.child(element, value, mapKey, key, -1, s, subSimplifier))
+		releaseNode(elementNode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyValueOf returns the key and the value of a key-value pair element, which is either a
+// struct or a map with string keys. mapKey is the key of the value if element is a map.
+func keyValueOf(element reflect.Value, keyField string, valueField string) (key string, value reflect.Value, mapKey reflect.Value, ok bool) {
+	var keyValue reflect.Value
+	switch element.Kind() {
+	case reflect.Struct:
+		keyValue, value = element.FieldByName(keyField), element.FieldByName(valueField)
+	case reflect.Map:
+		if element.Type().Key().Kind() != reflect.String {
+			return "", value, mapKey, false
+		}
+		mapKey = reflect.ValueOf(valueField).Convert(element.Type().Key())
+		keyValue = element.MapIndex(reflect.ValueOf(keyField).Convert(element.Type().Key()))
+		value = element.MapIndex(mapKey)
+	}
+	if !keyValue.IsValid() || !value.IsValid() {
+		return "", value, mapKey, false
+	}
+	keyValue = indirect(keyValue)
+	if keyValue.Kind() == reflect.String {
+		return keyValue.String(), value, mapKey, true
+	}
+	if !keyValue.IsValid() || !keyValue.CanInterface() {
+		return "", value, mapKey, false
+	}
+	return fmt.Sprint(keyValue.Interface()), value, mapKey, true
+}
@@ -0,0 +1,100 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+type Event struct {
+	Type       string
+	Attributes []Attribute
+}
+
+func TestKeyValueStructList(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Attributes": {
+				"key_value": { "key_field": "Name" },
+				"remove_properties": [ "password", "token" ]
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := Event{
+		Type: "login",
+		Attributes: []Attribute{
+			{Name: "user", Value: "john"},
+			{Name: "password", Value: "secret"},
+			{Name: "token", Value: "abc"},
+		},
+	}
+
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Event{
+		Type: "login",
+		Attributes: []Attribute{
+			{Name: "user", Value: "john"},
+			{Name: "password"},
+			{Name: "token"},
+		},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if original.Attributes[1].Value != "secret" {
+		t.Error("Expected the original to be unchanged")
+	}
+}
+
+func TestKeyValueMapList(t *testing.T) {
+	var paths []string
+	recorder := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Removing() {
+				paths = append(paths, node.Path())
+			}
+			return next(node)
+		}
+	}
+	simplifier, _ := NewSimplifier(`{
+		"property_simplifiers": {
+			"headers": {
+				"key_value": { "key_field": "name", "value_field": "value" },
+				"remove_properties": [ "Authorization" ]
+			}
+		}
+	}`, WithMiddleware(recorder))
+
+	simplified, err := simplifier.Simplify(map[string]interface{}{
+		"headers": []interface{}{
+			map[string]interface{}{"name": "Accept", "value": "*/*"},
+			map[string]interface{}{"name": "Authorization", "value": "Bearer abc"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"headers": []interface{}{
+			map[string]interface{}{"name": "Accept", "value": "*/*"},
+			map[string]interface{}{"name": "Authorization"},
+		},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	if !reflect.DeepEqual(paths, []string{"headers[1].Authorization"}) {
+		t.Errorf("Unexpected removed paths %v", paths)
+	}
+}
@@ -0,0 +1,58 @@
+package gosimplifier
+
+import (
+	"math/big"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// leafTypes is never modified once stored: RegisterLeafType stores a modified copy, so that
+	// isLeafType, which runs for every copied and walked value, reads it without locking.
+	leafTypes   atomic.Pointer[map[reflect.Type]bool]
+	leafTypesMu sync.Mutex
+)
+
+func init() {
+	leafTypes.Store(&map[reflect.Type]bool{
+		reflect.TypeOf(time.Time{}):     true,
+		reflect.TypeOf(big.Int{}):       true,
+		reflect.TypeOf(big.Float{}):     true,
+		reflect.TypeOf(big.Rat{}):       true,
+		reflect.TypeOf(time.Location{}): true,
+	})
+}
+
+// RegisterLeafType makes the values of t opaque to simplifiers: they are copied wholesale, as
+// the values of a basic type, and never walked into, so their internals are neither changed nor
+// matched against the rules. A leaf can still be removed, masked or transformed as a whole.
+// Pointers to t are followed as usual, so registering time.Time covers *time.Time as well.
+// time.Time, time.Location, big.Int, big.Float and big.Rat are leaf types by default; types of
+// other modules, such as UUIDs, can be registered by the programs using them.
+//
+// Simplifiers cache what they know of a type, so RegisterLeafType is meant to be called from
+// init functions, before any simplifier is created. It panics if t is nil.
+func RegisterLeafType(t reflect.Type) {
+	if t == nil {
+		panic("gosimplifier: RegisterLeafType with a nil type")
+	}
+	leafTypesMu.Lock()
+	defer leafTypesMu.Unlock()
+	current := *leafTypes.Load()
+	if current[t] {
+		return
+	}
+	registered := make(map[reflect.Type]bool, len(current)+1)
+	for leaf := range current {
+		registered[leaf] = true
+	}
+	registered[t] = true
+	leafTypes.Store(&registered)
+}
+
+// isLeafType reports whether t was registered with RegisterLeafType.
+func isLeafType(t reflect.Type) bool {
+	return (*leafTypes.Load())[t]
+}
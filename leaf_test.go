@@ -0,0 +1,85 @@
+package gosimplifier
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type leafMoney struct {
+	Currency string
+	Cents    int64
+}
+
+// leafID stands for the UUID types of other modules, arrays registered as leaves.
+type leafID [16]byte
+
+type leafOrder struct {
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt *time.Time
+	Amount    *big.Int
+	ID        leafID
+	Price     leafMoney
+}
+
+func init() {
+	RegisterLeafType(reflect.TypeOf(leafMoney{}))
+	RegisterLeafType(reflect.TypeOf(leafID{}))
+}
+
+func TestLeafTypes(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	original := &leafOrder{
+		Name:      "order",
+		CreatedAt: time.Now(),
+		UpdatedAt: &updatedAt,
+		Amount:    new(big.Int).Lsh(big.NewInt(1), 100),
+		ID:        leafID{0x9f, 0x3c, 0x8a, 0x52},
+		Price:     leafMoney{Currency: "EUR", Cents: 1250},
+	}
+	var walked []string
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Name" ],
+		"property_simplifiers": { "Price": { "remove_properties": [ "Cents" ] } }
+	}`, WithMiddleware(func(next Walker) Walker {
+		return func(node *Node) error {
+			walked = append(walked, node.Path())
+			return next(node)
+		}
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := result.(*leafOrder)
+	if order.Name != "" {
+		t.Errorf("Expected Name to be removed, got %q", order.Name)
+	}
+	if !order.CreatedAt.Equal(original.CreatedAt) || order.UpdatedAt.Location() != time.UTC || !order.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Expected the times to be kept, got %v and %v", order.CreatedAt, order.UpdatedAt)
+	}
+	if order.Amount.Cmp(original.Amount) != 0 || order.ID != original.ID {
+		t.Errorf("Expected Amount and ID to be kept, got %v and %v", order.Amount, order.ID)
+	}
+	if order.Price != original.Price {
+		t.Errorf("Expected the leaf Price to be kept whole, got %+v", order.Price)
+	}
+	expected := []string{"", "Name", "CreatedAt", "UpdatedAt", "Amount", "ID", "Price"}
+	if !reflect.DeepEqual(walked, expected) {
+		t.Errorf("Expected the walk to stop at leaves, got %q", walked)
+	}
+}
+
+func TestRegisterLeafTypeNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected RegisterLeafType(nil) to panic")
+		}
+	}()
+	RegisterLeafType(nil)
+}
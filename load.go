@@ -0,0 +1,36 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// NewSimplifierFromReader creates a Simplifier from the JSON rules read from r until EOF, see
+// NewSimplifier.
+func NewSimplifierFromReader(r io.Reader, opts ...Option) (Simplifier, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewSimplifier(string(data), opts...)
+}
+
+// NewSimplifierFromFS creates a Simplifier from the JSON rules of the file at path in fsys,
+// e.g. an embed.FS:
+//
+//	//go:embed rules
+//	var rulesFS embed.FS
+//
+//	simplifier, err := gosimplifier.NewSimplifierFromFS(rulesFS, "rules/public_api.json")
+func NewSimplifierFromFS(fsys fs.FS, path string, opts ...Option) (Simplifier, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := NewSimplifier(string(data), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
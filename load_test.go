@@ -0,0 +1,49 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestNewSimplifierFromReader(t *testing.T) {
+	simplifier, err := NewSimplifierFromReader(strings.NewReader(`{ "remove_properties": [ "Debug" ] }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(ExampleStruct0{Test: 1, Debug: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, ExampleStruct0{Test: 1}) {
+		t.Errorf("Expected Debug to be removed, got %+v", simplified)
+	}
+	if _, err := NewSimplifierFromReader(strings.NewReader(`{`)); err == nil {
+		t.Error("Expected an error for invalid rules")
+	}
+}
+
+func TestNewSimplifierFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"rules/public.json":  {Data: []byte(`{ "remove_properties": [ "Debug" ] }`)},
+		"rules/invalid.json": {Data: []byte(`{ "remove_properties": "Debug" }`)},
+	}
+	simplifier, err := NewSimplifierFromFS(fsys, "rules/public.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(ExampleStruct0{Test: 1, Debug: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, ExampleStruct0{Test: 1}) {
+		t.Errorf("Expected Debug to be removed, got %+v", simplified)
+	}
+	if _, err := NewSimplifierFromFS(fsys, "rules/missing.json"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+	if _, err := NewSimplifierFromFS(fsys, "rules/invalid.json"); err == nil || !strings.Contains(err.Error(), "rules/invalid.json") {
+		t.Errorf("Expected an error naming the file, got %v", err)
+	}
+}
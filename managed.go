@@ -0,0 +1,247 @@
+package gosimplifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotModified is returned by a RuleProvider when the rules did not change since the version
+// the caller has.
+var ErrNotModified = errors.New("rules not modified")
+
+// RuleProvider supplies centrally managed rules, such as those of a config service, see
+// ManagedSimplifier.
+type RuleProvider interface {
+	// Fetch returns the JSON rules and their version, e.g. an ETag. version is the version the
+	// caller has, or "" if it has none; when the rules are still at that version, Fetch may
+	// return ErrNotModified instead of the rules.
+	Fetch(ctx context.Context, version string) (rules []byte, newVersion string, err error)
+}
+
+// RuleProviderFunc adapts a function to the RuleProvider interface.
+type RuleProviderFunc func(ctx context.Context, version string) ([]byte, string, error)
+
+// Fetch calls f.
+func (f RuleProviderFunc) Fetch(ctx context.Context, version string) ([]byte, string, error) {
+	return f(ctx, version)
+}
+
+// FileRuleProvider provides the rules of the JSON file at path, versioned by the hash of its
+// content.
+func FileRuleProvider(path string) RuleProvider {
+	return RuleProviderFunc(func(_ context.Context, version string) ([]byte, string, error) {
+		rules, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		sum := sha256.Sum256(rules)
+		if newVersion := hex.EncodeToString(sum[:]); newVersion != version {
+			return rules, newVersion, nil
+		}
+		return nil, "", ErrNotModified
+	})
+}
+
+// FallbackRuleProvider provides the rules of primary, or the fallback JSON rules while primary
+// fails and the caller has no rules yet, so a service can start while the rule source is down.
+// The version of the fallback rules is "fallback".
+func FallbackRuleProvider(primary RuleProvider, fallback string) RuleProvider {
+	return RuleProviderFunc(func(ctx context.Context, version string) ([]byte, string, error) {
+		rules, newVersion, err := primary.Fetch(ctx, version)
+		if err != nil && version == "" && !errors.Is(err, ErrNotModified) {
+			return []byte(fallback), "fallback", nil
+		}
+		return rules, newVersion, err
+	})
+}
+
+// ManagedSimplifier is a Simplifier whose rules are fetched from a RuleProvider, and fetched
+// again periodically, so a fleet of services can pull centrally managed rules.
+//
+// Every call uses the rules current when it starts, and new versions are swapped in atomically.
+// Rules that fail to be fetched or compiled are not swapped in: the previous rules stay in use
+// and Err returns the failure until a later fetch succeeds.
+type ManagedSimplifier struct {
+	provider RuleProvider
+	opts     []Option
+	current  atomic.Pointer[simplifierImpl]
+	// mu guards version and err; it is not held while the rules are fetched
+	mu      sync.Mutex
+	version string
+	err     error
+	// ctx is the context of the fetches, which Close cancels
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewManagedSimplifier fetches the rules of provider, compiled with the options, and fetches
+// them again every interval until Close is called. It fails if the rules cannot be loaded
+// initially, see FallbackRuleProvider.
+func NewManagedSimplifier(provider RuleProvider, interval time.Duration, opts ...Option) (*ManagedSimplifier, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("NewManagedSimplifier requires a positive interval, got %s", interval)
+	}
+	m := &ManagedSimplifier{provider: provider, opts: opts, stop: make(chan struct{}), done: make(chan struct{})}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	if err := m.Reload(); err != nil {
+		m.cancel()
+		return nil, err
+	}
+	go m.poll(interval)
+	return m, nil
+}
+
+func (m *ManagedSimplifier) poll(interval time.Duration) {
+	defer close(m.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			_ = m.Reload()
+		}
+	}
+}
+
+// Reload fetches the rules now, swapping them in if they changed, and returns the error of
+// loading them, which Err returns as well. Fetches still running when Close is called are
+// canceled, and later ones fail.
+func (m *ManagedSimplifier) Reload() error {
+	m.mu.Lock()
+	current := m.version
+	m.mu.Unlock()
+	rules, version, err := m.provider.Fetch(m.ctx, current)
+	var s Simplifier
+	if err == nil {
+		s, err = NewSimplifier(string(rules), m.opts...)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.version != current {
+		// a concurrent reload swapped in newer rules while these were fetched
+		return m.err
+	}
+	if errors.Is(err, ErrNotModified) && m.current.Load() != nil {
+		m.err = nil
+		return nil
+	}
+	if err != nil {
+		m.err = fmt.Errorf("load rules: %w", err)
+		return m.err
+	}
+	m.current.Store(s.(*simplifierImpl))
+	m.version, m.err = version, nil
+	return nil
+}
+
+// Version returns the version of the rules in use.
+func (m *ManagedSimplifier) Version() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.version
+}
+
+// Err returns the error of the last reload, or nil if the rules in use are the latest.
+func (m *ManagedSimplifier) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// Close stops fetching the rules, canceling the fetch in progress. The rules in use remain
+// usable.
+func (m *ManagedSimplifier) Close() error {
+	m.cancel()
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	<-m.done
+	return nil
+}
+
+// Simplify calls Simplify of the rules in use.
+func (m *ManagedSimplifier) Simplify(original interface{}) (interface{}, error) {
+	return m.current.Load().Simplify(original)
+}
+
+// SimplifyContext calls SimplifyContext of the rules in use.
+func (m *ManagedSimplifier) SimplifyContext(ctx context.Context, original interface{}) (interface{}, error) {
+	return m.current.Load().SimplifyContext(ctx, original)
+}
+
+// SimplifyInPlace calls SimplifyInPlace of the rules in use.
+func (m *ManagedSimplifier) SimplifyInPlace(ptr interface{}) error {
+	return m.current.Load().SimplifyInPlace(ptr)
+}
+
+// SimplifyInto calls SimplifyInto of the rules in use.
+func (m *ManagedSimplifier) SimplifyInto(original interface{}, dst interface{}) error {
+	return m.current.Load().SimplifyInto(original, dst)
+}
+
+// SimplifyJSON calls SimplifyJSON of the rules in use.
+func (m *ManagedSimplifier) SimplifyJSON(data []byte) ([]byte, error) {
+	return m.current.Load().SimplifyJSON(data)
+}
+
+// DryRun calls DryRun of the rules in use.
+func (m *ManagedSimplifier) DryRun(original interface{}) (*Report, error) {
+	return m.current.Load().DryRun(original)
+}
+
+func (m *ManagedSimplifier) dryRun(original interface{}) (interface{}, *Report, error) {
+	return m.current.Load().dryRun(original)
+}
+
+// SimplifyWithAudit calls SimplifyWithAudit of the rules in use.
+func (m *ManagedSimplifier) SimplifyWithAudit(original interface{}) (interface{}, []string, error) {
+	return m.current.Load().SimplifyWithAudit(original)
+}
+
+// SimplifyToMap calls SimplifyToMap of the rules in use.
+func (m *ManagedSimplifier) SimplifyToMap(original interface{}) (map[string]interface{}, error) {
+	return m.current.Load().SimplifyToMap(original)
+}
+
+// ValidateForType calls ValidateForType of the rules in use.
+func (m *ManagedSimplifier) ValidateForType(t reflect.Type) error {
+	return m.current.Load().ValidateForType(t)
+}
+
+// DetectDrift calls DetectDrift of the rules in use.
+func (m *ManagedSimplifier) DetectDrift(t reflect.Type) []Warning {
+	return m.current.Load().DetectDrift(t)
+}
+
+func (m *ManagedSimplifier) mapRoot() *simplifierImpl {
+	return m.current.Load()
+}
+
+// Extend extends the rules in use, see ExtendSimplifier. The extended rules are not reloaded.
+func (m *ManagedSimplifier) Extend(rule *Rule) (Simplifier, error) {
+	return m.current.Load().Extend(rule)
+}
+
+// Clone returns a simplifier with the rules in use, which are not reloaded.
+func (m *ManagedSimplifier) Clone() Simplifier {
+	return m.current.Load().Clone()
+}
+
+// Rules calls Rules of the rules in use.
+func (m *ManagedSimplifier) Rules() *Rule {
+	return m.current.Load().Rules()
+}
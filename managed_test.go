@@ -0,0 +1,106 @@
+package gosimplifier
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManagedSimplifier(t *testing.T) {
+	rules, version := `{ "remove_properties": [ "Debug" ] }`, "1"
+	fail := false
+	var versions []string
+	provider := RuleProviderFunc(func(_ context.Context, current string) ([]byte, string, error) {
+		versions = append(versions, current)
+		if fail {
+			return nil, "", errors.New("unavailable")
+		}
+		if current == version {
+			return nil, "", ErrNotModified
+		}
+		return []byte(rules), version, nil
+	})
+	simplifier, err := NewManagedSimplifier(provider, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer simplifier.Close()
+	original := ExampleStruct0{Test: 1, Debug: "d"}
+	if simplified, err := simplifier.Simplify(original); err != nil || !reflect.DeepEqual(simplified, ExampleStruct0{Test: 1}) {
+		t.Errorf("Expected Debug to be removed, got %+v, %v", simplified, err)
+	}
+
+	if err := simplifier.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	rules, version = `{ "remove_properties": [ "Test" ] }`, "2"
+	if err := simplifier.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(versions, []string{"", "1", "1"}) || simplifier.Version() != "2" {
+		t.Errorf("Expected the version in use to be passed to the provider, got %v", versions)
+	}
+
+	fail = true
+	if err := simplifier.Reload(); err == nil || simplifier.Err() == nil {
+		t.Error("Expected the failure to be reported")
+	}
+	if simplified, err := simplifier.Simplify(original); err != nil || !reflect.DeepEqual(simplified, ExampleStruct0{Debug: "d"}) {
+		t.Errorf("Expected the previous rules to stay in use, got %+v, %v", simplified, err)
+	}
+}
+
+func TestFallbackRuleProvider(t *testing.T) {
+	failing := RuleProviderFunc(func(context.Context, string) ([]byte, string, error) {
+		return nil, "", errors.New("unavailable")
+	})
+	if _, err := NewManagedSimplifier(failing, time.Hour); err == nil {
+		t.Error("Expected an error when no rules can be loaded")
+	}
+	simplifier, err := NewManagedSimplifier(FallbackRuleProvider(failing, `{ "remove_properties": [ "Debug" ] }`), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer simplifier.Close()
+	if simplifier.Version() != "fallback" {
+		t.Errorf("Expected the fallback rules, got version %s", simplifier.Version())
+	}
+	if err := simplifier.Reload(); err == nil {
+		t.Error("Expected the failure to be reported once the fallback rules are in use")
+	}
+}
+
+func TestManagedSimplifierCloseCancelsFetch(t *testing.T) {
+	fetching := make(chan struct{}, 1)
+	provider := RuleProviderFunc(func(ctx context.Context, current string) ([]byte, string, error) {
+		if current == "" {
+			return []byte(`{}`), "1", nil
+		}
+		select {
+		case fetching <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return nil, "", ctx.Err()
+	})
+	simplifier, err := NewManagedSimplifier(provider, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-fetching
+	if simplifier.Version() != "1" || simplifier.Err() != nil {
+		t.Error("Expected the state to be readable while the rules are fetched")
+	}
+	closed := make(chan struct{})
+	go func() {
+		simplifier.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected Close to cancel the fetch in progress")
+	}
+}
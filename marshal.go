@@ -0,0 +1,47 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SimplifiedValue is a value that is simplified when it is marshaled, see Simplified.
+type SimplifiedValue struct {
+	value      interface{}
+	simplifier Simplifier
+}
+
+// Simplified wraps v so that encoding it with encoding/json, e.g. in a log or a response body,
+// encodes the copy simplified by s instead, without the caller invoking Simplify:
+//
+//	logger.Info("request", "body", gosimplifier.Simplified(req, simplifier))
+//
+// Formatting it with fmt prints the same JSON, so the original cannot leak through %v either.
+func Simplified(v interface{}, s Simplifier) SimplifiedValue {
+	return SimplifiedValue{value: v, simplifier: s}
+}
+
+// MarshalJSON encodes the simplified copy of the value. It fails if the value cannot be
+// simplified, rather than encoding the original; with WithBestEffort the partial result is
+// encoded.
+func (v SimplifiedValue) MarshalJSON() ([]byte, error) {
+	simplified, err := v.simplifier.Simplify(v.value)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	return json.Marshal(simplified)
+}
+
+// String returns the JSON encoding of the simplified copy, or the error preventing it.
+func (v SimplifiedValue) String() string {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return fmt.Sprintf("!(gosimplifier: %v)", err)
+	}
+	return string(data)
+}
+
+// GoString is String, so %#v does not print the original either.
+func (v SimplifiedValue) GoString() string {
+	return v.String()
+}
@@ -0,0 +1,37 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSimplified(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := SubStruct{Test: "t", Debug: "secret"}
+
+	data, err := json.Marshal(map[string]interface{}{"body": Simplified(original, simplifier)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"body":{"Test":"t","Debug":""}}`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		if printed := fmt.Sprintf(format, Simplified(original, simplifier)); strings.Contains(printed, "secret") {
+			t.Errorf("Expected %s not to print the original, got %s", format, printed)
+		}
+	}
+
+	strict, err := NewSimplifier(`{ "remove_properties": [ "Unknown" ] }`, WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(Simplified(original, strict)); err == nil {
+		t.Error("Expected the failure to simplify to fail the encoding")
+	}
+}
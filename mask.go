@@ -0,0 +1,75 @@
+package gosimplifier
+
+import "reflect"
+
+// DefaultMask is the mask used by mask_properties unless MaskRule.Mask is set.
+const DefaultMask = "****"
+
+// MaskRule configures how the mask_properties of the same rule are masked.
+//
+// Example, keeping the last 4 digits of a card number:
+//
+//	{
+//	  "mask_properties": [ "CardNumber" ],
+//	  "mask": { "mask": "**** **** **** ", "keep_last": 4 }
+//	}
+type MaskRule struct {
+	// Mask replaces the value, DefaultMask if empty
+	Mask string `json:"mask,omitempty"`
+	// KeepLast is the number of trailing characters of the value kept after the mask.
+	// Values that are not longer than KeepLast are masked entirely.
+	KeepLast int `json:"keep_last,omitempty"`
+}
+
+// maskRuler replaces string values with their mask, so consumers can see that a value existed.
+type maskRuler struct {
+	rule *MaskRule
+}
+
+// mask returns the masked value. Empty values stay empty, as there is nothing to hide.
+func (r *MaskRule) mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	mask, keepLast := DefaultMask, 0
+	if r != nil {
+		if r.Mask != "" {
+			mask = r.Mask
+		}
+		keepLast = r.KeepLast
+	}
+	runes := []rune(value)
+	if keepLast <= 0 || len(runes) <= keepLast {
+		return mask
+	}
+	return mask + string(runes[len(runes)-keepLast:])
+}
+
+// apply masks string values. Values of other kinds are removed instead, as they cannot hold
+// the mask.
+func (r *maskRuler) apply(node *Node) error {
+	value := indirect(node.Value)
+	if !value.IsValid() || node.Parent.Kind() == reflect.Invalid {
+		return nil
+	}
+	if value.Kind() != reflect.String {
+		return removeRulerSingleton.apply(node)
+	}
+	masked := r.rule.mask(value.String())
+	if value.CanSet() {
+		value.SetString(masked)
+		return nil
+	}
+	replacement := reflect.ValueOf(masked)
+	if node.Value.Kind() == reflect.String {
+		replacement = replacement.Convert(node.Value.Type())
+	}
+	if !node.set(replacement) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *maskRuler) action() string {
+	return "mask"
+}
@@ -0,0 +1,70 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Card struct {
+	Holder string
+	Number string
+	CVC    string
+	Expiry int
+}
+
+func TestMaskProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"mask_properties": [ "Number", "CVC", "Expiry", "token", "empty" ],
+		"mask": { "mask": "####", "keep_last": 4 }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := Card{Holder: "john", Number: "4111111111111111", CVC: "123", Expiry: 1224}
+	simplified, err := simplifier.Simplify(&original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	card := simplified.(*Card)
+	if card.Holder != "john" || card.Number != "####1111" || card.CVC != "####" || card.Expiry != 0 {
+		t.Errorf("Unexpected result %+v", card)
+	}
+	if original.Number != "4111111111111111" {
+		t.Error("Expected the original to be unchanged")
+	}
+
+	simplified, _ = simplifier.Simplify(map[string]interface{}{"token": "abcdefgh", "empty": "x", "other": "y"})
+	expected := map[string]interface{}{"token": "####efgh", "empty": "####", "other": "y"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+
+	token := "abcdefgh"
+	simplified, _ = simplifier.Simplify(map[string]*string{"token": &token})
+	if masked := *simplified.(map[string]*string)["token"]; masked != "####efgh" || token != "abcdefgh" {
+		t.Errorf("Expected the pointed to copy to be masked, got %q", masked)
+	}
+}
+
+func TestMaskDefault(t *testing.T) {
+	var rule *MaskRule
+	if masked := rule.mask("secret"); masked != DefaultMask {
+		t.Errorf("Expected the default mask, got %q", masked)
+	}
+	if masked := (&MaskRule{KeepLast: 2}).mask("日本語"); masked != DefaultMask+"本語" {
+		t.Errorf("Expected the last 2 characters to be kept, got %q", masked)
+	}
+	if masked := rule.mask(""); masked != "" {
+		t.Errorf("Expected empty values to stay empty, got %q", masked)
+	}
+}
+
+func TestMaskValidation(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "mask_properties": [ "Number", "Expiry" ] }`)
+	err := ValidateForType(simplifier, reflect.TypeOf(Card{}))
+	if err == nil || !strings.Contains(err.Error(), "Expiry: the mask action on a property of type int removes it instead") {
+		t.Errorf("Expected Expiry to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// limitElements truncates the list to the first max_items elements, returning the elements to
+// walk and the number of elements dropped. kept holds the original index of each element if
+// remove_if filtered the list, see filterElements, so the dropped elements are visited at their
+// original index. Arrays cannot shrink, so their elements past the limit are reset instead.
+func (s *simplifierImpl) limitElements(node *Node, list reflect.Value, kept []int) (reflect.Value, int, error) {
+	max := s.rule.MaxItems
+	if max == 0 || list.Len() <= max {
+		return list, 0, nil
+	}
+	dropped := list.Len() - max
+	if list.Kind() == reflect.Array {
+		for i := max; i < list.Len(); i++ {
+			if !list.Index(i).CanSet() {
+				return list, 0, nil
+			}
+		}
+		if err := s.visitTruncated(node, list, kept); err != nil {
+			return list, 0, err
+		}
+		for i := max; i < list.Len(); i++ {
+			list.Index(i).Set(reflect.Zero(list.Type().Elem()))
+		}
+		return list, 0, nil
+	}
+	// the slice may be read through the node's value, which truncated replaces
+	list = list.Slice(0, list.Len())
+	// the capacity is cut as well, so appending the marker does not write into the dropped elements
+	truncated := list.Slice3(0, max, max)
+	if !node.setIndirect(truncated) {
+		return list, 0, nil
+	}
+	return truncated, dropped, s.visitTruncated(node, list, kept)
+}
+
+// visitTruncated visits the elements of the list past max_items as removed nodes, see
+// visitDropped.
+func (s *simplifierImpl) visitTruncated(node *Node, list reflect.Value, kept []int) error {
+	if !node.walk.observesDrops() {
+		return nil
+	}
+	for i := s.rule.MaxItems; i < list.Len(); i++ {
+		index := i
+		if kept != nil {
+			index = kept[i]
+		}
+		if err := s.visitDropped(node, list.Index(i), index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendMoreItems appends the more_items marker, counting the dropped elements, to a list
+// truncated by max_items, if its elements can hold a string.
+func (s *simplifierImpl) appendMoreItems(node *Node, list reflect.Value, dropped int) {
+	if dropped == 0 || s.rule.MoreItems == "" {
+		return
+	}
+	marker := reflect.ValueOf(moreItems(s.rule.MoreItems, dropped))
+	elemType := list.Type().Elem()
+	switch {
+	case elemType.Kind() == reflect.String:
+		marker = marker.Convert(elemType)
+	case !marker.Type().AssignableTo(elemType):
+		return
+	}
+	node.setIndirect(reflect.Append(list, marker))
+}
+
+// moreItems formats the marker, replacing %d with the number of dropped elements.
+func moreItems(format string, dropped int) string {
+	return strings.ReplaceAll(format, "%d", fmt.Sprint(dropped))
+}
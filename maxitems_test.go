@@ -0,0 +1,111 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type MaxItemsExample struct {
+	Tags   []string
+	Items  []*SubStruct
+	Scores [3]int
+	Other  []int
+}
+
+func TestMaxItems(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Tags": { "max_items": 2, "more_items": "and %d more" },
+			"Items": { "max_items": 1, "remove_properties": [ "Debug" ] },
+			"Scores": { "max_items": 1 }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := MaxItemsExample{
+		Tags:   []string{"a", "b", "c", "d"},
+		Items:  []*SubStruct{{Test: "t", Debug: "d"}, {Test: "u"}},
+		Scores: [3]int{1, 2, 3},
+		Other:  []int{1, 2, 3},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MaxItemsExample{
+		Tags:   []string{"a", "b", "and 2 more"},
+		Items:  []*SubStruct{{Test: "t"}},
+		Scores: [3]int{1},
+		Other:  []int{1, 2, 3},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if len(original.Tags) != 4 || original.Tags[2] != "c" || original.Items[0].Debug != "d" {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"Tags":["a","b","c"],"Items":[{"Test":"t"},{"Test":"u"}],"Other":[1,2,3]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Items":[{"Test":"t"}],"Other":[1,2,3],"Tags":["a","b","and 1 more"]}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	simplifier, err = NewSimplifier(`{ "max_items": 2, "property_simplifiers": { "b": { "max_items": 3 } } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = simplifier.Simplify(map[string]interface{}{
+		"a": []interface{}{1, 2, 3, 4},
+		"b": []interface{}{1, 2, 3, 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": []interface{}{1, 2}, "b": []interface{}{1, 2, 3}}
+	if !reflect.DeepEqual(simplified, want) {
+		t.Errorf("Expected the root max_items to limit the lists no rule names, got %v", simplified)
+	}
+
+	if _, err := NewSimplifier(`{ "max_items": -1 }`); err == nil {
+		t.Error("Expected a negative max_items to be rejected")
+	}
+}
+
+func TestMaxItemsReportsDroppedElements(t *testing.T) {
+	rules := `{
+		"property_simplifiers": {
+			"Scores": { "max_items": 2 },
+			"Entities": { "remove_if": { "field": "Type", "equals": "debug" }, "max_items": 1 }
+		}
+	}`
+	original := struct {
+		Scores   [3]int
+		Entities []TypedEntity
+	}{
+		Scores:   [3]int{1, 2, 3},
+		Entities: []TypedEntity{{"debug", "a"}, {"user", "b"}, {"debug", "c"}, {"admin", "d"}},
+	}
+	expected := []string{"Scores[2]", "Entities[0]", "Entities[2]", "Entities[3]"}
+
+	simplifier, _ := NewSimplifier(rules)
+	_, removed, err := SimplifyWithAudit(simplifier, original)
+	if err != nil || !reflect.DeepEqual(removed, expected) {
+		t.Errorf("Expected the audit to list %v, got %v, %v", expected, removed, err)
+	}
+
+	var events []string
+	simplifier, _ = NewSimplifier(rules, WithOnRemove(func(event RemovalEvent) {
+		events = append(events, event.Path)
+	}))
+	simplified, err := simplifier.Simplify(original)
+	if err != nil || !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected OnRemove for %v, got %v, %v", expected, events, err)
+	}
+	if entities := reflect.ValueOf(simplified).Field(1).Interface(); !reflect.DeepEqual(entities, []TypedEntity{{"user", "b"}}) {
+		t.Errorf("Unexpected elements %v", entities)
+	}
+}
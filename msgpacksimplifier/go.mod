@@ -0,0 +1,14 @@
+module github.com/xhinliang/gosimplifier/msgpacksimplifier
+
+go 1.23.0
+
+require (
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	github.com/xhinliang/gosimplifier v0.1.0
+)
+
+require (
+	github.com/stretchr/testify v1.8.4 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
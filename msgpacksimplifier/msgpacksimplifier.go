@@ -0,0 +1,49 @@
+// Package msgpacksimplifier connects gosimplifier to MessagePack, simplifying encoded payloads
+// without a round trip through JSON.
+package msgpacksimplifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// SimplifyMsgpack decodes the MessagePack payload, applies the rules to it, matching the rule
+// names against the map keys, and returns it encoded again. Maps must have string keys, and are
+// encoded with their keys sorted so the output is reproducible. Values keep their MessagePack
+// types, timestamps included, and map entries whose value is nil are dropped like zero map
+// values, as with gosimplifier.SimplifyJSON. Partial results of WithBestEffort simplifiers are
+// returned with their *gosimplifier.PartialError.
+func SimplifyMsgpack(s gosimplifier.Simplifier, data []byte) ([]byte, error) {
+	return SimplifyMsgpackContext(context.Background(), s, data)
+}
+
+// SimplifyMsgpackContext is SimplifyMsgpack, giving up with the error of ctx once it is done.
+func SimplifyMsgpackContext(ctx context.Context, s gosimplifier.Simplifier, data []byte) ([]byte, error) {
+	reader := bytes.NewReader(data)
+	decoder := msgpack.NewDecoder(reader)
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if reader.Len() > 0 {
+		return nil, fmt.Errorf("invalid MessagePack: unexpected data after the top-level value")
+	}
+	simplified, err := gosimplifier.SimplifyContext(ctx, s, document)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	var out bytes.Buffer
+	encoder := msgpack.NewEncoder(&out)
+	encoder.SetSortMapKeys(true)
+	if encodeErr := encoder.Encode(simplified); encodeErr != nil {
+		return nil, encodeErr
+	}
+	return out.Bytes(), err
+}
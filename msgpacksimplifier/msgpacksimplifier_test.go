@@ -0,0 +1,90 @@
+package msgpacksimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestSimplifyMsgpack(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{
+		"remove_properties": [ "password" ],
+		"property_simplifiers": { "items": { "mask_properties": [ "card" ] } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	data, err := msgpack.Marshal(map[string]interface{}{
+		"id":       int64(42),
+		"password": "hunter2",
+		"at":       at,
+		"items":    []interface{}{map[string]interface{}{"card": "4111", "qty": int8(2)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := SimplifyMsgpack(simplifier, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := msgpack.Unmarshal(simplified, &result); err != nil {
+		t.Fatal(err)
+	}
+	if decodedAt, ok := result["at"].(time.Time); !ok || !decodedAt.Equal(at) {
+		t.Errorf("Expected the timestamp to be kept, got %v", result["at"])
+	}
+	delete(result, "at")
+	expected := map[string]interface{}{
+		"id":    int64(42),
+		"items": []interface{}{map[string]interface{}{"card": "****", "qty": int8(2)}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, result)
+	}
+
+	again, err := SimplifyMsgpack(simplifier, data)
+	if err != nil || string(again) != string(simplified) {
+		t.Errorf("Expected a reproducible output, got %v", err)
+	}
+}
+
+func TestSimplifyMsgpackErrors(t *testing.T) {
+	simplifier, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+	data, _ := msgpack.Marshal(map[string]interface{}{"id": 1})
+	if _, err := SimplifyMsgpack(simplifier, data[:len(data)-1]); err == nil {
+		t.Error("Expected a truncated payload to fail")
+	}
+	if _, err := SimplifyMsgpack(simplifier, append(data, data...)); err == nil {
+		t.Error("Expected trailing data to fail")
+	}
+	nonStringKeys, _ := msgpack.Marshal(map[int]string{1: "a"})
+	if _, err := SimplifyMsgpack(simplifier, nonStringKeys); err == nil {
+		t.Error("Expected a map with non-string keys to fail")
+	}
+}
+
+func TestSimplifyMsgpackPartial(t *testing.T) {
+	simplifier, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`,
+		gosimplifier.WithBestEffort(),
+		gosimplifier.WithMiddleware(func(next gosimplifier.Walker) gosimplifier.Walker {
+			return func(node *gosimplifier.Node) error {
+				if node.Name() == "broken" {
+					return errors.New("boom")
+				}
+				return next(node)
+			}
+		}))
+	data, _ := msgpack.Marshal(map[string]interface{}{"password": "x", "broken": "y", "id": "z"})
+	simplified, err := SimplifyMsgpack(simplifier, data)
+	var partial *gosimplifier.PartialError
+	if !errors.As(err, &partial) || simplified == nil {
+		t.Fatalf("Expected a partial result, got %v", err)
+	}
+}
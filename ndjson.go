@@ -0,0 +1,97 @@
+package gosimplifier
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// NDJSONConfig configures SimplifyNDJSON.
+type NDJSONConfig struct {
+	// Concurrency is the number of records simplified in parallel, 1 if not positive.
+	// The output keeps the order of the input regardless.
+	Concurrency int
+	// OnError is called for a record that failed to simplify, with its 1-based line number.
+	// Returning nil skips the record, returning an error aborts SimplifyNDJSON with it.
+	// If OnError is nil, the first failure aborts.
+	OnError func(line int, record []byte, err error) error
+}
+
+// SimplifyNDJSON reads newline-delimited JSON records from r, simplifies each with
+// SimplifyJSON, and writes them to w, one record per line. Blank lines are skipped.
+// Partial results of WithBestEffort simplifiers are written as they are.
+func SimplifyNDJSON(s Simplifier, r io.Reader, w io.Writer, config NDJSONConfig) error {
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	onError := config.OnError
+	if onError == nil {
+		onError = func(line int, record []byte, err error) error {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	type result struct {
+		line   int
+		record []byte
+		output []byte
+		err    error
+	}
+	// results holds a channel per record in input order; its capacity bounds the records in flight
+	results := make(chan chan result, concurrency)
+	done := make(chan struct{})
+	defer close(done)
+
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(results)
+		reader := bufio.NewReader(r)
+		for line := 1; ; line++ {
+			record, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(record)) > 0 {
+				pending := make(chan result, 1)
+				select {
+				case results <- pending:
+				case <-done:
+					return
+				}
+				go func(line int, record []byte) {
+					output, simplifyErr := SimplifyJSON(s, record)
+					if simplifyErr != nil && isPartial(simplifyErr) {
+						simplifyErr = nil
+					}
+					pending <- result{line: line, record: record, output: output, err: simplifyErr}
+				}(line, bytes.TrimSpace(record))
+			}
+			if err != nil {
+				if err != io.EOF {
+					readErr <- err
+				}
+				return
+			}
+		}
+	}()
+
+	writer := bufio.NewWriter(w)
+	for pending := range results {
+		res := <-pending
+		if res.err != nil {
+			if err := onError(res.line, res.record, res.err); err != nil {
+				return err
+			}
+			continue
+		}
+		writer.Write(res.output)
+		if err := writer.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	select {
+	case err := <-readErr:
+		return err
+	default:
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,74 @@
+package gosimplifier
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSimplifyNDJSON(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+
+	var input strings.Builder
+	var expected strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&input, `{"id":%d,"password":"secret"}`+"\n", i)
+		fmt.Fprintf(&expected, `{"id":%d}`+"\n", i)
+		if i%10 == 0 {
+			input.WriteString("\n")
+		}
+	}
+
+	for _, concurrency := range []int{0, 1, 8} {
+		var output bytes.Buffer
+		err := SimplifyNDJSON(simplifier, strings.NewReader(input.String()), &output, NDJSONConfig{Concurrency: concurrency})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if output.String() != expected.String() {
+			t.Errorf("Concurrency %d: unexpected output %q", concurrency, output.String())
+		}
+	}
+}
+
+func TestSimplifyNDJSONErrors(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+	input := "{\"id\":1}\n{broken\n{\"id\":3,\"password\":\"x\"}"
+
+	var output bytes.Buffer
+	err := SimplifyNDJSON(simplifier, strings.NewReader(input), &output, NDJSONConfig{})
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("Expected an error for line 2, got %v", err)
+	}
+
+	var skipped []int
+	output.Reset()
+	err = SimplifyNDJSON(simplifier, strings.NewReader(input), &output, NDJSONConfig{
+		Concurrency: 4,
+		OnError: func(line int, record []byte, err error) error {
+			skipped = append(skipped, line)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.String() != "{\"id\":1}\n{\"id\":3}\n" {
+		t.Errorf("Unexpected output %q", output.String())
+	}
+	if len(skipped) != 1 || skipped[0] != 2 {
+		t.Errorf("Expected line 2 to be skipped, got %v", skipped)
+	}
+
+	errAbort := errors.New("abort")
+	err = SimplifyNDJSON(simplifier, strings.NewReader(input), &output, NDJSONConfig{
+		OnError: func(line int, record []byte, err error) error {
+			return errAbort
+		},
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("Expected the handler error, got %v", err)
+	}
+}
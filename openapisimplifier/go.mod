@@ -0,0 +1,10 @@
+module github.com/xhinliang/gosimplifier/openapisimplifier
+
+go 1.23.0
+
+require (
+	github.com/xhinliang/gosimplifier v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require google.golang.org/protobuf v1.36.9 // indirect
@@ -0,0 +1,252 @@
+// Package openapisimplifier generates gosimplifier rules from OpenAPI 3 documents, so the
+// responses of a service carry the fields its API contract declares and nothing else.
+package openapisimplifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xhinliang/gosimplifier"
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a parsed OpenAPI document.
+type Document struct {
+	root map[string]interface{}
+}
+
+// Parse parses an OpenAPI document, in YAML or JSON.
+func Parse(data []byte) (*Document, error) {
+	var parsed interface{}
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	root, _ := normalize(parsed).(map[string]interface{})
+	if root == nil {
+		return nil, fmt.Errorf("empty OpenAPI document")
+	}
+	return &Document{root: root}, nil
+}
+
+// SchemaRule returns the rule keeping the properties the schema components/schemas/<name>
+// declares, see ResponseRule.
+func (d *Document) SchemaRule(name string) (*gosimplifier.Rule, error) {
+	return d.refRule("#/components/schemas/" + name)
+}
+
+// ResponseRule returns the rule keeping the properties the JSON response of the operation
+// declares, e.g. ResponseRule("GET", "/users/{id}", "200"). Properties the schema does not
+// declare, or marks with "x-internal: true", are removed, at every depth the schema describes;
+// the values of free-form objects are kept whole. Schema property names are JSON names, so the
+// simplifier is created with gosimplifier.WithJSONFieldNames:
+//
+//	rule, err := doc.ResponseRule("GET", "/users/{id}", "200")
+//	simplifier, err := gosimplifier.NewSimplifierByRule(rule, gosimplifier.WithJSONFieldNames())
+func (d *Document) ResponseRule(method string, path string, status string) (*gosimplifier.Rule, error) {
+	operation, ok := lookup(d.root, "paths", path, strings.ToLower(method)).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no operation %s %s", method, path)
+	}
+	response, err := d.resolve(lookup(operation, "responses", status))
+	if err != nil {
+		return nil, err
+	}
+	if response == nil {
+		return nil, fmt.Errorf("no response %s for %s %s", status, method, path)
+	}
+	content, _ := response["content"].(map[string]interface{})
+	media, ok := content["application/json"].(map[string]interface{})
+	if !ok {
+		for _, mediaType := range sortedKeys(content) {
+			if strings.HasSuffix(mediaType, "+json") {
+				media, _ = content[mediaType].(map[string]interface{})
+				break
+			}
+		}
+	}
+	if media == nil {
+		return nil, fmt.Errorf("no JSON content in response %s for %s %s", status, method, path)
+	}
+	return d.ruleOrEmpty(media["schema"])
+}
+
+func (d *Document) refRule(ref string) (*gosimplifier.Rule, error) {
+	return d.ruleOrEmpty(map[string]interface{}{"$ref": ref})
+}
+
+func (d *Document) ruleOrEmpty(schema interface{}) (*gosimplifier.Rule, error) {
+	rule, err := d.rule(schema, make(map[string]int))
+	if rule == nil && err == nil {
+		rule = &gosimplifier.Rule{}
+	}
+	return rule, err
+}
+
+// maxRecursion is the number of times a recursive schema is expanded in a rule.
+const maxRecursion = 4
+
+// rule returns the rule of the values described by schema, or nil if they are kept whole.
+// visiting counts the expansions of the references being expanded: a recursive schema is
+// expanded maxRecursion times, and the values it describes below are only simplified by the
+// property rules of the root rule.
+func (d *Document) rule(schema interface{}, visiting map[string]int) (*gosimplifier.Rule, error) {
+	ref, _ := lookup(schema, "$ref").(string)
+	if visiting[ref] >= maxRecursion {
+		return nil, nil
+	}
+	resolved, err := d.resolve(schema)
+	if err != nil || resolved == nil {
+		return nil, err
+	}
+	if ref != "" {
+		visiting[ref]++
+		defer func() { visiting[ref]-- }()
+	}
+	if items, ok := resolved["items"]; ok {
+		return d.rule(items, visiting)
+	}
+	properties := make(map[string]interface{})
+	internal := make(map[string]bool)
+	var extra interface{}
+	if err := d.collect(resolved, properties, internal, &extra); err != nil {
+		return nil, err
+	}
+	rule := &gosimplifier.Rule{}
+	if len(properties) == 0 && len(internal) == 0 {
+		// a free-form object, or a map whose values may still have rules
+		if extra == nil {
+			return nil, nil
+		}
+		sub, err := d.rule(extra, visiting)
+		if sub == nil || err != nil {
+			return nil, err
+		}
+		rule.PropertySimplifiers = map[string]*gosimplifier.Rule{"*": sub}
+		return rule, nil
+	}
+	for _, name := range sortedKeys(properties) {
+		rule.KeepProperties = append(rule.KeepProperties, name)
+		sub, err := d.rule(properties[name], visiting)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if sub != nil {
+			if rule.PropertySimplifiers == nil {
+				rule.PropertySimplifiers = make(map[string]*gosimplifier.Rule)
+			}
+			rule.PropertySimplifiers[name] = sub
+		}
+	}
+	if len(rule.KeepProperties) == 0 {
+		// every declared property is internal, and an empty keep_properties keeps everything
+		rule.RemoveProperties = sortedKeys(internal)
+	}
+	return rule, nil
+}
+
+// collect adds the properties schema declares, directly or through allOf, oneOf and anyOf, to
+// properties, or to internal if they are marked x-internal. extra is set to the schema of
+// additionalProperties, if any.
+func (d *Document) collect(schema map[string]interface{}, properties map[string]interface{}, internal map[string]bool, extra *interface{}) error {
+	declared, _ := schema["properties"].(map[string]interface{})
+	for name, property := range declared {
+		resolved, err := d.resolve(property)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if isInternal(property) || isInternal(resolved) {
+			internal[name] = true
+		} else {
+			properties[name] = property
+		}
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		*extra = additional
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		branches, _ := schema[key].([]interface{})
+		for _, branch := range branches {
+			resolved, err := d.resolve(branch)
+			if err != nil {
+				return err
+			}
+			if resolved != nil {
+				if err := d.collect(resolved, properties, internal, extra); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve follows the local reference of schema, if any, and returns the object it denotes.
+func (d *Document) resolve(schema interface{}) (map[string]interface{}, error) {
+	for depth := 0; depth < 32; depth++ {
+		object, _ := schema.(map[string]interface{})
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object, nil
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Errorf("reference %s is not local to the document", ref)
+		}
+		var path []string
+		for _, token := range strings.Split(ref[2:], "/") {
+			path = append(path, strings.NewReplacer("~1", "/", "~0", "~").Replace(token))
+		}
+		if schema = lookup(d.root, path...); schema == nil {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+	}
+	return nil, fmt.Errorf("too many nested references")
+}
+
+// normalize converts the YAML mappings with keys other than strings, such as the status codes of
+// responses, to objects with string keys.
+func normalize(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, v := range value {
+			value[key] = normalize(v)
+		}
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, v := range value {
+			object[fmt.Sprint(key)] = normalize(v)
+		}
+		return object
+	case []interface{}:
+		for i, v := range value {
+			value[i] = normalize(v)
+		}
+	}
+	return value
+}
+
+func isInternal(schema interface{}) bool {
+	internal, _ := lookup(schema, "x-internal").(bool)
+	return internal
+}
+
+// lookup returns the value at path in nested objects, or nil.
+func lookup(value interface{}, path ...string) interface{} {
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+	return value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
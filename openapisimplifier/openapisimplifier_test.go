@@ -0,0 +1,111 @@
+package openapisimplifier
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+const document = `
+openapi: 3.0.3
+paths:
+  /users/{id}:
+    get:
+      responses:
+        200:
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          properties:
+            name: { type: string }
+            password_hash: { type: string, x-internal: true }
+            friends:
+              type: array
+              items: { $ref: '#/components/schemas/User' }
+            labels:
+              type: object
+              additionalProperties: { $ref: '#/components/schemas/Label' }
+            extra: { type: object }
+    Base:
+      properties:
+        id: { type: integer }
+    Label:
+      properties:
+        value: { type: string }
+`
+
+type User struct {
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	PasswordHash string           `json:"password_hash"`
+	Debug        string           `json:"debug"`
+	Friends      []User           `json:"friends"`
+	Labels       map[string]Label `json:"labels"`
+	Extra        map[string]int   `json:"extra"`
+}
+
+type Label struct {
+	Value string `json:"value"`
+	Owner string `json:"owner"`
+}
+
+func TestResponseRule(t *testing.T) {
+	doc, err := Parse([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := doc.ResponseRule("GET", "/users/{id}", "200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"extra", "friends", "id", "labels", "name"}; !reflect.DeepEqual(rule.KeepProperties, expected) {
+		t.Errorf("Expected to keep %v, got %v", expected, rule.KeepProperties)
+	}
+	simplifier, err := gosimplifier.NewSimplifierByRule(rule, gosimplifier.WithJSONFieldNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := User{
+		ID: 1, Name: "a", PasswordHash: "h", Debug: "d",
+		Friends: []User{{ID: 2, PasswordHash: "h", Debug: "d"}},
+		Labels:  map[string]Label{"team": {Value: "core", Owner: "me"}},
+		Extra:   map[string]int{"any": 1},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := User{
+		ID: 1, Name: "a",
+		Friends: []User{{ID: 2}},
+		Labels:  map[string]Label{"team": {Value: "core"}},
+		Extra:   map[string]int{"any": 1},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+}
+
+func TestResponseRuleErrors(t *testing.T) {
+	doc, err := Parse([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc.ResponseRule("POST", "/users/{id}", "200"); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+	if _, err := doc.ResponseRule("GET", "/users/{id}", "404"); err == nil {
+		t.Error("Expected an error for an unknown response")
+	}
+	if _, err := doc.SchemaRule("Missing"); err == nil {
+		t.Error("Expected an error for an unresolved reference")
+	}
+}
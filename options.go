@@ -0,0 +1,76 @@
+package gosimplifier
+
+import "reflect"
+
+// Option configures optional behavior of a Simplifier.
+type Option func(*options)
+
+type options struct {
+	middlewares     []Middleware
+	provenanceKey   string
+	vault           func(token string, value interface{}) error
+	stats           *Stats
+	strictFields    bool
+	removedValues   map[reflect.Type]reflect.Value
+	bestEffort      bool
+	nodeBudget      int
+	removeTags      map[string][]string
+	hashSalt        []byte
+	jsonFieldNames  bool
+	caseInsensitive bool
+	dropEmpty       bool
+	maxDepth        int
+	depthPolicy     DepthPolicy
+	parallelism     int
+	errorPolicy     ErrorPolicy
+	onError         []func(path string, err error)
+	registry        *Registry
+	tokenizer       Tokenizer
+	removalRule     RemovalRule
+	removal         *removal
+	globstarNames   map[string]bool
+	beforeHooks     []func(v interface{})
+	afterHooks      []func(v interface{}, info CallInfo)
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.bestEffort && o.nodeBudget == 0 {
+		o.nodeBudget = defaultBestEffortNodeBudget
+	}
+	return o
+}
+
+// WithMiddleware wraps the traversal with the given middlewares.
+// The first middleware is the outermost one and sees every node first.
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// chain wraps the walker with the configured middlewares. The depth limit, budget and best
+// effort handling are outermost, so they also cover the middlewares.
+func (o *options) chain(walker Walker) Walker {
+	for i := len(o.middlewares) - 1; i >= 0; i-- {
+		walker = o.middlewares[i](walker)
+	}
+	if o.maxDepth > 0 {
+		walker = depthMiddleware(o.maxDepth, o.depthPolicy)(walker)
+	}
+	if o.nodeBudget > 0 {
+		walker = budgetMiddleware(o.nodeBudget)(walker)
+	}
+	if o.bestEffort {
+		walker = bestEffortMiddleware(walker)
+	}
+	return walker
+}
+
+// observesTraversal reports whether anything besides the rules acts on the visited nodes.
+func (o *options) observesTraversal() bool {
+	return len(o.middlewares) > 0 || o.nodeBudget > 0 || o.bestEffort || o.maxDepth > 0
+}
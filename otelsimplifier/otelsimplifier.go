@@ -0,0 +1,134 @@
+// Package otelsimplifier connects gosimplifier to OpenTelemetry, simplifying the attributes of
+// spans and of their events before they are exported, so traces shipped to third parties are
+// free of sensitive request fields. The rules are applied to the attributes as to a map from
+// their keys to their values, e.g.:
+//
+//	{ "remove_properties": [ "http.request.header.authorization" ], "mask_properties": [ "user.email" ] }
+package otelsimplifier
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xhinliang/gosimplifier"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SimplifyAttributes returns the attributes simplified by s, in their order. The attributes the
+// rules remove are left out, and those they change are replaced, e.g. masked strings; a value
+// changed to a type attributes cannot hold is left out as well. If the attributes cannot be
+// simplified, none are returned, so they never pass unscrubbed.
+func SimplifyAttributes(s gosimplifier.Simplifier, attrs []attribute.KeyValue) ([]attribute.KeyValue, error) {
+	if len(attrs) == 0 {
+		return attrs, nil
+	}
+	values := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		values[string(attr.Key)] = attr.Value.AsInterface()
+	}
+	simplified, err := s.Simplify(values)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	values, _ = simplified.(map[string]interface{})
+	result := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		value, ok := values[string(attr.Key)]
+		if !ok {
+			continue
+		}
+		if kv, ok := keyValue(attr.Key, value); ok {
+			result = append(result, kv)
+		}
+	}
+	return result, nil
+}
+
+// keyValue converts a simplified value back to an attribute.
+func keyValue(key attribute.Key, value interface{}) (attribute.KeyValue, bool) {
+	switch v := value.(type) {
+	case string:
+		return key.String(v), true
+	case bool:
+		return key.Bool(v), true
+	case int64:
+		return key.Int64(v), true
+	case float64:
+		return key.Float64(v), true
+	case []string:
+		return key.StringSlice(v), true
+	case []bool:
+		return key.BoolSlice(v), true
+	case []int64:
+		return key.Int64Slice(v), true
+	case []float64:
+		return key.Float64Slice(v), true
+	}
+	return attribute.KeyValue{}, false
+}
+
+// NewSpanProcessor returns a SpanProcessor handing the ended spans to next, e.g. the batch
+// processor of an exporter, with their attributes and the attributes of their events simplified
+// by s:
+//
+//	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+//		otelsimplifier.NewSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter), simplifier)))
+//
+// Spans are simplified when they end, so attributes set at any time are covered; the spans seen
+// by other processors are left as they are.
+func NewSpanProcessor(next sdktrace.SpanProcessor, s gosimplifier.Simplifier) sdktrace.SpanProcessor {
+	return &spanProcessor{next: next, simplifier: s}
+}
+
+type spanProcessor struct {
+	next       sdktrace.SpanProcessor
+	simplifier gosimplifier.Simplifier
+}
+
+func (p *spanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *spanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.next.OnEnd(p.simplifySpan(s))
+}
+
+func (p *spanProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *spanProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// simplifySpan returns the span with its attributes and the attributes of its events simplified.
+func (p *spanProcessor) simplifySpan(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	span := &simplifiedSpan{ReadOnlySpan: s}
+	span.attributes, _ = SimplifyAttributes(p.simplifier, s.Attributes())
+	events := s.Events()
+	if len(events) > 0 {
+		span.events = make([]sdktrace.Event, len(events))
+		for i, event := range events {
+			event.Attributes, _ = SimplifyAttributes(p.simplifier, event.Attributes)
+			span.events[i] = event
+		}
+	}
+	return span
+}
+
+// simplifiedSpan is a span whose attributes and events are replaced.
+type simplifiedSpan struct {
+	sdktrace.ReadOnlySpan
+	attributes []attribute.KeyValue
+	events     []sdktrace.Event
+}
+
+func (s *simplifiedSpan) Attributes() []attribute.KeyValue {
+	return s.attributes
+}
+
+func (s *simplifiedSpan) Events() []sdktrace.Event {
+	return s.events
+}
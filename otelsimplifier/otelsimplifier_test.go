@@ -0,0 +1,78 @@
+package otelsimplifier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const rules = `{
+	"remove_properties": [ "http.request.header.authorization", "db.statement" ],
+	"mask_properties": [ "user.email" ]
+}`
+
+func TestSimplifyAttributes(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := SimplifyAttributes(simplifier, []attribute.KeyValue{
+		attribute.String("http.method", "GET"),
+		attribute.String("http.request.header.authorization", "Bearer secret"),
+		attribute.String("user.email", "alice@example.com"),
+		attribute.Int64("http.status_code", 200),
+		attribute.Bool("retry", true),
+		attribute.StringSlice("tags", []string{"a", "b"}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []attribute.KeyValue{
+		attribute.String("http.method", "GET"),
+		attribute.String("user.email", "****"),
+		attribute.Int64("http.status_code", 200),
+		attribute.Bool("retry", true),
+		attribute.StringSlice("tags", []string{"a", "b"}),
+	}
+	if !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("Expected %v, got %v", expected, attrs)
+	}
+}
+
+func TestSpanProcessor(t *testing.T) {
+	simplifier, _ := gosimplifier.NewSimplifier(rules)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(NewSpanProcessor(recorder, simplifier)))
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Tracer("test").Start(context.Background(), "query",
+		trace.WithAttributes(attribute.String("http.request.header.authorization", "Bearer secret")))
+	span.SetAttributes(attribute.String("db.statement", "SELECT *"), attribute.String("db.system", "postgresql"))
+	span.AddEvent("user", trace.WithAttributes(attribute.String("user.email", "alice@example.com"), attribute.Int("user.id", 7)))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if attrs := spans[0].Attributes(); !reflect.DeepEqual(attrs, []attribute.KeyValue{attribute.String("db.system", "postgresql")}) {
+		t.Errorf("Expected only db.system to be kept, got %v", attrs)
+	}
+	events := spans[0].Events()
+	if len(events) != 1 || events[0].Name != "user" {
+		t.Fatalf("Expected the user event, got %v", events)
+	}
+	expected := []attribute.KeyValue{attribute.String("user.email", "****"), attribute.Int("user.id", 7)}
+	if !reflect.DeepEqual(events[0].Attributes, expected) {
+		t.Errorf("Expected %v, got %v", expected, events[0].Attributes)
+	}
+	if spans[0].Name() != "query" {
+		t.Errorf("Expected the other properties of the span to be kept, got %s", spans[0].Name())
+	}
+}
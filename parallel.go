@@ -0,0 +1,106 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"sync"
+)
+
+// minParallelElements is the length from which WithParallelism splits a list between workers;
+// shorter lists are cheaper to walk than to hand out.
+const minParallelElements = 1024
+
+// WithParallelism simplifies the elements of large lists, of at least minParallelElements
+// elements, on up to n goroutines. Lists nested in the elements of such a list are walked by the
+// goroutine of the element. Middlewares must be safe for concurrent use, and a node budget is
+// only checked per goroutine while the elements are walked.
+func WithParallelism(n int) Option {
+	return func(o *options) {
+		o.parallelism = n
+	}
+}
+
+// visitElements walks the elements of the list value from start to end with w.
+func (s *simplifierImpl) visitElements(node *Node, value reflect.Value, start int, end int, w *walk) error {
+	for i := start; i < end; i++ {
+		selector, elementRuler := s.elementRuler(i, value.Len())
+		child := node.child(value, value.Index(i), reflect.Value{}, selector, i, s, elementRuler)
+		child.walk = w
+		if err := w.visitChild(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitElementsParallel splits the elements of the list value between w.parallelism workers,
+// each walking its share with a fork of the walk, which is joined back once all are done.
+// The first error in element order is returned, and a panic is raised again by the caller.
+func (s *simplifierImpl) visitElementsParallel(node *Node, value reflect.Value) error {
+	w := node.walk
+	workers := min(w.parallelism, value.Len()/(minParallelElements/4))
+	chunk := (value.Len() + workers - 1) / workers
+	forks := make([]*walk, workers)
+	errs := make([]error, workers)
+	panics := make([]interface{}, workers)
+	visited := w.visited
+	var wg sync.WaitGroup
+	for k := range forks {
+		forks[k] = w.fork()
+		start, end := k*chunk, min((k+1)*chunk, value.Len())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				panics[k] = recover()
+			}()
+			errs[k] = s.visitElements(node, value, start, end, forks[k])
+		}()
+	}
+	wg.Wait()
+	for _, fork := range forks {
+		w.join(fork, visited)
+	}
+	for k := range forks {
+		if panics[k] != nil {
+			panic(panics[k])
+		}
+		if errs[k] != nil {
+			return errs[k]
+		}
+	}
+	return nil
+}
+
+// fork returns a walk for a worker, sharing the configuration of w but not its state.
+func (w *walk) fork() *walk {
+	fork := newWalk()
+	fork.root, fork.walker, fork.ctx, fork.done, fork.metadata = w.root, w.walker, w.ctx, w.done, w.metadata
+	fork.visited = w.visited
+	if w.provenance != nil {
+		fork.provenance = make(map[string]interface{})
+	}
+	if w.report != nil {
+		fork.report = &Report{dryRun: w.report.dryRun}
+	}
+	return fork
+}
+
+// join adds the state of the fork, forked when w had visited nodes, to w and releases the fork.
+func (w *walk) join(fork *walk, visited int) {
+	w.visited += fork.visited - visited
+	w.steps += fork.steps
+	w.errors = append(w.errors, fork.errors...)
+	for path, record := range fork.provenance {
+		w.provenance[path] = record
+	}
+	for rulePath, hits := range fork.ruleHits {
+		if w.ruleHits == nil {
+			w.ruleHits = make(map[string]uint64)
+		}
+		w.ruleHits[rulePath] += hits
+	}
+	if fork.report != nil {
+		w.report.Changes = append(w.report.Changes, fork.report.Changes...)
+	}
+	releaseWalk(fork)
+}
@@ -0,0 +1,74 @@
+package gosimplifier
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWithParallelism(t *testing.T) {
+	events := func() []map[string]interface{} {
+		events := make([]map[string]interface{}, 5000)
+		for i := range events {
+			events[i] = map[string]interface{}{
+				"id":    i,
+				"debug": "d",
+				"tags":  []interface{}{map[string]interface{}{"debug": "d", "name": fmt.Sprint(i)}},
+			}
+		}
+		return events
+	}
+	rules := `{ "remove_properties": [ "debug" ] }`
+
+	sequential, err := NewSimplifier(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := sequential.Simplify(events())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := NewStats()
+	parallel, err := NewSimplifier(rules, WithParallelism(4), WithStats(stats))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := parallel.Simplify(events())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Error("Expected the parallel result to match the sequential one")
+	}
+	if hits := stats.Snapshot().RuleHits["debug"]; hits != 10000 {
+		t.Errorf("Expected the rule hits of every worker to be counted, got %d", hits)
+	}
+}
+
+func TestWithParallelismError(t *testing.T) {
+	failing := errors.New("failing")
+	fail := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Name() == "fail" {
+				return fmt.Errorf("%s: %w", node.Path(), failing)
+			}
+			return next(node)
+		}
+	}
+	simplifier, err := NewSimplifier(`{}`, WithParallelism(4), WithMiddleware(fail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := make([]map[string]int, 2000)
+	for i := range items {
+		items[i] = map[string]int{"ok": 1}
+	}
+	items[1500]["fail"] = 1
+	items[600]["fail"] = 1
+	_, err = simplifier.Simplify(items)
+	if !errors.Is(err, failing) || err.Error() != "[600].fail: failing" {
+		t.Errorf("Expected the first failure in element order, got %v", err)
+	}
+}
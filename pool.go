@@ -0,0 +1,52 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"sync"
+)
+
+// The scratch state of Simplify calls is pooled, so a call does not generate garbage
+// proportional to the size of the value: nodes are released once walked, and the walk and the
+// map key buffers once the call returns.
+var (
+	walkPool = sync.Pool{New: func() interface{} { return new(walk) }}
+	nodePool = sync.Pool{New: func() interface{} { return new(Node) }}
+	keysPool = sync.Pool{New: func() interface{} { return new([]reflect.Value) }}
+)
+
+// newWalk returns a cleared walk from the pool.
+func newWalk() *walk {
+	return walkPool.Get().(*walk)
+}
+
+// releaseWalk clears the walk and returns it to the pool. State handed out by the call, such as
+// the errors of a PartialError, must be detached from the walk first.
+func releaseWalk(w *walk) {
+	*w = walk{}
+	walkPool.Put(w)
+}
+
+// releaseNode clears the node and returns it to the pool.
+func releaseNode(n *Node) {
+	*n = Node{}
+	nodePool.Put(n)
+}
+
+// visitChild walks the child node and releases it.
+func (w *walk) visitChild(child *Node) error {
+	err := w.visit(child)
+	releaseNode(child)
+	return err
+}
+
+// mapKeys returns the sorted keys of the map value in a pooled buffer, released with releaseKeys.
+func mapKeys(value reflect.Value) *[]reflect.Value {
+	keys := keysPool.Get().(*[]reflect.Value)
+	*keys = appendSortedMapKeys((*keys)[:0], value)
+	return keys
+}
+
+func releaseKeys(keys *[]reflect.Value) {
+	clear(*keys)
+	keysPool.Put(keys)
+}
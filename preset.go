@@ -0,0 +1,102 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// presets are the built-in rule fragments of PresetRule. Their rules sit at the root, so they
+// apply to the properties of every depth no other rule names. Names are listed as Go field names
+// and as JSON keys, see WithCaseInsensitiveMatch for other spellings.
+var presets = map[string]string{
+	"pii-emails": `{
+		"mask_properties": [ "Email", "EmailAddress", "email", "emailAddress", "email_address" ]
+	}`,
+	"pii-phones": `{
+		"mask_properties": [
+			"Phone", "PhoneNumber", "Mobile", "MobileNumber",
+			"phone", "phoneNumber", "phone_number", "mobile", "mobileNumber", "mobile_number"
+		]
+	}`,
+	"pii-passwords": `{
+		"remove_properties": [
+			"Password", "PasswordHash", "Passwd", "Passphrase",
+			"password", "passwordHash", "password_hash", "passwd", "passphrase"
+		]
+	}`,
+	"pii-tokens": `{
+		"remove_properties": [
+			"Token", "AccessToken", "RefreshToken", "IDToken", "APIKey", "ApiKey", "Secret", "ClientSecret",
+			"token", "accessToken", "access_token", "refreshToken", "refresh_token", "idToken", "id_token",
+			"apiKey", "api_key", "secret", "clientSecret", "client_secret", "Authorization", "authorization"
+		]
+	}`,
+	"pii-addresses": `{
+		"remove_properties": [
+			"Address", "StreetAddress", "Street", "PostalCode", "ZipCode",
+			"address", "streetAddress", "street_address", "street", "postalCode", "postal_code", "zipCode", "zip_code"
+		]
+	}`,
+}
+
+// presetGroups are the presets made of other presets.
+var presetGroups = map[string][]string{
+	"pii-basic": {"pii-emails", "pii-phones", "pii-passwords", "pii-tokens", "pii-addresses"},
+}
+
+// PresetRule returns a new copy of the built-in rule fragment named name, so teams do not each
+// rebuild the same redaction lists:
+//
+//   - "pii-emails" and "pii-phones" mask e-mail addresses and phone numbers
+//   - "pii-passwords", "pii-tokens" and "pii-addresses" remove passwords, credentials and
+//     postal addresses
+//   - "pii-basic" is all of the above
+//
+// Presets can be named by "extends" unless a registered rule set has the same name, which
+// merges them into the rules extending them:
+//
+//	{
+//	  "extends": [ "pii-basic" ],
+//	  "remove_properties": [ "Debug" ]
+//	}
+func PresetRule(name string) (*Rule, error) {
+	rule, ok := presetRule(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %s", name)
+	}
+	return rule, nil
+}
+
+// PresetNames returns the names of the built-in presets, sorted.
+func PresetNames() []string {
+	names := make([]string, 0, len(presets)+len(presetGroups))
+	for name := range presets {
+		names = append(names, name)
+	}
+	for name := range presetGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func presetRule(name string) (*Rule, bool) {
+	if group, ok := presetGroups[name]; ok {
+		merged := &Rule{}
+		for _, member := range group {
+			rule, _ := presetRule(member)
+			merged = mergeRules(merged, rule)
+		}
+		return merged, true
+	}
+	rulesJson, ok := presets[name]
+	if !ok {
+		return nil, false
+	}
+	rule := &Rule{}
+	if err := json.Unmarshal([]byte(rulesJson), rule); err != nil {
+		panic(fmt.Sprintf("preset %s: %v", name, err))
+	}
+	return rule, true
+}
@@ -0,0 +1,78 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type PresetExample struct {
+	Name     string
+	Email    string
+	Password string
+	Account  *PresetAccount
+}
+
+type PresetAccount struct {
+	PhoneNumber string
+	APIKey      string
+	Plan        string
+}
+
+func TestPresetRule(t *testing.T) {
+	rule, err := PresetRule("pii-basic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplifier, err := NewSimplifierByRule(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(PresetExample{
+		Name:     "n",
+		Email:    "a@example.com",
+		Password: "p",
+		Account:  &PresetAccount{PhoneNumber: "555-0100", APIKey: "k", Plan: "pro"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := PresetExample{Name: "n", Email: DefaultMask, Account: &PresetAccount{PhoneNumber: DefaultMask, Plan: "pro"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	rule.RemoveProperties = nil
+	if again, _ := PresetRule("pii-basic"); len(again.RemoveProperties) == 0 {
+		t.Error("Expected PresetRule to return a new copy")
+	}
+	if _, err := PresetRule("unknown"); err == nil {
+		t.Error("Expected an error for an unknown preset")
+	}
+}
+
+func TestPresetExtends(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "extends": [ "pii-passwords" ], "remove_properties": [ "Name" ] }`, WithRegistry(NewRegistry()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(PresetExample{Name: "n", Email: "e", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, PresetExample{Email: "e"}) {
+		t.Errorf("Expected the preset to be merged into the rules, got %+v", simplified)
+	}
+}
+
+func TestPresetNames(t *testing.T) {
+	names := PresetNames()
+	if !slices.IsSorted(names) || !slices.Contains(names, "pii-basic") || !slices.Contains(names, "pii-tokens") {
+		t.Errorf("Expected the sorted preset names, got %v", names)
+	}
+	for _, name := range names {
+		if _, err := PresetRule(name); err != nil {
+			t.Errorf("Preset %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package gosimplifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// simplifyMessage returns the simplified copy of a protobuf message. Messages are copied with
+// proto.Clone and simplified through protoreflect rather than through the fields of the
+// generated structs, whose internals are not meant to be copied or walked:
+//
+//   - rule names match the proto name of a field ("user_id"), its JSON name ("userId") or the
+//     name of its generated Go field ("UserId"); a name of a oneof matches its set field
+//   - removed fields are cleared, so they are absent rather than zero
+//   - actions other than removals apply to singular scalar fields; a repeated or map field they
+//     apply to is cleared
+//
+// Middlewares and the options observing the traversal, such as WithStats or WithBestEffort, do
+// not apply to messages, nor do remove_if, remove_if_zero and max_items: simplifying a message
+// with them fails rather than leaving them out silently.
+func (s *simplifierImpl) simplifyMessage(ctx context.Context, message proto.Message) (proto.Message, error) {
+	clone := proto.Clone(message)
+	return clone, s.simplifyMessageInPlace(ctx, clone)
+}
+
+// simplifyMessageInPlace applies the rules to the message itself.
+func (s *simplifierImpl) simplifyMessageInPlace(ctx context.Context, message proto.Message) error {
+	if s.messageErr != nil {
+		return fmt.Errorf("simplify %T: %w", message, s.messageErr)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	value := reflect.ValueOf(message)
+	start := s.options.beforeSimplify(value)
+	w := newWalk()
+	defer releaseWalk(w)
+	w.root, w.walker, w.ctx, w.done, w.metadata = s, applyNode, ctx, ctx.Done(), metadataFrom(ctx)
+	root := &Node{Value: value, index: -1, ruler: s, walk: w}
+	err := s.applyMessage(root, message.ProtoReflect())
+	s.options.afterSimplify(value, start, err)
+	return err
+}
+
+// messageSupport returns the error simplifying protobuf messages with the root simplifier s
+// fails with, or nil if its options and rules all apply to messages.
+func (s *simplifierImpl) messageSupport() error {
+	if s.options.observesTraversal() {
+		return errors.New("middlewares and the options observing the traversal do not apply to protobuf messages")
+	}
+	return messageRules(s.rule, "")
+}
+
+// messageRules returns an error if rule, located at path in the rule tree, or one of its
+// sub-rules has a section that does not apply to protobuf messages.
+func messageRules(rule *Rule, path string) error {
+	if rule == nil {
+		return nil
+	}
+	switch {
+	case rule.RemoveIf != nil:
+		return fmt.Errorf("%s: remove_if does not apply to protobuf messages", displayPath(path))
+	case len(rule.RemoveIfZero) > 0:
+		return fmt.Errorf("%s: remove_if_zero does not apply to protobuf messages", displayPath(path))
+	case rule.MaxItems > 0:
+		return fmt.Errorf("%s: max_items does not apply to protobuf messages", displayPath(path))
+	}
+	for _, name := range sortedKeys(rule.PropertySimplifiers) {
+		if err := messageRules(rule.PropertySimplifiers[name], joinRulePath(path, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMessage applies the rules to the populated fields of the message held by node.
+func (s *simplifierImpl) applyMessage(node *Node, m protoreflect.Message) error {
+	if err := node.walk.ctx.Err(); err != nil {
+		return err
+	}
+	s.prune(node)
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name, r := s.fieldRuler(fd)
+		if r == nil {
+			name = string(fd.Name())
+			r = s.unmatchedRuler(name, node.walk.root)
+		}
+		if node.prunesChildren() {
+			r = removeRulerSingleton
+		}
+		err = s.applyField(node, m, fd, v, name, r)
+		return err == nil
+	})
+	return err
+}
+
+// fieldRuler returns the name of the rule for the field and its ruler, or nil if no rule names
+// the field.
+func (s *simplifierImpl) fieldRuler(fd protoreflect.FieldDescriptor) (string, ruler) {
+	names := []string{string(fd.Name()), fd.JSONName(), goCamelCase(string(fd.Name()))}
+	if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		names = append(names, string(oneof.Name()), goCamelCase(string(oneof.Name())))
+	}
+	for _, name := range names {
+		if ruleName, r := s.propertyRuler(name); r != nil {
+			return ruleName, r
+		}
+	}
+	return "", nil
+}
+
+// applyField applies the ruler to the field fd of m, whose value is v.
+func (s *simplifierImpl) applyField(node *Node, m protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value, name string, r ruler) error {
+	if r == removeRulerSingleton {
+		m.Clear(fd)
+		return nil
+	}
+	sub, descends := r.(*simplifierImpl)
+	child := node.child(reflect.Value{}, reflect.Value{}, reflect.Value{}, name, -1, s, r)
+	defer releaseNode(child)
+	switch {
+	case fd.IsList():
+		if !descends || child.prunesChildren() {
+			m.Clear(fd)
+			return nil
+		}
+		if fd.Message() == nil {
+			return nil
+		}
+		list := v.List()
+		for i := 0; i < list.Len(); i++ {
+			element := child.child(reflect.Value{}, reflect.Value{}, reflect.Value{}, "", i, sub, sub)
+			err := sub.applyMessage(element, list.Get(i).Message())
+			releaseNode(element)
+			if err != nil {
+				return err
+			}
+		}
+	case fd.IsMap():
+		if !descends {
+			m.Clear(fd)
+			return nil
+		}
+		return sub.applyMap(child, v.Map(), fd.MapValue())
+	case fd.Message() != nil:
+		if descends {
+			return sub.applyMessage(child, v.Message())
+		}
+		m.Clear(fd)
+	case !descends:
+		return s.applyScalar(child, m, fd, v, r)
+	}
+	return nil
+}
+
+// applyMap applies the rules to the entries of a map field, matching the keys like the keys of
+// a Go map.
+func (s *simplifierImpl) applyMap(node *Node, entries protoreflect.Map, valueField protoreflect.FieldDescriptor) error {
+	var keys []protoreflect.MapKey
+	entries.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	s.prune(node)
+	for _, key := range keys {
+		keyName := key.String()
+		_, r := s.keyRuler(keyName)
+		if r == nil {
+			r = s.unmatchedRuler(keyName, node.walk.root)
+		}
+		if node.prunesChildren() {
+			r = removeRulerSingleton
+		}
+		child := node.child(reflect.Value{}, reflect.Value{}, reflect.Value{}, keyName, -1, s, r)
+		var err error
+		switch sub, descends := r.(*simplifierImpl); {
+		case r == removeRulerSingleton:
+			entries.Clear(key)
+		case descends && valueField.Message() != nil:
+			err = sub.applyMessage(child, entries.Get(key).Message())
+		case descends:
+		case valueField.Message() != nil:
+			entries.Clear(key)
+		default:
+			err = s.applyMapScalar(child, entries, key, r)
+		}
+		releaseNode(child)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyScalar applies an action to a singular scalar field, through a Go map holding its value
+// so the ruler can replace it like a map value.
+func (s *simplifierImpl) applyScalar(node *Node, m protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value, r ruler) error {
+	result, ok, err := applyHeld(node, v, r)
+	if err != nil {
+		return err
+	}
+	if ok {
+		m.Set(fd, result)
+	} else {
+		m.Clear(fd)
+	}
+	return nil
+}
+
+func (s *simplifierImpl) applyMapScalar(node *Node, entries protoreflect.Map, key protoreflect.MapKey, r ruler) error {
+	result, ok, err := applyHeld(node, entries.Get(key), r)
+	if err != nil {
+		return err
+	}
+	if ok {
+		entries.Set(key, result)
+	} else {
+		entries.Clear(key)
+	}
+	return nil
+}
+
+// applyHeld applies the ruler to the scalar value v held by a Go map, and returns the result,
+// or false if the ruler removed the value or replaced it with a value of another type.
+func applyHeld(node *Node, v protoreflect.Value, r ruler) (protoreflect.Value, bool, error) {
+	value := reflect.ValueOf(v.Interface())
+	holder := reflect.MakeMapWithSize(reflect.MapOf(reflect.TypeOf(""), value.Type()), 1)
+	key := reflect.ValueOf(node.name)
+	holder.SetMapIndex(key, value)
+	node.Value, node.Parent, node.Key = value, holder, key
+	if err := r.apply(node); err != nil {
+		return protoreflect.Value{}, false, err
+	}
+	result := holder.MapIndex(key)
+	if !result.IsValid() || result.Type() != value.Type() {
+		return protoreflect.Value{}, false, nil
+	}
+	return protoreflect.ValueOf(result.Interface()), true, nil
+}
+
+// goCamelCase returns the name of the generated Go field of a proto field, as protoc-gen-go
+// derives it.
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case isASCIIDigit(c):
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
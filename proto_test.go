@@ -0,0 +1,116 @@
+package gosimplifier
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestSimplifyProtoMessage(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "source_context", "Oneofs" ],
+		"mask_properties": [ "name" ],
+		"property_simplifiers": {
+			"fields": { "remove_properties": [ "jsonName" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := &typepb.Type{
+		Name:          "secret",
+		Fields:        []*typepb.Field{{Name: "id", JsonName: "id", Number: 1}},
+		Oneofs:        []string{"kind"},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "a.proto"},
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &typepb.Type{
+		Name:   DefaultMask,
+		Fields: []*typepb.Field{{Number: 1, Name: "id"}},
+		Syntax: typepb.Syntax_SYNTAX_PROTO3,
+	}
+	if !proto.Equal(simplified.(proto.Message), expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	if original.Name != "secret" || original.SourceContext == nil {
+		t.Error("Expected the original to be unchanged")
+	}
+}
+
+func TestSimplifyProtoOneofsAndMaps(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"fields": {
+				"remove_properties": [ "secret" ],
+				"property_simplifiers": { "nested": { "remove_properties": [ "kind" ] } }
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := structpb.NewStruct(map[string]interface{}{
+		"secret": "s",
+		"public": "p",
+		"nested": map[string]interface{}{"a": 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SimplifyInPlace(simplifier, original); err != nil {
+		t.Fatal(err)
+	}
+	expected := &structpb.Struct{Fields: map[string]*structpb.Value{
+		"public": structpb.NewStringValue("p"),
+		"nested": {},
+	}}
+	if !proto.Equal(original, expected) {
+		t.Errorf("Expected %v, got %v", expected, original)
+	}
+}
+
+func TestGoCamelCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"user_id":     "UserId",
+		"json_name":   "JsonName",
+		"_private":    "XPrivate",
+		"field2_name": "Field2Name",
+		"HTTPServer":  "HTTPServer",
+	} {
+		if goName := goCamelCase(name); goName != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, name, goName)
+		}
+	}
+}
+
+func TestSimplifyProtoUnsupported(t *testing.T) {
+	for name, s := range map[string]func() (Simplifier, error){
+		"stats": func() (Simplifier, error) {
+			return NewSimplifier(`{ "remove_properties": [ "name" ] }`, WithStats(NewStats()))
+		},
+		"best effort": func() (Simplifier, error) {
+			return NewSimplifier(`{ "remove_properties": [ "name" ] }`, WithBestEffort())
+		},
+		"remove_if": func() (Simplifier, error) {
+			return NewSimplifier(`{ "property_simplifiers": { "fields": { "remove_if": { "field": "name", "equals": "id" } } } }`)
+		},
+		"max_items": func() (Simplifier, error) {
+			return NewSimplifier(`{ "property_simplifiers": { "fields": { "max_items": 1 } } }`)
+		},
+	} {
+		simplifier, err := s()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := simplifier.Simplify(&typepb.Type{Name: "secret"}); err == nil {
+			t.Errorf("%s: expected an error for a protobuf message", name)
+		}
+	}
+}
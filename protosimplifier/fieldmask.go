@@ -0,0 +1,60 @@
+package protosimplifier
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/xhinliang/gosimplifier"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// RuleFromFieldMask returns the rule applying the FieldMask semantics of gRPC APIs: with keep,
+// the paths of the mask are kept and every other property is removed, as in a read mask;
+// otherwise the paths are removed. Paths are dot-separated, "user.address.city", and match the
+// properties like the names of a rule, so they match the proto names of message fields.
+//
+// An empty mask returns an empty rule, which keeps everything.
+func RuleFromFieldMask(mask *fieldmaskpb.FieldMask, keep bool) *gosimplifier.Rule {
+	rule := &gosimplifier.Rule{}
+	for _, path := range mask.GetPaths() {
+		if path == "" {
+			continue
+		}
+		addPath(rule, strings.Split(path, "."), keep)
+	}
+	return rule
+}
+
+// addPath adds the path, split on dots, to rule.
+func addPath(rule *gosimplifier.Rule, path []string, keep bool) {
+	name := path[0]
+	sub, nested := rule.PropertySimplifiers[name]
+	if len(path) == 1 {
+		// the whole property is kept or removed, whatever its other paths select
+		if keep && !slices.Contains(rule.KeepProperties, name) {
+			rule.KeepProperties = append(rule.KeepProperties, name)
+		} else if !keep && !slices.Contains(rule.RemoveProperties, name) {
+			rule.RemoveProperties = append(rule.RemoveProperties, name)
+		}
+		delete(rule.PropertySimplifiers, name)
+		return
+	}
+	if keep {
+		if slices.Contains(rule.KeepProperties, name) && !nested {
+			return
+		}
+		if !nested {
+			rule.KeepProperties = append(rule.KeepProperties, name)
+		}
+	} else if slices.Contains(rule.RemoveProperties, name) {
+		return
+	}
+	if !nested {
+		sub = &gosimplifier.Rule{}
+		if rule.PropertySimplifiers == nil {
+			rule.PropertySimplifiers = make(map[string]*gosimplifier.Rule)
+		}
+		rule.PropertySimplifiers[name] = sub
+	}
+	addPath(sub, path[1:], keep)
+}
@@ -0,0 +1,84 @@
+package protosimplifier
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestRuleFromFieldMask(t *testing.T) {
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"name", "fields.name", "fields.number", "source_context", "source_context.file_name"}}
+	expected := &gosimplifier.Rule{
+		KeepProperties: []string{"name", "fields", "source_context"},
+		PropertySimplifiers: map[string]*gosimplifier.Rule{
+			"fields": {KeepProperties: []string{"name", "number"}},
+		},
+	}
+	if rule := RuleFromFieldMask(mask, true); !reflect.DeepEqual(rule, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rule)
+	}
+
+	mask = &fieldmaskpb.FieldMask{Paths: []string{"fields.json_name", "syntax", "syntax.value"}}
+	expected = &gosimplifier.Rule{
+		RemoveProperties: []string{"syntax"},
+		PropertySimplifiers: map[string]*gosimplifier.Rule{
+			"fields": {RemoveProperties: []string{"json_name"}},
+		},
+	}
+	if rule := RuleFromFieldMask(mask, false); !reflect.DeepEqual(rule, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rule)
+	}
+
+	if rule := RuleFromFieldMask(nil, true); !reflect.DeepEqual(rule, &gosimplifier.Rule{}) {
+		t.Errorf("Expected an empty rule, got %+v", rule)
+	}
+}
+
+func TestSimplifyWithFieldMask(t *testing.T) {
+	message := &typepb.Type{
+		Name:          "User",
+		Fields:        []*typepb.Field{{Name: "id", Number: 1, JsonName: "id", Kind: typepb.Field_TYPE_INT64}},
+		Oneofs:        []string{"contact"},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "user.proto"},
+		Syntax:        typepb.Syntax_SYNTAX_PROTO3,
+	}
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"name", "fields.name", "fields.number", "source_context"}}
+	simplifier, err := gosimplifier.NewSimplifierByRule(RuleFromFieldMask(mask, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &typepb.Type{
+		Name:          "User",
+		Fields:        []*typepb.Field{{Name: "id", Number: 1}},
+		SourceContext: &sourcecontextpb.SourceContext{FileName: "user.proto"},
+	}
+	if !proto.Equal(simplified.(proto.Message), expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+
+	simplifier, err = gosimplifier.NewSimplifierByRule(RuleFromFieldMask(mask, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = simplifier.Simplify(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = &typepb.Type{
+		Fields: []*typepb.Field{{JsonName: "id", Kind: typepb.Field_TYPE_INT64}},
+		Oneofs: []string{"contact"},
+		Syntax: typepb.Syntax_SYNTAX_PROTO3,
+	}
+	if !proto.Equal(simplified.(proto.Message), expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
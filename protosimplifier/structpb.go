@@ -0,0 +1,220 @@
+// Package protosimplifier connects gosimplifier to protocol buffers.
+package protosimplifier
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xhinliang/gosimplifier"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SimplifyToStructpb simplifies v with s and returns the result as a protobuf Struct, converting
+// the value directly instead of marshaling it to JSON and back. The simplified value must be a
+// struct or a map, and is converted the way encoding/json would encode it.
+func SimplifyToStructpb(s gosimplifier.Simplifier, v interface{}) (*structpb.Struct, error) {
+	value, err := SimplifyToValue(s, v)
+	if value == nil {
+		return nil, err
+	}
+	structValue := value.GetStructValue()
+	if structValue == nil {
+		return nil, fmt.Errorf("SimplifyToStructpb: %T is not converted to a JSON object", v)
+	}
+	return structValue, err
+}
+
+// SimplifyToValue simplifies v with s and returns the result as a protobuf Value, see SimplifyToStructpb.
+func SimplifyToValue(s gosimplifier.Simplifier, v interface{}) (*structpb.Value, error) {
+	simplified, err := s.Simplify(v)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil, err
+	}
+	value, convertErr := toValue(reflect.ValueOf(simplified))
+	if convertErr != nil {
+		return nil, convertErr
+	}
+	return value, err
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// toValue converts value like encoding/json would encode it.
+func toValue(value reflect.Value) (*structpb.Value, error) {
+	if !value.IsValid() {
+		return structpb.NewNullValue(), nil
+	}
+	valueType := value.Type()
+	if valueType == jsonNumberType {
+		number, err := strconv.ParseFloat(value.String(), 64)
+		if err != nil {
+			return nil, err
+		}
+		return structpb.NewNumberValue(number), nil
+	}
+	if valueType.Implements(jsonMarshalerType) || valueType.Implements(textMarshalerType) {
+		if (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil() {
+			return structpb.NewNullValue(), nil
+		}
+		return marshaledValue(value.Interface())
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return structpb.NewNullValue(), nil
+		}
+		return toValue(value.Elem())
+	case reflect.Bool:
+		return structpb.NewBoolValue(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return structpb.NewNumberValue(float64(value.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return structpb.NewNumberValue(float64(value.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return structpb.NewNumberValue(value.Float()), nil
+	case reflect.String:
+		return structpb.NewStringValue(value.String()), nil
+	case reflect.Slice:
+		if value.IsNil() {
+			return structpb.NewNullValue(), nil
+		}
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return structpb.NewStringValue(base64.StdEncoding.EncodeToString(value.Bytes())), nil
+		}
+		return listValue(value)
+	case reflect.Array:
+		return listValue(value)
+	case reflect.Map:
+		if value.IsNil() {
+			return structpb.NewNullValue(), nil
+		}
+		fields := make(map[string]*structpb.Value, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			if fields[key], err = toValue(iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+	case reflect.Struct:
+		fields := make(map[string]*structpb.Value, value.NumField())
+		if err := addStructFields(fields, value); err != nil {
+			return nil, err
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to a protobuf Value", valueType)
+}
+
+func listValue(value reflect.Value) (*structpb.Value, error) {
+	values := make([]*structpb.Value, value.Len())
+	for i := range values {
+		var err error
+		if values[i], err = toValue(value.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+}
+
+// marshaledValue converts a value with its own JSON or text encoding through that encoding.
+func marshaledValue(v interface{}) (*structpb.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	value := &structpb.Value{}
+	if err := value.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func mapKey(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("cannot convert map key of type %s to a protobuf Struct key", key.Type())
+}
+
+// addStructFields adds the exported fields of value under their JSON names, honoring the
+// "-" and omitempty options and inlining embedded structs without a JSON name.
+func addStructFields(fields map[string]*structpb.Value, value reflect.Value) error {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldValue := value.Field(i)
+		if field.Anonymous && name == "" {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructFields(fields, embedded); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(fieldValue) {
+			continue
+		}
+		converted, err := toValue(fieldValue)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", valueType, field.Name, err)
+		}
+		fields[name] = converted
+	}
+	return nil
+}
+
+// isEmptyValue reports whether encoding/json omits the value of an omitempty field.
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return value.IsZero() && value.Kind() != reflect.Struct
+}
@@ -0,0 +1,85 @@
+package protosimplifier
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/xhinliang/gosimplifier"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type Base struct {
+	ID int `json:"id"`
+}
+
+type AuditEvent struct {
+	Base
+	Actor    string            `json:"actor"`
+	Password string            `json:"password,omitempty"`
+	Internal string            `json:"-"`
+	At       time.Time         `json:"at"`
+	Payload  []byte            `json:"payload"`
+	Labels   map[string]string `json:"labels"`
+	Targets  []*Target         `json:"targets"`
+}
+
+type Target struct {
+	Name   string
+	Secret string `json:",omitempty"`
+}
+
+func TestSimplifyToStructpb(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{
+		"remove_properties": [ "Password" ],
+		"property_simplifiers": { "Targets": { "remove_properties": [ "Secret" ] } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := AuditEvent{
+		Base:     Base{ID: 7},
+		Actor:    "john",
+		Password: "secret",
+		Internal: "internal",
+		At:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Payload:  []byte("hi"),
+		Labels:   map[string]string{"env": "prod"},
+		Targets:  []*Target{{Name: "db", Secret: "s"}, nil},
+	}
+
+	result, err := SimplifyToStructpb(simplifier, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{}
+	viaJSON, _ := simplifier.Simplify(event)
+	data, _ := json.Marshal(viaJSON)
+	json.Unmarshal(data, &expected)
+
+	actual := map[string]interface{}{}
+	data, _ = protojson.Marshal(result)
+	json.Unmarshal(data, &actual)
+	if expectedJSON, actualJSON := mustMarshal(expected), mustMarshal(actual); expectedJSON != actualJSON {
+		t.Errorf("Expected %s, got %s", expectedJSON, actualJSON)
+	}
+	if _, ok := result.Fields["password"]; ok {
+		t.Error("Expected the password to be removed")
+	}
+}
+
+func TestSimplifyToStructpbNotObject(t *testing.T) {
+	simplifier, _ := gosimplifier.NewSimplifier(`{}`)
+	if _, err := SimplifyToStructpb(simplifier, []string{"a"}); err == nil {
+		t.Error("Expected an error for a list")
+	}
+	value, err := SimplifyToValue(simplifier, []string{"a"})
+	if err != nil || value.GetListValue().GetValues()[0].GetStringValue() != "a" {
+		t.Errorf("Unexpected value %v, %v", value, err)
+	}
+}
+
+func mustMarshal(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
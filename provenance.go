@@ -0,0 +1,51 @@
+package gosimplifier
+
+import "reflect"
+
+// WithProvenance records which rule produced every removal. When the simplified value is a
+// map with string keys, the records are attached to it under key as a parallel metadata map:
+//
+//	{
+//	  "name": "John",
+//	  "$provenance": {
+//	    "password":      { "action": "remove", "rule": "password" },
+//	    "profile.email": { "action": "remove", "rule": "profile.email" }
+//	  }
+//	}
+//
+// Entries are keyed by the path of the affected value. Non-map outputs are left untouched.
+func WithProvenance(key string) Option {
+	return func(o *options) {
+		o.provenanceKey = key
+		o.middlewares = append(o.middlewares, provenanceMiddleware)
+	}
+}
+
+// provenanceMiddleware records the rule acting on every node that the rules modify.
+func provenanceMiddleware(next Walker) Walker {
+	return func(node *Node) error {
+		if provenance := node.walk.provenance; provenance != nil {
+			if action := node.Action(); action != "" {
+				provenance[node.Path()] = map[string]interface{}{
+					"action": action,
+					"rule":   node.RulePath(),
+				}
+			}
+		}
+		return next(node)
+	}
+}
+
+// attachProvenance stores the provenance records in the simplified value if it is a map
+// that can hold them.
+func attachProvenance(value reflect.Value, key string, provenance map[string]interface{}) {
+	value = indirect(value)
+	if value.Kind() != reflect.Map || value.IsNil() || value.Type().Key().Kind() != reflect.String {
+		return
+	}
+	records := reflect.ValueOf(provenance)
+	if !records.Type().AssignableTo(value.Type().Elem()) {
+		return
+	}
+	value.SetMapIndex(reflect.ValueOf(key).Convert(value.Type().Key()), records)
+}
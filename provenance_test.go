@@ -0,0 +1,75 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithProvenance(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "password" ],
+		"property_simplifiers": {
+			"profile": {
+				"remove_properties": [ "email" ]
+			}
+		}
+	}`, WithProvenance("$provenance"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := map[string]interface{}{
+		"name":     "John",
+		"password": "secret",
+		"profile": map[string]interface{}{
+			"email": "john@example.com",
+			"city":  "Berlin",
+		},
+	}
+
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name": "John",
+		"profile": map[string]interface{}{
+			"city": "Berlin",
+		},
+		"$provenance": map[string]interface{}{
+			"password": map[string]interface{}{
+				"action": "remove",
+				"rule":   "password",
+			},
+			"profile.email": map[string]interface{}{
+				"action": "remove",
+				"rule":   "profile.email",
+			},
+		},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	if _, ok := original["password"]; !ok {
+		t.Error("Expected the original map to be unchanged")
+	}
+	if _, ok := original["$provenance"]; ok {
+		t.Error("Expected the provenance to be attached to the copy only")
+	}
+	if _, ok := original["profile"].(map[string]interface{})["email"]; !ok {
+		t.Error("Expected the original nested map to be unchanged")
+	}
+}
+
+func TestWithProvenanceIgnoresStructs(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithProvenance("$provenance"))
+
+	simplified, err := simplifier.Simplify(ExampleStruct{Test: 5, Debug: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := simplified.(ExampleStruct); result.Debug != "" || result.Test != 5 {
+		t.Errorf("Unexpected result %v", result)
+	}
+}
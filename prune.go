@@ -0,0 +1,38 @@
+package gosimplifier
+
+// prune starts removing the values nested more than prune_below_depth levels below the node,
+// unless an enclosing rule already prunes closer to the root.
+func (s *simplifierImpl) prune(node *Node) {
+	n := s.rule.PruneBelowDepth
+	if n > 0 && (node.pruneDepth == 0 || node.Depth+n < node.pruneDepth) {
+		node.pruneDepth, node.pruner = node.Depth+n, s
+	}
+}
+
+// prunesChildren reports whether the children of the node are below the depth a rule prunes at.
+func (n *Node) prunesChildren() bool {
+	return n.pruneDepth > 0 && n.Depth >= n.pruneDepth
+}
+
+// pruned reports whether the node is removed by prune_below_depth.
+func (n *Node) pruned() bool {
+	return n.pruneDepth > 0 && n.Depth > n.pruneDepth
+}
+
+// prunes reports whether the rule or any of its nested rules prunes, in which case any value
+// may be removed.
+func (s *simplifierImpl) prunes(visited map[*simplifierImpl]bool) bool {
+	if visited[s] {
+		return false
+	}
+	visited[s] = true
+	if s.rule.PruneBelowDepth > 0 {
+		return true
+	}
+	for _, r := range s.propertySimplifiers {
+		if sub, ok := r.(*simplifierImpl); ok && sub.prunes(visited) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type PruneEvent struct {
+	ID      int
+	Payload map[string]interface{}
+	Info    *SubStruct
+}
+
+func TestPruneBelowDepth(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "debug" ],
+		"property_simplifiers": {
+			"Payload": {
+				"prune_below_depth": 2,
+				"removal": { "mode": "placeholder", "placeholder": "[pruned]" }
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := PruneEvent{
+		ID: 1,
+		Payload: map[string]interface{}{
+			"order": map[string]interface{}{
+				"id":    "o",
+				"debug": "d",
+				"items": []interface{}{map[string]interface{}{"sku": "s"}},
+			},
+		},
+		Info: &SubStruct{Test: "t"},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := PruneEvent{
+		ID: 1,
+		Payload: map[string]interface{}{
+			"order": map[string]interface{}{"id": "o", "items": []interface{}{"[pruned]"}},
+		},
+		Info: &SubStruct{Test: "t"},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	if _, ok := original.Payload["order"].(map[string]interface{})["items"].([]interface{}); !ok {
+		t.Errorf("Expected the original to be left unchanged, got %v", original.Payload)
+	}
+
+	data, err := SimplifyJSON(simplifier, []byte(`{"ID":1,"Payload":{"order":{"id":"o","items":[{"sku":"s"}]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"ID":1,"Payload":{"order":{"id":"o","items":["[pruned]"]}}}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+func TestPruneBelowDepthRoot(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"prune_below_depth": 1,
+		"property_simplifiers": { "a": { "prune_below_depth": 5 } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(map[string]interface{}{
+		"a": map[string]interface{}{"b": "c"},
+		"d": []interface{}{"e"},
+		"f": "g",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"a": map[string]interface{}{}, "d": []interface{}{nil}, "f": "g"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the enclosing rule to prune first, got %v", simplified)
+	}
+
+	report, err := DryRun(simplifier, map[string]interface{}{"a": map[string]interface{}{"b": "c"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changes) != 1 || report.Changes[0].Path != "a.b" || report.Changes[0].Action != "remove" {
+		t.Errorf("Expected the pruned value to be reported, got %+v", report.Changes)
+	}
+
+	if _, err := NewSimplifier(`{ "prune_below_depth": -1 }`); err == nil {
+		t.Error("Expected a negative depth to be rejected")
+	}
+}
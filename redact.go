@@ -0,0 +1,185 @@
+package gosimplifier
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+// RedactedKey is the key of a redaction envelope, {"$redacted": "<token>"}.
+const RedactedKey = "$redacted"
+
+// redactRuler replaces a value with a redaction envelope after handing it to the vault.
+type redactRuler struct {
+}
+
+var redactRulerSingleton = &redactRuler{}
+
+// WithVault sets the callback receiving every value replaced by redact_properties, together with
+// the token of the envelope that replaced it. The pair can later be used by Rehydrate to restore
+// the original value. An error returned by the vault aborts Simplify.
+//
+// Envelopes are map[string]interface{} values, so they can only be stored where such a map fits,
+// e.g. in decoded JSON or interface{} fields. Other values are reset to their zero value once
+// the vault stored them.
+func WithVault(vault func(token string, value interface{}) error) Option {
+	return func(o *options) {
+		o.vault = vault
+	}
+}
+
+func (r *redactRuler) apply(node *Node) error {
+	if !node.Value.IsValid() || node.Parent.Kind() == reflect.Invalid || !node.Value.CanInterface() {
+		return nil
+	}
+	token, err := newRedactionToken()
+	if err != nil {
+		return err
+	}
+	if report := node.walk.report; report == nil || !report.dryRun {
+		if err := node.walk.root.options.vault(token, node.Value.Interface()); err != nil {
+			return fmt.Errorf("vault %s: %w", node.Path(), err)
+		}
+	}
+	envelope := map[string]interface{}{RedactedKey: token}
+	if !node.set(reflect.ValueOf(envelope)) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *redactRuler) action() string {
+	return "redact"
+}
+
+func newRedactionToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// usesRedaction reports whether the rule or any of its nested or type rules has
+// redact_properties.
+func usesRedaction(rule *Rule) bool {
+	if rule == nil {
+		return false
+	}
+	if len(rule.RedactProperties) > 0 {
+		return true
+	}
+	for _, subRule := range rule.PropertySimplifiers {
+		if usesRedaction(subRule) {
+			return true
+		}
+	}
+	for _, typeRule := range rule.TypeSimplifiers {
+		if usesRedaction(typeRule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rehydrate returns a copy of v where every redaction envelope is replaced by the value that
+// load returns for its token. The original v is not modified.
+func Rehydrate(v interface{}, load func(token string) (interface{}, error)) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	original := reflect.ValueOf(v)
+	cp, err := deepCopy(reflect.New(original.Type()).Elem(), original)
+	if err != nil {
+		return nil, err
+	}
+	if token, ok := envelopeToken(cp); ok {
+		return load(token)
+	}
+	if err := rehydrateChildren(cp, load); err != nil {
+		return nil, err
+	}
+	return cp.Interface(), nil
+}
+
+// rehydrateChildren replaces the envelopes held by value.
+func rehydrateChildren(value reflect.Value, load func(token string) (interface{}, error)) error {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Map:
+		for _, mapKey := range value.MapKeys() {
+			mapValue := value.MapIndex(mapKey)
+			if token, ok := envelopeToken(mapValue); ok {
+				restored, err := loadEnvelope(token, value.Type().Elem(), load)
+				if err != nil {
+					return err
+				}
+				value.SetMapIndex(mapKey, restored)
+				continue
+			}
+			if err := rehydrateChildren(mapValue, load); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := rehydrateSlot(value.Index(i), load); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if err := rehydrateSlot(value.Field(i), load); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rehydrateSlot restores slot if it holds an envelope, or the envelopes below it otherwise.
+func rehydrateSlot(slot reflect.Value, load func(token string) (interface{}, error)) error {
+	token, ok := envelopeToken(slot)
+	if !ok || !slot.CanSet() {
+		return rehydrateChildren(slot, load)
+	}
+	restored, err := loadEnvelope(token, slot.Type(), load)
+	if err != nil {
+		return err
+	}
+	slot.Set(restored)
+	return nil
+}
+
+// loadEnvelope loads the value of token as a value assignable to slotType.
+func loadEnvelope(token string, slotType reflect.Type, load func(token string) (interface{}, error)) (reflect.Value, error) {
+	restored, err := load(token)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("rehydrate %s: %w", token, err)
+	}
+	if restored == nil {
+		return reflect.Zero(slotType), nil
+	}
+	restoredValue := reflect.ValueOf(restored)
+	if !restoredValue.Type().AssignableTo(slotType) {
+		return reflect.Value{}, fmt.Errorf("rehydrate %s: %s is not assignable to %s", token, restoredValue.Type(), slotType)
+	}
+	return restoredValue, nil
+}
+
+// envelopeToken returns the token if value is a redaction envelope.
+func envelopeToken(value reflect.Value) (string, bool) {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+	if !value.IsValid() || !value.CanInterface() {
+		return "", false
+	}
+	envelope, ok := value.Interface().(map[string]interface{})
+	if !ok || len(envelope) != 1 {
+		return "", false
+	}
+	token, ok := envelope[RedactedKey].(string)
+	return token, ok
+}
@@ -0,0 +1,121 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type RedactStruct struct {
+	Name   string
+	Secret string
+	Extra  interface{}
+}
+
+func TestRedactAndRehydrate(t *testing.T) {
+	vault := make(map[string]interface{})
+	simplifier, err := NewSimplifier(`{
+		"redact_properties": [ "password" ],
+		"property_simplifiers": {
+			"cards": {
+				"redact_properties": [ "number" ]
+			}
+		}
+	}`, WithVault(func(token string, value interface{}) error {
+		vault[token] = value
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := map[string]interface{}{
+		"user":     "john",
+		"password": "secret",
+		"cards": []interface{}{
+			map[string]interface{}{"number": "4111", "type": "visa"},
+		},
+	}
+
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := simplified.(map[string]interface{})
+	envelope, ok := result["password"].(map[string]interface{})
+	if !ok || envelope[RedactedKey] == nil {
+		t.Fatalf("Expected password to be replaced by an envelope, got %v", result["password"])
+	}
+	if vault[envelope[RedactedKey].(string)] != "secret" {
+		t.Error("Expected the vault to receive the redacted value")
+	}
+	if len(vault) != 2 {
+		t.Errorf("Expected 2 vaulted values, got %d", len(vault))
+	}
+	if original["password"] != "secret" {
+		t.Error("Expected the original to be unchanged")
+	}
+
+	rehydrated, err := Rehydrate(simplified, func(token string) (interface{}, error) {
+		return vault[token], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rehydrated, original) {
+		t.Errorf("Expected %v, got %v", original, rehydrated)
+	}
+	if _, ok := result["password"].(map[string]interface{}); !ok {
+		t.Error("Expected Rehydrate to leave its input unchanged")
+	}
+}
+
+func TestRedactStructFields(t *testing.T) {
+	vault := make(map[string]interface{})
+	simplifier, _ := NewSimplifier(`{ "redact_properties": [ "Secret", "Extra" ] }`, WithVault(func(token string, value interface{}) error {
+		vault[token] = value
+		return nil
+	}))
+
+	simplified, err := simplifier.Simplify(&RedactStruct{Name: "john", Secret: "secret", Extra: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := simplified.(*RedactStruct)
+	if result.Secret != "" {
+		t.Error("Expected Secret, which cannot hold an envelope, to be reset")
+	}
+	if _, ok := result.Extra.(map[string]interface{}); !ok {
+		t.Errorf("Expected Extra to hold an envelope, got %v", result.Extra)
+	}
+
+	rehydrated, err := Rehydrate(result, func(token string) (interface{}, error) {
+		return vault[token], nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rehydrated.(*RedactStruct).Extra != 42 {
+		t.Errorf("Expected Extra to be rehydrated, got %v", rehydrated.(*RedactStruct).Extra)
+	}
+}
+
+func TestRedactRequiresVault(t *testing.T) {
+	if _, err := NewSimplifier(`{ "property_simplifiers": { "a": { "redact_properties": [ "b" ] } } }`); err == nil {
+		t.Error("Expected an error without a vault")
+	}
+	if _, err := NewSimplifier(`{ "type_simplifiers": { "pkg.T": { "redact_properties": [ "Token" ] } } }`); err == nil {
+		t.Error("Expected an error without a vault for a type rule")
+	}
+}
+
+func TestRedactVaultError(t *testing.T) {
+	errVault := errors.New("vault unavailable")
+	simplifier, _ := NewSimplifier(`{ "redact_properties": [ "password" ] }`, WithVault(func(token string, value interface{}) error {
+		return errVault
+	}))
+
+	if _, err := simplifier.Simplify(map[string]interface{}{"password": "secret"}); !errors.Is(err, errVault) {
+		t.Errorf("Expected the vault error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds named rule sets, so applications can register them at init and look the
+// simplifiers up by name where they are needed, instead of passing them around. It is safe for
+// concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*registered
+}
+
+type registered struct {
+	rule       *Rule
+	simplifier Simplifier
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[string]*registered)}
+}
+
+// Register compiles the JSON rules with the options and registers them under name. The rule
+// sets the rules extend are looked up in r, so they must be registered first. Registering a
+// name twice is an error.
+func (r *Registry) Register(name string, rulesJson string, opts ...Option) error {
+	rule := &Rule{}
+	if err := decodeRules([]byte(rulesJson), rule); err != nil {
+		return fmt.Errorf("rules %s: %w", name, err)
+	}
+	rule, err := r.resolveExtends(rule)
+	if err != nil {
+		return fmt.Errorf("rules %s: %w", name, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; ok {
+		return fmt.Errorf("rules %s are already registered", name)
+	}
+	s, err := newRootSimplifier(rule, newOptions(append([]Option{WithRegistry(r)}, opts...)))
+	if err != nil {
+		return fmt.Errorf("rules %s: %w", name, err)
+	}
+	r.entries[name] = &registered{rule: rule, simplifier: s}
+	return nil
+}
+
+// Get returns the Simplifier registered under name.
+func (r *Registry) Get(name string) (Simplifier, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.simplifier, true
+}
+
+// Names returns the registered names in ascending order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.entries))
+	for name := range r.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultRegistry is the registry of RegisterRules and Get.
+var defaultRegistry = NewRegistry()
+
+// RegisterRules registers the JSON rules under name in the package registry, see
+// Registry.Register:
+//
+//	func init() {
+//		gosimplifier.MustRegisterRules("public_api_v2", publicAPIRules)
+//	}
+func RegisterRules(name string, rulesJson string, opts ...Option) error {
+	return defaultRegistry.Register(name, rulesJson, opts...)
+}
+
+// MustRegisterRules is RegisterRules, panicking on error, for rules registered at init.
+func MustRegisterRules(name string, rulesJson string, opts ...Option) {
+	if err := RegisterRules(name, rulesJson, opts...); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns the Simplifier registered under name in the package registry.
+func Get(name string) (Simplifier, bool) {
+	return defaultRegistry.Get(name)
+}
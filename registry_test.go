@@ -0,0 +1,43 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("public_api_v2", `{ "remove_properties": [ "Debug" ] }`); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("public_api_v2", `{}`); err == nil {
+		t.Error("Expected an error registering a name twice")
+	}
+	if err := registry.Register("invalid", `{ "remove_properties": "Debug" }`); err == nil {
+		t.Error("Expected an error for invalid rules")
+	}
+	simplifier, ok := registry.Get("public_api_v2")
+	if !ok {
+		t.Fatal("Expected the rules to be registered")
+	}
+	simplified, err := simplifier.Simplify(ExampleStruct0{Test: 1, Debug: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, ExampleStruct0{Test: 1}) {
+		t.Errorf("Expected Debug to be removed, got %+v", simplified)
+	}
+	if _, ok := registry.Get("missing"); ok {
+		t.Error("Expected no Simplifier for an unregistered name")
+	}
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"public_api_v2"}) {
+		t.Errorf("Expected the registered names, got %v", names)
+	}
+}
+
+func TestRegisterRules(t *testing.T) {
+	MustRegisterRules("registry_test", `{ "remove_properties": [ "Debug" ] }`)
+	if _, ok := Get("registry_test"); !ok {
+		t.Error("Expected the rules to be registered in the package registry")
+	}
+}
@@ -0,0 +1,155 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithRemovedValue registers the value that removed struct fields of the same type are set to
+// instead of their Go zero value, for downstream schemas where zero values are meaningful data:
+//
+//	gosimplifier.WithRemovedValue(time.Unix(0, 0).UTC()) // time.Time fields become the epoch
+//	gosimplifier.WithRemovedValue(-1)                    // int fields become -1
+//	gosimplifier.WithRemovedValue("N/A")                 // string fields become "N/A"
+//
+// The registry is keyed by the exact dynamic type of value, so named types need their own
+// registration. Removed map entries are still deleted.
+func WithRemovedValue(value interface{}) Option {
+	return func(o *options) {
+		if value == nil {
+			return
+		}
+		if o.removedValues == nil {
+			o.removedValues = make(map[reflect.Type]reflect.Value)
+		}
+		o.removedValues[reflect.TypeOf(value)] = reflect.ValueOf(value)
+	}
+}
+
+// removedValue returns the value a removed value of type t is replaced with.
+func (o *options) removedValue(t reflect.Type) reflect.Value {
+	if value, ok := o.removedValues[t]; ok {
+		return value
+	}
+	return reflect.Zero(t)
+}
+
+// RemovalMode selects how removed values are removed, see WithRemovalMode.
+type RemovalMode string
+
+const (
+	// RemoveDelete deletes map entries, the default. Struct fields and list elements, which
+	// cannot be deleted, are reset to their zero value, or the value of WithRemovedValue.
+	RemoveDelete RemovalMode = "delete"
+	// RemoveZero resets map entries to their zero value as well, keeping the keys.
+	RemoveZero RemovalMode = "zero"
+	// RemovePlaceholder sets removed values to a placeholder, e.g. "[REMOVED]", so they cannot be
+	// mistaken for real data downstream. Values the placeholder does not fit are deleted.
+	RemovePlaceholder RemovalMode = "placeholder"
+)
+
+// RemovalRule configures how the properties removed by the same rule are removed, instead of
+// the mode of WithRemovalMode.
+//
+// Example, replacing the removed properties with a placeholder:
+//
+//	{
+//	  "remove_properties": [ "Balance", "Owner" ],
+//	  "removal": { "mode": "placeholder", "placeholder": "[REMOVED]" }
+//	}
+type RemovalRule struct {
+	Mode RemovalMode `json:"mode"`
+	// Placeholder is the value removed properties are set to with RemovePlaceholder. It is
+	// decoded like the literals of replace_properties, e.g. into -1 for an int field.
+	Placeholder interface{} `json:"placeholder,omitempty"`
+}
+
+// WithRemovalMode sets how the values the rules remove are removed, RemoveDelete by default.
+// Protobuf message fields are always cleared.
+func WithRemovalMode(mode RemovalMode) Option {
+	return func(o *options) {
+		o.removalRule.Mode = mode
+	}
+}
+
+// WithRemovalPlaceholder sets the values the rules remove to placeholder, in RemovePlaceholder
+// mode.
+func WithRemovalPlaceholder(placeholder interface{}) Option {
+	return func(o *options) {
+		o.removalRule = RemovalRule{Mode: RemovePlaceholder, Placeholder: placeholder}
+	}
+}
+
+// removal is the compiled form of a RemovalRule.
+type removal struct {
+	mode        RemovalMode
+	placeholder *replaceRuler
+}
+
+var defaultRemoval = &removal{mode: RemoveDelete}
+
+func newRemoval(rule *RemovalRule) (*removal, error) {
+	if rule == nil || rule.Mode == "" {
+		return nil, nil
+	}
+	switch rule.Mode {
+	case RemoveDelete, RemoveZero:
+		return &removal{mode: rule.Mode}, nil
+	case RemovePlaceholder:
+		if rule.Placeholder == nil {
+			return nil, fmt.Errorf("removal: placeholder mode requires a placeholder")
+		}
+		placeholder, err := newReplaceRuler("placeholder", rule.Placeholder)
+		if err != nil {
+			return nil, fmt.Errorf("removal: %w", err)
+		}
+		return &removal{mode: RemovePlaceholder, placeholder: placeholder}, nil
+	}
+	return nil, fmt.Errorf("removal: unknown mode %q", rule.Mode)
+}
+
+// removalOf returns the removal of the node: that of the rules removing it, or pruning it,
+// or else that of the options.
+func removalOf(node *Node) *removal {
+	if node.pruned() && node.pruner.removal != nil {
+		return node.pruner.removal
+	}
+	if !node.pruned() && node.rules != nil && node.rules.removal != nil {
+		return node.rules.removal
+	}
+	if o := node.walk.root.options; o.removal != nil {
+		return o.removal
+	}
+	return defaultRemoval
+}
+
+// placeholderOf returns the placeholder replacing a value of type t, or an invalid value if there
+// is none or it does not fit.
+func (r *removal) placeholderOf(t reflect.Type) reflect.Value {
+	if r.mode != RemovePlaceholder {
+		return reflect.Value{}
+	}
+	placeholder, err := r.placeholder.replacement(t)
+	if err != nil {
+		return reflect.Value{}
+	}
+	return placeholder
+}
+
+// usesRemovalModes reports whether the rule or any of its nested rules removes values other
+// than by deleting them.
+func (s *simplifierImpl) usesRemovalModes(visited map[*simplifierImpl]bool) bool {
+	if visited[s] {
+		return false
+	}
+	visited[s] = true
+	if s.removal != nil && s.removal.mode != RemoveDelete {
+		return true
+	}
+	for _, r := range s.propertySimplifiers {
+		if sub, ok := r.(*simplifierImpl); ok && sub.usesRemovalModes(visited) {
+			return true
+		}
+	}
+	return false
+}
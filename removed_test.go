@@ -0,0 +1,143 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type RemovedValueStruct struct {
+	Name    string
+	Count   int
+	Ratio   float64
+	Created time.Time
+	Kept    string
+}
+
+func TestWithRemovedValue(t *testing.T) {
+	epoch := time.Unix(0, 0).UTC()
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Name", "Count", "Ratio", "Created" ] }`,
+		WithRemovedValue("N/A"),
+		WithRemovedValue(-1),
+		WithRemovedValue(epoch),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(&RemovedValueStruct{
+		Name:    "john",
+		Count:   3,
+		Ratio:   0.5,
+		Created: time.Now(),
+		Kept:    "kept",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := simplified.(*RemovedValueStruct)
+	if result.Name != "N/A" || result.Count != -1 || !result.Created.Equal(epoch) {
+		t.Errorf("Expected the registered removed values, got %+v", result)
+	}
+	if result.Ratio != 0 {
+		t.Errorf("Expected unregistered types to be zeroed, got %v", result.Ratio)
+	}
+	if result.Kept != "kept" {
+		t.Errorf("Expected Kept to be unchanged, got %v", result.Kept)
+	}
+
+	mapResult, err := simplifier.Simplify(map[string]interface{}{"Name": "john"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mapResult.(map[string]interface{})["Name"]; ok {
+		t.Error("Expected map entries to still be deleted")
+	}
+}
+
+func TestRemovalModes(t *testing.T) {
+	rules := `{ "remove_properties": [ "Name", "Count" ] }`
+	document := map[string]interface{}{"Name": "john", "Count": 3, "Kept": "kept"}
+
+	simplifier, err := NewSimplifier(rules, WithRemovalMode(RemoveZero))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"Name": nil, "Count": nil, "Kept": "kept"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the keys to be kept with zero values, got %v", simplified)
+	}
+	data, err := SimplifyJSON(simplifier, []byte(`{"Name":"john","Kept":"kept"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Kept":"kept","Name":null}` {
+		t.Errorf("Expected the JSON members to be nulled, got %s", data)
+	}
+
+	simplifier, err = NewSimplifier(rules, WithRemovalPlaceholder("[REMOVED]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err = simplifier.Simplify(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = map[string]interface{}{"Name": "[REMOVED]", "Count": "[REMOVED]", "Kept": "kept"}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected the placeholders, got %v", simplified)
+	}
+	structResult, err := simplifier.Simplify(RemovedValueStruct{Name: "john", Count: 3, Kept: "kept"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(structResult, RemovedValueStruct{Name: "[REMOVED]", Kept: "kept"}) {
+		t.Errorf("Expected the placeholder where it fits and zero values elsewhere, got %+v", structResult)
+	}
+}
+
+func TestRemovalRule(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Name" ],
+		"property_simplifiers": {
+			"Account": {
+				"remove_properties": [ "Count", "Owner" ],
+				"removal": { "mode": "placeholder", "placeholder": -1 }
+			}
+		}
+	}`, WithRemovalMode(RemoveZero))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(map[string]interface{}{
+		"Name":    "john",
+		"Account": map[string]int{"Count": 3, "Owner": 7, "Kept": 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"Name":    nil,
+		"Account": map[string]int{"Count": -1, "Owner": -1, "Kept": 1},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+
+	for _, rules := range []string{
+		`{ "removal": { "mode": "erase" } }`,
+		`{ "removal": { "mode": "placeholder" } }`,
+	} {
+		if _, err := NewSimplifier(rules); err == nil {
+			t.Errorf("Expected an error for %s", rules)
+		}
+	}
+	if _, err := NewSimplifier(`{}`, WithRemovalMode("erase")); err == nil {
+		t.Error("Expected an error for an unknown mode option")
+	}
+}
@@ -0,0 +1,31 @@
+package gosimplifier
+
+import "reflect"
+
+// renameKeys moves the entries of the map value whose keys are named by rename_properties to
+// their new keys, replacing any entry already held by a new key. Entries removed by the rules
+// are not renamed.
+func (s *simplifierImpl) renameKeys(mapValue reflect.Value) {
+	keyType := mapValue.Type().Key()
+	if len(s.rule.RenameProperties) == 0 || keyType.Kind() != reflect.String {
+		return
+	}
+	type rename struct {
+		value  reflect.Value
+		newKey reflect.Value
+	}
+	var renames []rename
+	for _, oldName := range sortedKeys(s.rule.RenameProperties) {
+		oldKey := reflect.ValueOf(oldName).Convert(keyType)
+		value := mapValue.MapIndex(oldKey)
+		if !value.IsValid() {
+			continue
+		}
+		mapValue.SetMapIndex(oldKey, reflect.Value{})
+		renames = append(renames, rename{value, reflect.ValueOf(s.rule.RenameProperties[oldName]).Convert(keyType)})
+	}
+	// the entries are only set once every old key is gone, so renames can swap keys
+	for _, r := range renames {
+		mapValue.SetMapIndex(r.newKey, r.value)
+	}
+}
@@ -0,0 +1,58 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenameProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "pwd" ],
+		"rename_properties": { "usr": "user", "pwd": "password", "a": "b", "b": "a" },
+		"property_simplifiers": {
+			"usr": {
+				"remove_properties": [ "token" ],
+				"rename_properties": { "fullName": "name" }
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := map[string]interface{}{
+		"usr": map[string]interface{}{"fullName": "John", "token": "secret"},
+		"pwd": "secret",
+		"a":   1,
+		"b":   2,
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"user": map[string]interface{}{"name": "John"},
+		"a":    2,
+		"b":    1,
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+
+	output, err := SimplifyJSON(simplifier, []byte(`{"usr":{"fullName":"John"},"pwd":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(output) != `{"user":{"name":"John"}}` {
+		t.Errorf("Unexpected JSON %s", output)
+	}
+}
+
+func TestRenamePropertiesOnStruct(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "rename_properties": { "Debug": "debug" } }`)
+	err := ValidateForType(simplifier, reflect.TypeOf(ExampleStruct{}))
+	if err == nil || !strings.Contains(err.Error(), "Debug: rename_properties entry has no effect") {
+		t.Errorf("Expected the rename to be reported, got %v", err)
+	}
+}
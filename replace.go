@@ -0,0 +1,51 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// replaceRuler replaces a value with a literal given in the rules.
+type replaceRuler struct {
+	// literal is the JSON form of the replacement
+	literal []byte
+}
+
+func newReplaceRuler(propName string, replacement interface{}) (*replaceRuler, error) {
+	literal, err := json.Marshal(replacement)
+	if err != nil {
+		return nil, fmt.Errorf("replace_properties %q: %w", propName, err)
+	}
+	return &replaceRuler{literal: literal}, nil
+}
+
+// replacement decodes a fresh copy of the literal as a value of type t, so replaced values
+// never share maps or slices.
+func (r *replaceRuler) replacement(t reflect.Type) (reflect.Value, error) {
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(r.literal, ptr.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return ptr.Elem(), nil
+}
+
+// apply replaces the node with the literal. Values the literal does not fit are removed.
+func (r *replaceRuler) apply(node *Node) error {
+	if !node.Value.IsValid() || node.Parent.Kind() == reflect.Invalid {
+		return nil
+	}
+	slotType := node.Value.Type()
+	if node.Parent.Kind() == reflect.Map {
+		slotType = node.Parent.Type().Elem()
+	}
+	replacement, err := r.replacement(slotType)
+	if err != nil || !node.set(replacement) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *replaceRuler) action() string {
+	return "replace"
+}
@@ -0,0 +1,48 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Login struct {
+	User     string
+	Password string
+	Attempts int
+	Tags     []string
+}
+
+func TestReplaceProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"replace_properties": {
+			"Password": "[REDACTED]",
+			"Attempts": -1,
+			"Tags": [ "hidden" ],
+			"User": 5
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(Login{User: "john", Password: "secret", Attempts: 3, Tags: []string{"a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Login{Password: "[REDACTED]", Attempts: -1, Tags: []string{"hidden"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	simplified, _ = simplifier.Simplify(map[string]interface{}{"Password": "secret", "Tags": []interface{}{"a"}})
+	expectedMap := map[string]interface{}{"Password": "[REDACTED]", "Tags": []interface{}{"hidden"}}
+	if !reflect.DeepEqual(simplified, expectedMap) {
+		t.Errorf("Expected %v, got %v", expectedMap, simplified)
+	}
+
+	err = ValidateForType(simplifier, reflect.TypeOf(Login{}))
+	if err == nil || !strings.Contains(err.Error(), "User: replacement 5 does not fit string") {
+		t.Errorf("Expected the User replacement to be reported, got %v", err)
+	}
+}
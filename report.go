@@ -0,0 +1,121 @@
+package gosimplifier
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Report lists the changes the rules make to a value, in traversal order.
+type Report struct {
+	Changes []Change
+	// dryRun is set by DryRun, so redacted values are not handed to the vault
+	dryRun bool
+}
+
+// Change describes what a rule does to a value.
+type Change struct {
+	// Path is the location of the value, e.g. "EntityList[3].SubProperties.ABC"
+	Path string
+	// Action is what the rule does, e.g. "remove" or "mask"
+	Action string
+	// Rule is the location of the rule in the rule tree, see Node.RulePath
+	Rule string
+}
+
+// Removed returns the paths of the removed values, which are zeroed when they are struct fields.
+func (r *Report) Removed() []string {
+	var paths []string
+	for _, change := range r.Changes {
+		if change.Action == removeRulerSingleton.action() {
+			paths = append(paths, change.Path)
+		}
+	}
+	return paths
+}
+
+// record adds the change the rules make to node, if any.
+func (r *Report) record(node *Node) {
+	if node.parent == nil {
+		return
+	}
+	if action := node.Action(); action != "" {
+		r.Changes = append(r.Changes, Change{Path: node.Path(), Action: action, Rule: node.RulePath()})
+	}
+}
+
+// DryRunSimplifier is a Simplifier reporting its changes without making them, see DryRun.
+type DryRunSimplifier interface {
+	Simplifier
+	// DryRun reports what Simplify would change in original.
+	DryRun(original interface{}) (*Report, error)
+}
+
+// DryRun reports what s would change in original, without returning the simplified copy, so
+// rule changes can be reviewed against real payloads before they are rolled out. It fails for
+// simplifiers that are not a DryRunSimplifier.
+func DryRun(s Simplifier, original interface{}) (*Report, error) {
+	if ds, ok := s.(DryRunSimplifier); ok {
+		return ds.DryRun(original)
+	}
+	return nil, fmt.Errorf("DryRun is not supported by %T", s)
+}
+
+// AuditSimplifier is a Simplifier recording the values it removes, see SimplifyWithAudit.
+type AuditSimplifier interface {
+	Simplifier
+	// SimplifyWithAudit is Simplify, also returning the paths of the removed values.
+	SimplifyWithAudit(original interface{}) (interface{}, []string, error)
+}
+
+// SimplifyWithAudit is Simplify, also returning the paths of the removed values, e.g.
+// "EntityList[3].SubProperties.ABC", as a record of what was scrubbed. It fails for simplifiers
+// that are not an AuditSimplifier.
+func SimplifyWithAudit(s Simplifier, original interface{}) (interface{}, []string, error) {
+	if as, ok := s.(AuditSimplifier); ok {
+		return as.SimplifyWithAudit(original)
+	}
+	return nil, nil, fmt.Errorf("SimplifyWithAudit is not supported by %T", s)
+}
+
+// DryRun simplifies a copy of original and reports the changes. Redacted values are not handed
+// to the vault, but middlewares run as they do for Simplify. Protobuf messages are not
+// supported, as their fields are cleared without being walked.
+func (s *simplifierImpl) DryRun(original interface{}) (*Report, error) {
+	_, report, err := s.dryRun(original)
+	return report, err
+}
+
+// dryRun is DryRun, also returning the simplified copy.
+func (s *simplifierImpl) dryRun(original interface{}) (interface{}, *Report, error) {
+	report := &Report{dryRun: true}
+	if original == nil {
+		return nil, report, nil
+	}
+	if _, ok := original.(proto.Message); ok {
+		return nil, nil, fmt.Errorf("DryRun does not support protobuf messages, got %T", original)
+	}
+	simplified, err := s.simplifyCopy(context.Background(), original, report)
+	if err != nil && !isPartial(err) {
+		return nil, nil, err
+	}
+	return simplified, report, nil
+}
+
+// SimplifyWithAudit simplifies a copy of original and returns it with the removed paths.
+// Protobuf messages are not supported, see DryRun.
+func (s *simplifierImpl) SimplifyWithAudit(original interface{}) (interface{}, []string, error) {
+	if original == nil {
+		return nil, nil, nil
+	}
+	if _, ok := original.(proto.Message); ok {
+		return nil, nil, fmt.Errorf("SimplifyWithAudit does not support protobuf messages, got %T", original)
+	}
+	report := &Report{}
+	simplified, err := s.simplifyCopy(context.Background(), original, report)
+	if err != nil && !isPartial(err) {
+		return nil, nil, err
+	}
+	return simplified, report.Removed(), err
+}
@@ -0,0 +1,94 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var vaulted int
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"redact_properties": [ "Test" ],
+		"property_simplifiers": {
+			"Data": { "remove_properties": [ "DataDebug" ] },
+			"EntityList": {
+				"property_simplifiers": { "SubProperties": { "mask_properties": [ "ABC" ] } }
+			}
+		}
+	}`, WithVault(func(token string, value interface{}) error {
+		vaulted++
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := ExampleStruct0{
+		Test:       1,
+		Debug:      "d",
+		Data:       DataStruct{DataTest: "t", DataDebug: 2},
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{ABC: "abc", DEF: "def"}}},
+	}
+	report, err := DryRun(simplifier, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		{Path: "Data.DataDebug", Action: "remove", Rule: "Data.DataDebug"},
+		{Path: "Debug", Action: "remove", Rule: "Debug"},
+		{Path: "EntityList[0].SubProperties.ABC", Action: "mask", Rule: "EntityList.SubProperties.ABC"},
+		{Path: "Test", Action: "redact", Rule: "Test"},
+	}
+	if !reflect.DeepEqual(sortedChanges(report.Changes), expected) {
+		t.Errorf("Expected %+v, got %+v", expected, report.Changes)
+	}
+	if removed := report.Removed(); len(removed) != 2 {
+		t.Errorf("Expected two removed paths, got %v", removed)
+	}
+	if vaulted != 0 {
+		t.Errorf("Expected no value to be handed to the vault, got %d", vaulted)
+	}
+	if original.Debug != "d" || original.EntityList[0].SubProperties.ABC != "abc" {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+}
+
+func sortedChanges(changes []Change) []Change {
+	sorted := append([]Change(nil), changes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	return sorted
+}
+
+func TestSimplifyWithAudit(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"EntityList": {
+				"property_simplifiers": { "SubProperties": { "remove_properties": [ "ABC" ] } }
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := ExampleStruct0{
+		Debug: "d",
+		EntityList: []EntityStruct{
+			{SubProperties: SubPropertyStruct{ABC: "a0"}},
+			{SubProperties: SubPropertyStruct{ABC: "a1", DEF: "d1"}},
+		},
+	}
+	simplified, removed, err := SimplifyWithAudit(simplifier, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ExampleStruct0{EntityList: []EntityStruct{{}, {SubProperties: SubPropertyStruct{DEF: "d1"}}}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	expectedRemoved := []string{"Debug", "EntityList[0].SubProperties.ABC", "EntityList[1].SubProperties.ABC"}
+	if !reflect.DeepEqual(removed, expectedRemoved) {
+		t.Errorf("Expected the removed paths %v, got %v", expectedRemoved, removed)
+	}
+}
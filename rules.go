@@ -0,0 +1,83 @@
+package gosimplifier
+
+import "encoding/json"
+
+// MarshalJSON encodes the rule in the format NewSimplifier reads, leaving out empty sections.
+func (r Rule) MarshalJSON() ([]byte, error) {
+	// rule has the fields of Rule but not its methods, so it is encoded field by field
+	type rule Rule
+	return json.Marshal(struct {
+		*rule
+		RemoveProperties    []string         `json:"remove_properties,omitempty"`
+		PropertySimplifiers map[string]*Rule `json:"property_simplifiers,omitempty"`
+	}{(*rule)(&r), r.RemoveProperties, r.PropertySimplifiers})
+}
+
+// RuleSimplifier is a Simplifier exposing its rules, see Rules.
+type RuleSimplifier interface {
+	Simplifier
+	// Rules returns a copy of the effective rules.
+	Rules() *Rule
+}
+
+// Rules returns a copy of the effective rules of s, with the rules of ExtendSimplifier and
+// "extends" merged in, so they can be dumped and reviewed, or nil if s is not a RuleSimplifier.
+func Rules(s Simplifier) *Rule {
+	if rs, ok := s.(RuleSimplifier); ok {
+		return rs.Rules()
+	}
+	return nil
+}
+
+// CloneableSimplifier is a Simplifier that can be cloned, see Clone.
+type CloneableSimplifier interface {
+	Simplifier
+	// Clone returns an independent simplifier with the same rules and options.
+	Clone() Simplifier
+}
+
+// Clone returns an independent simplifier with the rules and options of s. Simplifiers are
+// immutable once created and safe for concurrent use, so Clone is only needed to get a
+// simplifier that shares no caches with the original; s itself is returned if it is not a
+// CloneableSimplifier.
+func Clone(s Simplifier) Simplifier {
+	if cs, ok := s.(CloneableSimplifier); ok {
+		return cs.Clone()
+	}
+	return s
+}
+
+// Rules returns a deep copy of the rules of s, in which the globstar names, e.g. "**.Password",
+// are in every nested rule as well, without their prefix.
+func (s *simplifierImpl) Rules() *Rule {
+	rule, err := cloneRule(s.fullRule())
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+// Clone returns a new simplifier with the rules and options of s.
+func (s *simplifierImpl) Clone() Simplifier {
+	// the clone compiles into its own copy of the options, which Simplify on s keeps reading
+	options := *s.options
+	clone, err := newRootSimplifier(s.Rules(), &options)
+	if err != nil {
+		// the rules of s compiled with the same options
+		panic(err)
+	}
+	return clone
+}
+
+// cloneRule returns a deep copy of rule, which shares nothing with it.
+func cloneRule(rule *Rule) (*Rule, error) {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	clone := &Rule{}
+	if err := json.Unmarshal(data, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
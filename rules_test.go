@@ -0,0 +1,134 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	base, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": { "Data": { "remove_properties": [ "DataDebug" ] } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extended, err := ExtendSimplifier(base, `{
+		"mask_properties": [ "Test" ],
+		"property_simplifiers": { "Data": { "remove_properties": [ "DataTest" ] } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := Rules(extended)
+	data, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"mask_properties":["Test"],"remove_properties":["Debug"],` +
+		`"property_simplifiers":{"Data":{"remove_properties":["DataDebug","DataTest"]}}}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	rules.PropertySimplifiers["Data"].RemoveProperties[0] = "Changed"
+	if Rules(extended).PropertySimplifiers["Data"].RemoveProperties[0] != "DataDebug" {
+		t.Error("Expected Rules to return a copy")
+	}
+	if _, err := NewSimplifier(string(data)); err != nil {
+		t.Errorf("Expected the encoded rules to be accepted by NewSimplifier, got %v", err)
+	}
+}
+
+func TestNewSimplifierByRuleCopiesRule(t *testing.T) {
+	rule := &Rule{
+		RemoveProperties:    []string{"Debug"},
+		PropertySimplifiers: map[string]*Rule{"Data": {RemoveProperties: []string{"DataDebug"}}},
+	}
+	simplifier, err := NewSimplifierByRule(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule.RemoveProperties[0] = "Test"
+	rule.PropertySimplifiers["Data"].RemoveProperties = nil
+	rule.KeepProperties = []string{"Data"}
+
+	simplified, err := simplifier.Simplify(ExampleStruct0{Test: 1, Debug: "d", Data: DataStruct{DataTest: "t", DataDebug: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ExampleStruct0{Test: 1, Data: DataStruct{DataTest: "t"}}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected changes to the rule not to affect the simplifier, got %+v", simplified)
+	}
+}
+
+func TestClone(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithJSONFieldNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := Clone(simplifier)
+	if clone == simplifier {
+		t.Fatal("Expected a new simplifier")
+	}
+	simplified, err := clone.Simplify(SubStruct{Test: "t", Debug: "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified != (SubStruct{Test: "t"}) {
+		t.Errorf("Expected the clone to have the same rules, got %+v", simplified)
+	}
+	Rules(clone).RemoveProperties[0] = "Test"
+	if Rules(simplifier).RemoveProperties[0] != "Debug" || Rules(clone).RemoveProperties[0] != "Debug" {
+		t.Error("Expected the rules of the simplifiers not to be shared")
+	}
+}
+
+func TestCloneConcurrentSimplify(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithStats(NewStats()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, err := simplifier.Simplify(SubStruct{Test: "t", Debug: "d"}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		Clone(simplifier)
+	}
+	wg.Wait()
+}
+
+func TestExtendConcurrentSimplify(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithStats(NewStats()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, err := simplifier.Simplify(SubStruct{Test: "t", Debug: "d"}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := ExtendSimplifierByRule(simplifier, &Rule{RemoveProperties: []string{"Test"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
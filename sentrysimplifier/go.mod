@@ -0,0 +1,14 @@
+module github.com/xhinliang/gosimplifier/sentrysimplifier
+
+go 1.23.0
+
+require (
+	github.com/getsentry/sentry-go v0.40.0
+	github.com/xhinliang/gosimplifier v0.1.0
+)
+
+require (
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
@@ -0,0 +1,68 @@
+// Package sentrysimplifier connects gosimplifier to Sentry, simplifying the data attached to
+// events before they are sent, in place of hand-written scrubbers.
+package sentrysimplifier
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/xhinliang/gosimplifier"
+)
+
+// BeforeSend returns a hook for sentry.ClientOptions.BeforeSend, and BeforeSendTransaction,
+// simplifying with s the extras of the events, the data of their breadcrumbs, as maps from the
+// keys to the values, and the body of their request, as a JSON document:
+//
+//	sentry.Init(sentry.ClientOptions{
+//		Dsn:        dsn,
+//		BeforeSend: sentrysimplifier.BeforeSend(simplifier),
+//	})
+//
+// A request body that is not JSON cannot be matched against the rules, and data that cannot be
+// simplified are not sent either way, so such data are dropped from the event, which is sent
+// without them.
+func BeforeSend(s gosimplifier.Simplifier) func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	return func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		if event == nil {
+			return nil
+		}
+		event.Extra = simplifyMap(s, event.Extra)
+		for _, breadcrumb := range event.Breadcrumbs {
+			if breadcrumb != nil {
+				breadcrumb.Data = simplifyMap(s, breadcrumb.Data)
+			}
+		}
+		if event.Request != nil && event.Request.Data != "" {
+			event.Request.Data = simplifyBody(s, event.Request.Data)
+		}
+		return event
+	}
+}
+
+// simplifyMap returns the simplified copy of m, or nil if it cannot be simplified.
+func simplifyMap(s gosimplifier.Simplifier, m map[string]interface{}) map[string]interface{} {
+	if len(m) == 0 {
+		return m
+	}
+	simplified, err := s.Simplify(m)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return nil
+	}
+	result, _ := simplified.(map[string]interface{})
+	return result
+}
+
+// simplifyBody returns the simplified JSON body, or "" if it is not JSON or cannot be simplified.
+func simplifyBody(s gosimplifier.Simplifier, body string) string {
+	if !json.Valid([]byte(body)) {
+		return ""
+	}
+	simplified, err := gosimplifier.SimplifyJSON(s, []byte(body))
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return ""
+	}
+	return string(simplified)
+}
@@ -0,0 +1,69 @@
+package sentrysimplifier
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestBeforeSend(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{
+		"remove_properties": [ "password", "token" ],
+		"mask_properties": [ "email" ]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &sentry.Event{
+		Extra: map[string]interface{}{"order_id": 42, "token": "t"},
+		Breadcrumbs: []*sentry.Breadcrumb{
+			{Category: "auth", Data: map[string]interface{}{"email": "alice@example.com", "password": "p"}},
+			{Category: "empty"},
+			nil,
+		},
+		Request: &sentry.Request{Method: "POST", Data: `{"email":"alice@example.com","password":"p","remember":true}`},
+	}
+	event = BeforeSend(simplifier)(event, nil)
+
+	if expected := map[string]interface{}{"order_id": 42}; !reflect.DeepEqual(event.Extra, expected) {
+		t.Errorf("Expected the extras %v, got %v", expected, event.Extra)
+	}
+	if expected := map[string]interface{}{"email": "****"}; !reflect.DeepEqual(event.Breadcrumbs[0].Data, expected) {
+		t.Errorf("Expected the breadcrumb data %v, got %v", expected, event.Breadcrumbs[0].Data)
+	}
+	if expected := `{"email":"****","remember":true}`; event.Request.Data != expected {
+		t.Errorf("Expected the request body %s, got %s", expected, event.Request.Data)
+	}
+	if event.Request.Method != "POST" {
+		t.Error("Expected the rest of the request to be kept")
+	}
+}
+
+func TestBeforeSendDrops(t *testing.T) {
+	failing, _ := gosimplifier.NewSimplifier(`{}`, gosimplifier.WithMiddleware(func(next gosimplifier.Walker) gosimplifier.Walker {
+		return func(node *gosimplifier.Node) error {
+			return context.Canceled
+		}
+	}))
+	event := &sentry.Event{
+		Message: "failed",
+		Extra:   map[string]interface{}{"token": "t"},
+		Request: &sentry.Request{Data: `{"token":"t"}`},
+	}
+	event = BeforeSend(failing)(event, nil)
+	if event == nil || event.Message != "failed" || event.Extra != nil || event.Request.Data != "" {
+		t.Errorf("Expected the data that cannot be simplified to be dropped, got %+v", event)
+	}
+
+	simplifier, _ := gosimplifier.NewSimplifier(`{}`)
+	event = BeforeSend(simplifier)(&sentry.Event{Request: &sentry.Request{Data: "password=p&user=u"}}, nil)
+	if event.Request.Data != "" {
+		t.Errorf("Expected a body that is not JSON to be dropped, got %s", event.Request.Data)
+	}
+	if BeforeSend(simplifier)(nil, nil) != nil {
+		t.Error("Expected a nil event to stay nil")
+	}
+}
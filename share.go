@@ -0,0 +1,199 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// sharing decides which values the copy made by Simplify can share with the original: values
+// of a type no rule can ever change, such as a []string, or a struct none of whose fields is
+// named by a rule, are not copied. The analysis is by type, so it is conservative: maps and
+// interfaces can hold anything, and are always copied.
+type sharing struct {
+	options *options
+	// names holds every property name of the rule tree, lower-cased with WithCaseInsensitiveMatch
+	names map[string]bool
+	// actions holds the names of the properties an action applies to, e.g. a mask, which may
+	// change the value they point to
+	actions map[string]bool
+	// elements is set when rules select list elements by position or content, so no list is shared
+	elements bool
+	// globs is set when rules match map keys against patterns
+	globs bool
+	// keeps is set when rules keep listed properties only or remove every property, so any
+	// field may be removed
+	keeps bool
+	// untouched caches the result of shares per type
+	untouched sync.Map
+}
+
+// newSharing analyses the rule tree of s, or returns nil if the options or rules allow to
+// change any value, e.g. with middlewares or prune_below_depth.
+func newSharing(s *simplifierImpl, o *options) *sharing {
+	if len(o.middlewares) > 0 || o.nodeBudget > 0 || o.bestEffort || o.maxDepth > 0 && o.depthPolicy == PruneDeeper {
+		return nil
+	}
+	pruning := make(map[*simplifierImpl]bool)
+	if s.prunes(pruning) {
+		return nil
+	}
+	if s.typeRules != nil {
+		for _, sub := range s.typeRules.simplifiers {
+			if sub.prunes(pruning) {
+				return nil
+			}
+		}
+	}
+	sh := &sharing{options: o, names: make(map[string]bool), actions: make(map[string]bool)}
+	visited := make(map[*simplifierImpl]bool)
+	sh.collect(s, visited)
+	if s.typeRules != nil {
+		for _, name := range sortedKeys(s.typeRules.simplifiers) {
+			sh.collect(s.typeRules.simplifiers[name], visited)
+		}
+	}
+	return sh
+}
+
+func (sh *sharing) collect(s *simplifierImpl, visited map[*simplifierImpl]bool) {
+	if visited[s] {
+		return
+	}
+	visited[s] = true
+	if len(s.globRules) > 0 {
+		sh.globs = true
+	}
+	if len(s.rule.KeepProperties) > 0 || s.removesAll() {
+		sh.keeps = true
+	}
+	for name, r := range s.propertySimplifiers {
+		if sub, ok := r.(*simplifierImpl); ok {
+			sh.addName(name, false)
+			sh.collect(sub, visited)
+		} else {
+			sh.addName(name, true)
+		}
+	}
+	if len(s.indexRules) > 0 || s.rule.KeyValue != nil || s.rule.MaxItems > 0 {
+		sh.elements = true
+	}
+	for _, rule := range s.indexRules {
+		if sub, ok := rule.ruler.(*simplifierImpl); ok {
+			sh.collect(sub, visited)
+		}
+	}
+	if s.rule.KeyValue != nil {
+		keyField, valueField := s.rule.KeyValue.fields()
+		sh.addName(keyField, true)
+		sh.addName(valueField, true)
+	}
+	for _, name := range s.rule.RemoveIfZero {
+		sh.addName(name, true)
+	}
+	if s.removeIf != nil {
+		sh.elements = true
+		sh.addName(s.removeIf.rule.Field, false)
+		for _, name := range s.removeIf.rule.Properties {
+			sh.addName(name, true)
+		}
+	}
+}
+
+func (sh *sharing) addName(name string, action bool) {
+	name = sh.fold(name)
+	sh.names[name] = true
+	if action {
+		sh.actions[name] = true
+	}
+}
+
+func (sh *sharing) fold(name string) string {
+	if sh.options.caseInsensitive {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// acted reports whether an action may apply to the property, in which case it must not share
+// anything with the original: the action may change the value a pointer property points to.
+func (sh *sharing) acted(name string) bool {
+	return sh.actions[sh.fold(name)]
+}
+
+// actedKey is acted for a map key.
+func (sh *sharing) actedKey(key reflect.Value) bool {
+	return sh != nil && (key.Kind() != reflect.String || sh.globs || sh.acted(key.String()))
+}
+
+// shares reports whether values of type t can be shared with the original.
+func (sh *sharing) shares(t reflect.Type) bool {
+	if sh == nil {
+		return false
+	}
+	if shared, ok := sh.untouched.Load(t); ok {
+		return shared.(bool)
+	}
+	shared := sh.untouchedType(t, make(map[reflect.Type]bool))
+	sh.untouched.Store(t, shared)
+	return shared
+}
+
+// untouchedType reports whether no rule can change a value of type t. Types in inProgress are
+// assumed untouched, as whether a recursive type is changed is decided by its other parts.
+func (sh *sharing) untouchedType(t reflect.Type, inProgress map[reflect.Type]bool) bool {
+	if isLeafType(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Map, reflect.Interface:
+		return false
+	case reflect.Ptr:
+		return sh.untouchedType(t.Elem(), inProgress)
+	case reflect.Slice, reflect.Array:
+		return !sh.elements && sh.untouchedType(t.Elem(), inProgress)
+	case reflect.Struct:
+		if sh.keeps {
+			return false
+		}
+		if inProgress[t] {
+			return true
+		}
+		inProgress[t] = true
+		defer delete(inProgress, t)
+		o := sh.options
+		for i, name := range o.fieldNames(t) {
+			field := t.Field(i)
+			if sh.names[sh.fold(name)] || o.removeTags != nil && o.removesTagged(field) ||
+				!sh.untouchedType(field.Type, inProgress) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// child returns the sharing for a value held by a value being copied: a value an action may
+// apply to is copied entirely.
+func (sh *sharing) child(acted bool) *sharing {
+	if acted {
+		return nil
+	}
+	return sh
+}
+
+// element returns the sharing for the elements of a list.
+func (sh *sharing) element() *sharing {
+	if sh == nil {
+		return nil
+	}
+	return sh.child(sh.elements)
+}
+
+// field returns the sharing for the field i of the struct type t.
+func (sh *sharing) field(t reflect.Type, i int) *sharing {
+	if sh == nil {
+		return nil
+	}
+	return sh.child(sh.acted(sh.options.fieldNames(t)[i]))
+}
@@ -0,0 +1,90 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type SharedLeaf struct {
+	Tags []string
+}
+
+type SharedRecord struct {
+	Leaf    *SharedLeaf
+	Secret  *string
+	Nested  *SharedRecord
+	Labels  map[string]*SharedLeaf
+	Entries []*SharedLeaf
+}
+
+func TestSimplifySharesUntouchedValues(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"mask_properties": [ "Secret" ],
+		"property_simplifiers": { "Labels": { "remove_properties": [ "drop" ] } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := "password"
+	leaf := &SharedLeaf{Tags: []string{"a"}}
+	original := &SharedRecord{
+		Leaf:    leaf,
+		Secret:  &secret,
+		Nested:  &SharedRecord{Leaf: leaf, Secret: &secret},
+		Labels:  map[string]*SharedLeaf{"keep": leaf, "drop": leaf},
+		Entries: []*SharedLeaf{leaf},
+	}
+	result, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified := result.(*SharedRecord)
+	if simplified == original || simplified.Nested == original.Nested {
+		t.Error("Expected the records holding rule targets to be copied")
+	}
+	if simplified.Leaf != leaf || simplified.Nested.Leaf != leaf || simplified.Labels["keep"] != leaf ||
+		!reflect.DeepEqual(simplified.Entries, []*SharedLeaf{leaf}) || simplified.Entries[0] != leaf {
+		t.Error("Expected the untouched leaves to be shared with the original")
+	}
+	if secret != "password" || *simplified.Secret != DefaultMask || *simplified.Nested.Secret != DefaultMask {
+		t.Errorf("Expected the secrets of the copy only to be masked, got %q", secret)
+	}
+	if len(original.Labels) != 2 || len(simplified.Labels) != 1 {
+		t.Errorf("Expected the maps to be copied, got %v", simplified.Labels)
+	}
+}
+
+func TestSimplifyCopiesNamedValues(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": { "Entries": { "mask_properties": [ "[0]" ] } }
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := "first", "second"
+	type Pointers struct {
+		Entries []*string
+	}
+	original := Pointers{Entries: []*string{&first, &second}}
+	result, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified := result.(Pointers)
+	if *simplified.Entries[0] != DefaultMask || first != "first" {
+		t.Errorf("Expected the first element of the copy only to be masked, got %q", first)
+	}
+
+	withMiddleware, err := NewSimplifier(`{}`, WithMiddleware(func(next Walker) Walker { return next }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = withMiddleware.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.(Pointers).Entries[1] == &second {
+		t.Error("Expected nothing to be shared when middlewares may change any value")
+	}
+}
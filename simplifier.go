@@ -1,23 +1,108 @@
 package gosimplifier
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
 )
 
 // Rule defines the rule structure for property removal and nested property rules.
 type Rule struct {
 	RemoveProperties    []string         `json:"remove_properties"`
 	PropertySimplifiers map[string]*Rule `json:"property_simplifiers"`
+	// RedactProperties replaces the properties with redaction envelopes, see WithVault
+	RedactProperties []string `json:"redact_properties,omitempty"`
+	// KeyValue treats the elements of a list of key-value pairs like map entries, see KeyValueRule
+	KeyValue *KeyValueRule `json:"key_value,omitempty"`
+	// MaskProperties replaces the string values of the properties with a mask, see MaskRule
+	MaskProperties []string  `json:"mask_properties,omitempty"`
+	Mask           *MaskRule `json:"mask,omitempty"`
+	// InjectProperties replaces the properties with the metadata value of the given key,
+	// see ContextWithMetadata
+	InjectProperties map[string]string `json:"inject_properties,omitempty"`
+	// HashProperties replaces the properties with their SHA-256 hash, see WithHashSalt
+	HashProperties []string `json:"hash_properties,omitempty"`
+	// TokenizeProperties replaces the properties with opaque tokens, see Tokenizer
+	TokenizeProperties []string `json:"tokenize_properties,omitempty"`
+	// TruncateProperties shortens the string and []byte values of the properties to the given
+	// number of bytes
+	TruncateProperties map[string]int `json:"truncate_properties,omitempty"`
+	// RenameProperties renames map entries from the old to the new key, after the rules for the
+	// old key are applied. Struct fields cannot be renamed and are left as they are.
+	RenameProperties map[string]string `json:"rename_properties,omitempty"`
+	// ReplaceProperties replaces the properties with the given literals, e.g. "[REDACTED]".
+	// Values the literal cannot be decoded into are removed.
+	ReplaceProperties map[string]interface{} `json:"replace_properties,omitempty"`
+	// TransformProperties replaces the properties with the result of the named transformer,
+	// see RegisterTransformer
+	TransformProperties map[string]string `json:"transform_properties,omitempty"`
+	// RemoveIf removes the value or some of its properties when a field matches, see RemoveIfRule
+	RemoveIf *RemoveIfRule `json:"remove_if,omitempty"`
+	// RemoveIfZero removes the properties only when they hold their zero value, e.g. 0, "" or
+	// nil, so noise fields go while the ones carrying data are kept. Map values are compared by
+	// their dynamic value, so a 0 decoded from JSON is removed as well.
+	RemoveIfZero []string `json:"remove_if_zero,omitempty"`
+	// KeepProperties turns the rule into an allowlist: the properties neither listed nor named by
+	// another section are removed. It applies at any depth, next to denylists elsewhere, and only
+	// to the properties of the value the rule applies to. The other sections act first, so a
+	// property both kept and removed is removed, and a kept property without a sub-rule is
+	// simplified by the root rules, as any property no rule names
+	KeepProperties []string `json:"keep_properties,omitempty"`
+	// Extends names registered rule sets the rule is merged onto, see Registry
+	Extends []string `json:"extends,omitempty"`
+	// Except exempts properties from the glob patterns of the rule, e.g. with
+	// "remove_properties": ["*"], which removes every property no other section names
+	Except []string `json:"except,omitempty"`
+	// Removal sets how the properties the rule removes are removed, see RemovalRule
+	Removal *RemovalRule `json:"removal,omitempty"`
+	// PruneBelowDepth removes the values nested more than the given number of levels below the
+	// value the rule applies to, e.g. 2 keeps the properties of the value and of its children
+	// but not their own children. The pruned values are removed as Removal says, so a
+	// placeholder can summarize them. An enclosing rule pruning closer to the root takes
+	// precedence. Protobuf messages are pruned at the message fields.
+	PruneBelowDepth int `json:"prune_below_depth,omitempty"`
+	// MaxItems truncates the lists the rule applies to to their first elements, to keep samples
+	// of large lists. Arrays cannot shrink, so their elements past the limit are reset. As the
+	// root rules apply to the values no rule names, a MaxItems of the root rule limits every
+	// list no other rule names.
+	MaxItems int `json:"max_items,omitempty"`
+	// MoreItems is appended to the lists MaxItems truncates whose elements can hold a string,
+	// e.g. decoded JSON, with %d replaced by the number of elements dropped, e.g. "and %d more"
+	MoreItems string `json:"more_items,omitempty"`
+	// Flatten lifts the properties of the value into the value holding it in the output of
+	// SimplifyToMap and SimplifyJSON, see FlattenRule
+	Flatten *FlattenRule `json:"flatten,omitempty"`
+	// TypeSimplifiers applies rules to the values of a Go type wherever they are, keyed by the
+	// type name, e.g. "mypkg.User". Only the root rule may have them, see forType.
+	TypeSimplifiers map[string]*Rule `json:"type_simplifiers,omitempty"`
 }
 
 // Simplifier defines the interface for struct simplification.
+//
+// The other entry points, such as SimplifyContext and SimplifyJSON, are package functions
+// taking a Simplifier. They call the method of the matching optional interface, e.g.
+// ContextSimplifier, when the Simplifier implements it, as the simplifiers of this package do,
+// so wrappers and decorators of a Simplifier implement those they can pass on.
 type Simplifier interface {
 	// Simplify method:
-	// 1. Receives any type of struct or pointer to it, returns the same type of struct(pointer)
-	// 2. Will not modify the original, but just make a copy as the return value
+	// 1. Receives any type of struct or pointer to it, returns the same type of struct(pointer).
+	//    Maps, slices and arrays are accepted as well; the rules are applied to every element of a
+	//    root slice or array, e.g. a decoded JSON array such as []interface{} or []map[string]interface{}
+	// 2. Will not modify the original, but just make a copy as the return value. Values no rule
+	//    can ever change, e.g. a []string no rule names, are shared with the original instead of
+	//    being copied, so the result must not be modified in place unless it is simplified again
 	// 3. Removes the properties of the return value according to the rules
+	// 4. Visits map entries in ascending key order, so repeated runs over the same input are reproducible
+	// 5. Applies the rules of interface values, e.g. of interface{} fields and map values, to their
+	//    dynamic value, which keeps its type in the copy: a boxed struct is replaced by its
+	//    simplified copy, and a boxed pointer by a pointer to it
+	// 6. Copies the values of leaf types, e.g. time.Time, wholesale without walking into them,
+	//    see RegisterLeafType, and the values of types implementing Copier by their own logic
 	Simplify(original interface{}) (interface{}, error)
 }
 
@@ -41,10 +126,34 @@ type Simplifier interface {
 type simplifierImpl struct {
 	propertySimplifiers map[string]ruler
 	rule                *Rule
+	removeIf            *condition
+	indexRules          []*indexRule
+	globRules           []*globRule
+	// folded indexes the rules by their lower-cased names, see WithCaseInsensitiveMatch
+	folded map[string]foldedRuler
+	// sharing selects the values Simplify does not need to copy, nil to copy everything
+	sharing *sharing
+	// options and walker are only set on the root simplifier
+	options *options
+	walker  Walker
+	// fallback is the root simplifier without the rules that only apply to the root value,
+	// see unmatchedRuler
+	fallback *simplifierImpl
+	// removal is how the properties the rule removes are removed, nil for the options' mode
+	removal *removal
+	// typeRules are the type_simplifiers, only set on the root simplifier
+	typeRules *typeRules
+	// globstars is the root rule as declared, if it has globstar names, which are pushed into
+	// every rule of the tree, see expandGlobstars
+	globstars *Rule
+	// messageErr is the error simplifying protobuf messages fails with, see messageSupport
+	messageErr error
 }
 
 type ruler interface {
-	applyRules(value reflect.Value, mapParent *reflect.Value, mapKey *reflect.Value, root *simplifierImpl)
+	apply(node *Node) error
+	// action names what the ruler does to the node, or "" if it only descends into it
+	action() string
 }
 
 // removeRuler for removing a valueKey from parent
@@ -83,82 +192,252 @@ var removeRulerSingleton = &removeRuler{}
 //	root.field2.sub1.b
 //
 // Other properties will be kept.
-func NewSimplifier(rulesJson string) (Simplifier, error) {
+//
+// A name of the root rule prefixed with "**.", e.g. "**.Password" in remove_properties, applies
+// to the property at any depth, including below the properties other rules name.
+func NewSimplifier(rulesJson string, opts ...Option) (Simplifier, error) {
 	rule := &Rule{}
-	if err := json.Unmarshal([]byte(rulesJson), rule); err != nil {
+	if err := decodeRules([]byte(rulesJson), rule); err != nil {
+		return nil, err
+	}
+	return newRootSimplifier(rule, newOptions(opts))
+}
+
+// NewSimplifierByRule creates a Simplifier from rule. The simplifier keeps a copy of rule, so
+// changing rule afterwards does not change the simplifier.
+func NewSimplifierByRule(rule *Rule, opts ...Option) (Simplifier, error) {
+	rule, err := cloneRule(rule)
+	if err != nil {
 		return nil, err
 	}
-	return newSimplifierByRule0(rule)
+	return newRootSimplifier(rule, newOptions(opts))
 }
 
-func NewSimplifierByRule(rule *Rule) (Simplifier, error) {
-	return newSimplifierByRule0(rule)
+// newRootSimplifier creates the simplifier that Simplify is called on, carrying the options.
+func newRootSimplifier(rule *Rule, options *options) (*simplifierImpl, error) {
+	rule, err := options.registryOrDefault().resolveExtends(rule)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := expandGlobstars(rule)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newSimplifierByRule0(expanded, make(map[string]*simplifierImpl))
+	if err != nil {
+		return nil, err
+	}
+	if expanded != rule {
+		s.globstars = rule
+	}
+	found, _ := splitGlobstars(rule)
+	options.globstarNames = globstarNames(found)
+	if options.vault == nil && usesRedaction(rule) {
+		return nil, fmt.Errorf("redact_properties requires a vault, see WithVault")
+	}
+	if options.tokenizer == nil && usesTokenization(rule) {
+		return nil, fmt.Errorf("tokenize_properties requires a tokenizer, see WithTokenizer")
+	}
+	if options.removal, err = newRemoval(&options.removalRule); err != nil {
+		return nil, err
+	}
+	if s.typeRules, err = newTypeRules(expanded); err != nil {
+		return nil, err
+	}
+	if options.stats != nil {
+		options.stats.register(s)
+	}
+	if options.caseInsensitive {
+		s.foldNames(make(map[*simplifierImpl]bool))
+	}
+	s.options = options
+	s.walker = options.chain(applyNode)
+	s.messageErr = s.messageSupport()
+	s.sharing = newSharing(s, options)
+	s.fallback = s.fallbackRules()
+	return s, nil
 }
 
-// newSimplifierByRule0 creates a new instance of simplifierImpl with the given rule
-func newSimplifierByRule0(rule *Rule) (*simplifierImpl, error) {
-	propertySimplifiers, err := createPropertySimplifiers(rule)
+// newSimplifierByRule0 creates a new instance of simplifierImpl with the given rule.
+// Structurally identical rules share a single instance through interned, which is keyed by
+// the JSON form of the rule.
+func newSimplifierByRule0(rule *Rule, interned map[string]*simplifierImpl) (*simplifierImpl, error) {
+	rule = expandIndexedNames(rule)
+	key, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := interned[string(key)]; ok {
+		return s, nil
+	}
+	propertySimplifiers, err := createPropertySimplifiers(rule, interned)
+	if err != nil {
+		return nil, err
+	}
+	removeIf, err := newCondition(rule.RemoveIf)
+	if err != nil {
+		return nil, err
+	}
+	indexRules, err := newIndexRules(propertySimplifiers)
+	if err != nil {
+		return nil, err
+	}
+	removal, err := newRemoval(rule.Removal)
 	if err != nil {
 		return nil, err
 	}
-	return &simplifierImpl{
+	if rule.PruneBelowDepth < 0 {
+		return nil, fmt.Errorf("prune_below_depth: negative depth %d", rule.PruneBelowDepth)
+	}
+	if rule.MaxItems < 0 {
+		return nil, fmt.Errorf("max_items: negative length %d", rule.MaxItems)
+	}
+	s := &simplifierImpl{
 		propertySimplifiers: propertySimplifiers,
 		rule:                rule,
-	}, nil
+		removeIf:            removeIf,
+		indexRules:          indexRules,
+		globRules:           newGlobRules(propertySimplifiers),
+		removal:             removal,
+	}
+	interned[string(key)] = s
+	return s, nil
+}
+
+// ExtendableSimplifier is a Simplifier that can be extended with more rules, keeping its
+// options, see ExtendSimplifier. Wrappers and decorators of a Simplifier implement it to stay
+// extendable, e.g. by extending the simplifier they wrap and wrapping the result.
+type ExtendableSimplifier interface {
+	Simplifier
+	// Extend returns a new simplifier with the rules of the simplifier and rule merged, rule
+	// taking precedence.
+	Extend(rule *Rule) (Simplifier, error)
 }
 
 // ExtendSimplifier extends the base simplifier with the given rules.
 // The new Simplifier will have the rules merge from the base and the given rules.
 func ExtendSimplifier(base Simplifier, rulesJson string) (Simplifier, error) {
-	baseImpl, ok := base.(*simplifierImpl)
-	if !ok {
-		return nil, fmt.Errorf("base Simplifier is not the correct type")
-	}
 	newRule := &Rule{}
-	if err := json.Unmarshal([]byte(rulesJson), newRule); err != nil {
+	if err := decodeRules([]byte(rulesJson), newRule); err != nil {
 		return nil, err
 	}
-	return ExtendSimplifierByRule(baseImpl, newRule)
+	return ExtendSimplifierByRule(base, newRule)
+}
+
+// ExtendSimplifierByRule extends the base simplifier with newRule, see ExtendSimplifier. Base
+// simplifiers that are not an ExtendableSimplifier are extended through their rules, see Rules,
+// without their options.
+func ExtendSimplifierByRule(base Simplifier, newRule *Rule) (Simplifier, error) {
+	if extendable, ok := base.(ExtendableSimplifier); ok {
+		return extendable.Extend(newRule)
+	}
+	rule := Rules(base)
+	if rule == nil {
+		return nil, fmt.Errorf("cannot read the rules of %T to extend them", base)
+	}
+	return NewSimplifierByRule(mergeRules(rule, newRule))
 }
 
-func ExtendSimplifierByRule(baseImpl *simplifierImpl, newRule *Rule) (Simplifier, error) {
-	return newSimplifierByRule0(mergeRules(baseImpl.rule, newRule))
+// Extend returns a new simplifier with the rules of s and rule merged, and the options of s.
+func (s *simplifierImpl) Extend(rule *Rule) (Simplifier, error) {
+	rule, err := cloneRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	// the extension compiles into its own copy of the options, which Simplify on s keeps reading
+	options := *s.options
+	return newRootSimplifier(mergeRules(s.fullRule(), rule), &options)
 }
 
 func mergeRules(rule *Rule, newRule *Rule) *Rule {
-	// Copy old rule's remove_properties
-	mergedRemoveProperties := make([]string, len(rule.RemoveProperties))
-	copy(mergedRemoveProperties, rule.RemoveProperties)
+	// Merge remove_properties
+	mergedRemoveProperties := mergeProperties(rule.RemoveProperties, newRule.RemoveProperties)
 
-	// Copy old rule's propertySimplifiers
-	mergedPropertySimplifiers := make(map[string]*Rule)
-	for k, v := range rule.PropertySimplifiers {
-		mergedPropertySimplifiers[k] = v
+	// Return the merged rule
+	return &Rule{
+		RemoveProperties:    mergedRemoveProperties,
+		PropertySimplifiers: mergeSubRules(rule.PropertySimplifiers, newRule.PropertySimplifiers),
+		RedactProperties:    mergeProperties(rule.RedactProperties, newRule.RedactProperties),
+		KeyValue:            preferNew(rule.KeyValue, newRule.KeyValue),
+		MaskProperties:      mergeProperties(rule.MaskProperties, newRule.MaskProperties),
+		Mask:                preferNew(rule.Mask, newRule.Mask),
+		InjectProperties:    mergeMaps(rule.InjectProperties, newRule.InjectProperties),
+		HashProperties:      mergeProperties(rule.HashProperties, newRule.HashProperties),
+		TokenizeProperties:  mergeProperties(rule.TokenizeProperties, newRule.TokenizeProperties),
+		TruncateProperties:  mergeMaps(rule.TruncateProperties, newRule.TruncateProperties),
+		RenameProperties:    mergeMaps(rule.RenameProperties, newRule.RenameProperties),
+		ReplaceProperties:   mergeMaps(rule.ReplaceProperties, newRule.ReplaceProperties),
+		TransformProperties: mergeMaps(rule.TransformProperties, newRule.TransformProperties),
+		RemoveIf:            preferNew(rule.RemoveIf, newRule.RemoveIf),
+		RemoveIfZero:        mergeProperties(rule.RemoveIfZero, newRule.RemoveIfZero),
+		KeepProperties:      mergeProperties(rule.KeepProperties, newRule.KeepProperties),
+		Extends:             mergeProperties(rule.Extends, newRule.Extends),
+		Except:              mergeProperties(rule.Except, newRule.Except),
+		Removal:             preferNew(rule.Removal, newRule.Removal),
+		PruneBelowDepth:     cmp.Or(newRule.PruneBelowDepth, rule.PruneBelowDepth),
+		MaxItems:            cmp.Or(newRule.MaxItems, rule.MaxItems),
+		MoreItems:           cmp.Or(newRule.MoreItems, rule.MoreItems),
+		Flatten:             preferNew(rule.Flatten, newRule.Flatten),
+		TypeSimplifiers:     mergeSubRules(rule.TypeSimplifiers, newRule.TypeSimplifiers),
 	}
+}
 
-	// Merge remove_properties
-	for _, prop := range newRule.RemoveProperties {
-		if !contains(mergedRemoveProperties, prop) {
-			mergedRemoveProperties = append(mergedRemoveProperties, prop)
-		}
+// mergeSubRules returns the sub-rules of both rules, merging those of the same name
+func mergeSubRules(rules map[string]*Rule, newRules map[string]*Rule) map[string]*Rule {
+	// Copy old rule's sub-rules
+	merged := make(map[string]*Rule)
+	for k, v := range rules {
+		merged[k] = v
 	}
 
-	// Merge property_simplifiers
-	for k, v := range newRule.PropertySimplifiers {
-		if _, ok := mergedPropertySimplifiers[k]; ok {
+	for k, v := range newRules {
+		if _, ok := merged[k]; ok {
 			// If the key already exists, merge the sub-rule
-			mergedPropertySimplifiers[k] = mergeRules(mergedPropertySimplifiers[k], v)
+			merged[k] = mergeRules(merged[k], v)
 		} else {
 			// Otherwise, just add the new rule
-			mergedPropertySimplifiers[k] = v
+			merged[k] = v
 		}
 	}
+	return merged
+}
 
-	// Return the merged rule
-	return &Rule{
-		RemoveProperties:    mergedRemoveProperties,
-		PropertySimplifiers: mergedPropertySimplifiers,
+// mergeProperties returns a copy of props with the missing newProps appended
+func mergeProperties(props []string, newProps []string) []string {
+	merged := make([]string, len(props))
+	copy(merged, props)
+	for _, prop := range newProps {
+		if !contains(merged, prop) {
+			merged = append(merged, prop)
+		}
+	}
+	return merged
+}
+
+// preferNew returns the setting of the new rule if it has one, or else the setting of the old rule.
+func preferNew[T any](old *T, new *T) *T {
+	if new != nil {
+		return new
+	}
+	return old
+}
+
+// hasUnexportedFields reports whether the struct type has fields reflection cannot set
+func hasUnexportedFields(structType reflect.Type) bool {
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).PkgPath != "" {
+			return true
+		}
 	}
+	return false
+}
+
+// joinRulePath appends a property name to a rule tree path
+func joinRulePath(path string, propName string) string {
+	if path == "" || isIndexSelector(propName) {
+		return path + propName
+	}
+	return path + "." + propName
 }
 
 // Helper function to check if a string is in a slice
@@ -172,17 +451,63 @@ func contains(s []string, str string) bool {
 }
 
 // createPropertySimplifiers creates property simplifiers based on the provided rules.
-func createPropertySimplifiers(rule *Rule) (map[string]ruler, error) {
+func createPropertySimplifiers(rule *Rule, interned map[string]*simplifierImpl) (map[string]ruler, error) {
 	propertySimplifiers := make(map[string]ruler)
 
 	for propName, subRule := range rule.PropertySimplifiers {
-		propertySimplifier, err := newSimplifierByRule0(subRule)
+		propertySimplifier, err := newSimplifierByRule0(subRule, interned)
 		if err != nil {
 			return nil, err
 		}
 		propertySimplifiers[propName] = propertySimplifier
 	}
 
+	for propName, max := range rule.TruncateProperties {
+		if max < 0 {
+			return nil, fmt.Errorf("truncate_properties %q: negative length %d", propName, max)
+		}
+		propertySimplifiers[propName] = &truncateRuler{max: max}
+	}
+
+	for _, propName := range rule.RedactProperties {
+		propertySimplifiers[propName] = redactRulerSingleton
+	}
+
+	if len(rule.MaskProperties) > 0 {
+		masker := &maskRuler{rule: rule.Mask}
+		for _, propName := range rule.MaskProperties {
+			propertySimplifiers[propName] = masker
+		}
+	}
+
+	for _, propName := range rule.HashProperties {
+		propertySimplifiers[propName] = hashRulerSingleton
+	}
+
+	for _, propName := range rule.TokenizeProperties {
+		propertySimplifiers[propName] = tokenizeRulerSingleton
+	}
+
+	for propName, replacement := range rule.ReplaceProperties {
+		replacer, err := newReplaceRuler(propName, replacement)
+		if err != nil {
+			return nil, err
+		}
+		propertySimplifiers[propName] = replacer
+	}
+
+	for propName, name := range rule.TransformProperties {
+		transformer, err := newTransformRuler(propName, name)
+		if err != nil {
+			return nil, err
+		}
+		propertySimplifiers[propName] = transformer
+	}
+
+	for propName, key := range rule.InjectProperties {
+		propertySimplifiers[propName] = &injectRuler{key: key}
+	}
+
 	for _, propName := range rule.RemoveProperties {
 		propertySimplifiers[propName] = removeRulerSingleton
 	}
@@ -192,117 +517,516 @@ func createPropertySimplifiers(rule *Rule) (map[string]ruler, error) {
 
 // Simplify applies the rules to the original struct and returns a simplified copy.
 func (s *simplifierImpl) Simplify(original interface{}) (interface{}, error) {
+	return s.SimplifyContext(context.Background(), original)
+}
+
+// ContextSimplifier is a Simplifier checking a context while it simplifies, see SimplifyContext.
+type ContextSimplifier interface {
+	Simplifier
+	// SimplifyContext is Simplify, giving up with the error of ctx once it is done.
+	SimplifyContext(ctx context.Context, original interface{}) (interface{}, error)
+}
+
+// SimplifyContext is Simplify, checking ctx periodically while walking the value so a large
+// object graph cannot block the caller past its deadline. It returns the error of ctx once ctx
+// is done. Simplifiers that are not a ContextSimplifier only check ctx before simplifying.
+func SimplifyContext(ctx context.Context, s Simplifier, original interface{}) (interface{}, error) {
+	if cs, ok := s.(ContextSimplifier); ok {
+		return cs.SimplifyContext(ctx, original)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.Simplify(original)
+}
+
+// SimplifyContext is Simplify, giving up with the error of ctx once it is done.
+func (s *simplifierImpl) SimplifyContext(ctx context.Context, original interface{}) (interface{}, error) {
+	if original == nil {
+		return nil, nil
+	}
+	if message, ok := original.(proto.Message); ok {
+		return s.simplifyMessage(ctx, message)
+	}
+	return s.simplifyCopy(ctx, original, nil)
+}
+
+// simplifyCopy applies the rules to a copy of original, recording the changes in report if it
+// is not nil.
+func (s *simplifierImpl) simplifyCopy(ctx context.Context, original interface{}, report *Report) (interface{}, error) {
 	copyValue := reflect.ValueOf(original)
 	copyType := reflect.TypeOf(original)
 
 	// Make a deep copy of the original value
-	cp := reflect.New(copyType).Elem()
-	cp = deepCopy(cp, copyValue)
+	cp, err := s.sharing.copy(reflect.New(copyType).Elem(), copyValue)
+	if err != nil {
+		return nil, err
+	}
 
 	// Apply the rules recursively
-	s.applyRules(cp, nil, nil, s)
+	if err := s.simplify(ctx, cp, report); err != nil {
+		if isPartial(err) {
+			return cp.Interface(), err
+		}
+		return nil, err
+	}
 
 	return cp.Interface(), nil
 }
 
+// InPlaceSimplifier is a Simplifier applying its rules to a value itself, see SimplifyInPlace.
+type InPlaceSimplifier interface {
+	Simplifier
+	// SimplifyInPlace applies the rules to the value behind a non-nil pointer instead of to a
+	// copy.
+	SimplifyInPlace(ptr interface{}) error
+}
+
+// SimplifyInPlace applies the rules of s to the value behind a non-nil pointer instead of to a
+// copy, avoiding the deep copy for callers that own the value. Simplifiers that are not an
+// InPlaceSimplifier simplify a copy, which then replaces the value.
+func SimplifyInPlace(s Simplifier, ptr interface{}) error {
+	if is, ok := s.(InPlaceSimplifier); ok {
+		return is.SimplifyInPlace(ptr)
+	}
+	value := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("SimplifyInPlace requires a non-nil pointer, got %T", ptr)
+	}
+	simplified, err := s.Simplify(ptr)
+	if err != nil && !isPartial(err) {
+		return err
+	}
+	result := reflect.ValueOf(simplified)
+	if !result.IsValid() || result.Type() != value.Type() || result.IsNil() {
+		return fmt.Errorf("SimplifyInPlace cannot write %T into %T", simplified, ptr)
+	}
+	value.Elem().Set(result.Elem())
+	return err
+}
+
+// SimplifyInPlace applies the rules directly to the value ptr points to, without making a copy.
+// ptr must be a non-nil pointer; maps and slices reachable from it are modified as well.
+func (s *simplifierImpl) SimplifyInPlace(ptr interface{}) error {
+	value := reflect.ValueOf(ptr)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("SimplifyInPlace requires a non-nil pointer, got %T", ptr)
+	}
+	if message, ok := ptr.(proto.Message); ok {
+		return s.simplifyMessageInPlace(context.Background(), message)
+	}
+	return s.simplify(context.Background(), value, nil)
+}
+
+// simplify applies the rules to value recursively, recording the changes in report if it is
+// not nil.
+func (s *simplifierImpl) simplify(ctx context.Context, value reflect.Value, report *Report) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	start := s.options.beforeSimplify(value)
+	w := newWalk()
+	defer releaseWalk(w)
+	w.root, w.walker, w.ctx, w.done, w.metadata = s, s.walker, ctx, ctx.Done(), metadataFrom(ctx)
+	w.parallelism, w.report = s.options.parallelism, report
+	if s.options.provenanceKey != "" {
+		w.provenance = make(map[string]interface{})
+	}
+	err := w.visit(&Node{Value: value, index: -1, ruler: s, walk: w})
+	if err == nil && len(w.errors) > 0 {
+		err = &PartialError{Errors: w.errors}
+	}
+	if s.options.stats != nil {
+		s.options.stats.add(w.ruleHits, err)
+	}
+	if w.provenance != nil && (err == nil || isPartial(err)) {
+		attachProvenance(value, s.options.provenanceKey, w.provenance)
+	}
+	s.options.afterSimplify(value, start, err)
+	return err
+}
+
 // deepCopy makes a deep copy of the original value recursively.
-func deepCopy(copy reflect.Value, original reflect.Value) reflect.Value {
+func deepCopy(copy reflect.Value, original reflect.Value) (reflect.Value, error) {
+	return (*sharing)(nil).copy(copy, original)
+}
+
+// copy makes a copy of the original value recursively, sharing the values no rule can change.
+// It fails if a Copier or a registered copier returns a value of the wrong type.
+func (sh *sharing) copy(copy reflect.Value, original reflect.Value) (reflect.Value, error) {
+	if original.IsValid() && sh.shares(original.Type()) {
+		if copy.CanSet() {
+			copy.Set(original)
+		}
+		return original, nil
+	}
+	if copied, ok, err := copyByHook(original); err != nil {
+		return reflect.Value{}, err
+	} else if ok {
+		if copy.CanSet() {
+			copy.Set(copied)
+			return copy, nil
+		}
+		return copied, nil
+	}
+	if original.IsValid() && isLeafType(original.Type()) {
+		if copy.CanSet() {
+			copy.Set(original)
+		}
+		return original, nil
+	}
 	switch original.Kind() {
 	case reflect.Ptr:
 		originalValue := original.Elem()
 		if !originalValue.IsValid() {
-			return original
+			return original, nil
 		}
 		newValue := reflect.New(originalValue.Type())
+		if _, err := sh.copy(newValue.Elem(), originalValue); err != nil {
+			return reflect.Value{}, err
+		}
+		if copy.CanSet() {
+			copy.Set(newValue)
+		}
 		copy = newValue
-		deepCopy(copy.Elem(), originalValue)
+	case reflect.Interface:
+		if original.IsNil() {
+			copy.Set(original)
+			break
+		}
+		elem := original.Elem()
+		copied, err := sh.copy(reflect.New(elem.Type()).Elem(), elem)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		copy.Set(copied)
+	case reflect.Map:
+		if original.IsNil() {
+			copy.Set(original)
+			break
+		}
+		newMap := reflect.MakeMapWithSize(original.Type(), original.Len())
+		for _, mapKey := range original.MapKeys() {
+			mapValue := original.MapIndex(mapKey)
+			copied, err := sh.child(sh.actedKey(mapKey)).copy(reflect.New(mapValue.Type()).Elem(), mapValue)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			newMap.SetMapIndex(mapKey, copied)
+		}
+		copy.Set(newMap)
 	case reflect.Slice:
+		if original.IsNil() {
+			copy.Set(original)
+			break
+		}
 		copy.Set(reflect.MakeSlice(original.Type(), original.Len(), original.Cap()))
+		elements := sh.element()
 		for i := 0; i < original.Len(); i++ {
-			deepCopy(copy.Index(i), original.Index(i))
+			if _, err := elements.copy(copy.Index(i), original.Index(i)); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+	case reflect.Array:
+		elements := sh.element()
+		for i := 0; i < original.Len(); i++ {
+			if _, err := elements.copy(copy.Index(i), original.Index(i)); err != nil {
+				return reflect.Value{}, err
+			}
 		}
 	case reflect.Struct:
+		if hasUnexportedFields(original.Type()) {
+			// Unexported fields, e.g. the internals of time.Time, can only be copied along with
+			// the whole struct; the exported fields are deep copied over it
+			copy.Set(original)
+			for i := 0; i < original.NumField(); i++ {
+				if field := copy.Field(i); field.CanSet() {
+					copied, err := sh.field(original.Type(), i).copy(field, original.Field(i))
+					if err != nil {
+						return reflect.Value{}, err
+					}
+					field.Set(copied)
+				}
+			}
+			break
+		}
 		copy.Set(reflect.New(original.Type()).Elem())
 		for i := 0; i < original.NumField(); i++ {
-			deepCopy(copy.Field(i), original.Field(i))
+			if _, err := sh.field(original.Type(), i).copy(copy.Field(i), original.Field(i)); err != nil {
+				return reflect.Value{}, err
+			}
 		}
 	default:
 		copy.Set(original)
 	}
-	return copy
+	return copy, nil
 }
 
-func (s *removeRuler) applyRules(value reflect.Value, parent *reflect.Value, mapKey *reflect.Value, rootSimplifier *simplifierImpl) {
-	if parent == nil {
-		return
-	}
-	switch p := *parent; p.Kind() {
+// apply removes the node from its parent according to its RemovalMode: struct fields are reset
+// to their zero value, or the value registered with WithRemovedValue, map entries are deleted
+// and list elements, which keep their position, are reset to their zero value, unless they
+// are set to a placeholder.
+func (s *removeRuler) apply(node *Node) error {
+	removal := removalOf(node)
+	switch p := node.Parent; p.Kind() {
+	case reflect.Slice, reflect.Array:
+		if !node.Value.CanSet() {
+			return node.walk.unexpected(node, ErrUnsettable)
+		}
+		if placeholder := removal.placeholderOf(node.Value.Type()); placeholder.IsValid() {
+			node.Value.Set(placeholder)
+			return nil
+		}
+		node.Value.Set(reflect.Zero(node.Value.Type()))
 	case reflect.Struct:
-		if value.IsValid() && value.CanSet() {
-			value.Set(reflect.Zero(value.Type()))
+		if !node.Value.IsValid() {
+			return nil
+		}
+		if !node.Value.CanSet() {
+			return node.walk.unexpected(node, ErrUnsettable)
+		}
+		if placeholder := removal.placeholderOf(node.Value.Type()); placeholder.IsValid() {
+			node.Value.Set(placeholder)
+			return nil
 		}
+		node.Value.Set(node.walk.root.options.removedValue(node.Value.Type()))
 	case reflect.Map:
-		if mapKey == nil {
-			return
+		if !node.Key.IsValid() {
+			return nil
 		}
-		p.SetMapIndex(*mapKey, reflect.Value{})
+		if placeholder := removal.placeholderOf(p.Type().Elem()); placeholder.IsValid() {
+			p.SetMapIndex(node.Key, placeholder)
+			return nil
+		}
+		if removal.mode == RemoveZero {
+			p.SetMapIndex(node.Key, node.walk.root.options.removedValue(p.Type().Elem()))
+			return nil
+		}
+		p.SetMapIndex(node.Key, reflect.Value{})
 	}
+	return nil
+}
+
+func (s *removeRuler) action() string {
+	return "remove"
 }
 
-func (s *simplifierImpl) applyRules(value reflect.Value, parent *reflect.Value, mapKey *reflect.Value, rootSimpifier *simplifierImpl) {
-	s.applyRules0(value, rootSimpifier)
+func (s *simplifierImpl) action() string {
+	return ""
 }
 
-func getRealValue(value reflect.Value) reflect.Value {
-	if value.Kind() == reflect.Ptr {
+// apply descends into the node and applies the rules of s, merged with the type rules of its
+// value, to its children.
+func (s *simplifierImpl) apply(node *Node) error {
+	return s.forType(node).applyRules0(node)
+}
+
+// indirect follows pointers and interfaces down to the underlying value.
+// Pointer targets stay addressable, so struct fields reached through them can be set.
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
 		if value.IsNil() {
 			return reflect.Value{}
 		}
 		value = value.Elem()
 	}
-	return reflect.ValueOf(value.Interface())
+	return value
 }
 
-func (s *simplifierImpl) applyRules0(value reflect.Value, rootSimpifier *simplifierImpl) {
-	// applyRules applies the rules to the struct recursively.
-	val, kind := value.Interface(), value.Kind()
-	if val == nil || kind == reflect.Invalid {
-		return
+// applyRules0 applies the rules to the children of the node recursively.
+func (s *simplifierImpl) applyRules0(node *Node) error {
+	value := indirect(node.Value)
+	if !value.IsValid() || isLeafType(value.Type()) {
+		return nil
+	}
+	w := node.walk
+	root := w.root
+	s.prune(node)
+	if node.promotes {
+		// remove_if is evaluated on the struct embedding the value, as its fields are promoted
+		removals, _ := s.conditionalRemovals(indirect(node.Parent), root.options)
+		return s.applyStructRules(node, value, removals)
 	}
-	value = getRealValue(value)
-	underlyingKind := value.Kind()
+	removals, removeValue := s.conditionalRemovals(value, root.options)
+	if removeValue && node.parent != nil {
+		return removeRulerSingleton.apply(node)
+	}
+	if (value.Kind() == reflect.Struct || value.Kind() == reflect.Array) && !value.CanAddr() {
+		// a struct or array held by a map or an interface cannot be changed in place, so the
+		// rules are applied to an addressable copy, which then replaces it
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		if err := s.applyValueRules(node, addressable, removals); err != nil {
+			return err
+		}
+		if !node.setIndirect(addressable) {
+			return w.unexpected(node, ErrUnsettable)
+		}
+		return nil
+	}
+	return s.applyValueRules(node, value, removals)
+}
 
-	switch underlyingKind {
-	case reflect.Slice:
-		for i := 0; i < value.Len(); i++ {
-			item := value.Index(i)
-			s.applyRules(item, &value, nil, rootSimpifier)
+// applyValueRules applies the rules to the children of value, the value of the node, removing
+// the properties in removals.
+func (s *simplifierImpl) applyValueRules(node *Node, value reflect.Value, removals []string) error {
+	w := node.walk
+	root := w.root
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if s.rule.KeyValue != nil {
+			return s.applyKeyValueRules(node, value)
 		}
-	case reflect.Struct:
-		for i := 0; i < value.NumField(); i++ {
-			field, fieldName := value.Field(i), value.Type().Field(i).Name
-			if subSimplifier := s.propertySimplifiers[fieldName]; subSimplifier == nil {
-				rootSimpifier.applyRules0(field, rootSimpifier)
-			} else {
-				subSimplifier.applyRules(field, &value, nil, rootSimpifier)
-			}
+		value, kept, err := s.filterElements(node, value)
+		if err != nil {
+			return err
+		}
+		value, dropped, err := s.limitElements(node, value, kept)
+		if err != nil {
+			return err
+		}
+		if w.parallelism > 1 && value.Len() >= minParallelElements {
+			err = s.visitElementsParallel(node, value)
+		} else {
+			err = s.visitElements(node, value, 0, value.Len(), w)
 		}
+		if err != nil {
+			return err
+		}
+		s.appendMoreItems(node, value, dropped)
+	case reflect.Struct:
+		return s.applyStructRules(node, value, removals)
 	case reflect.Map:
-		for _, mapKey := range value.MapKeys() {
+		keys := mapKeys(value)
+		defer releaseKeys(keys)
+		for _, mapKey := range *keys {
 			mapValue := value.MapIndex(mapKey)
-			mapVal, mapKeyStr := mapValue.Interface(), mapKey.String()
-			if mapVal == nil && mapKeyStr == "" {
+			mapKeyStr := mapKey.String()
+			if mapValue.Kind() == reflect.Interface && mapValue.IsNil() && mapKeyStr == "" {
 				continue
 			}
-			if mapValue.IsZero() {
-				removeRulerSingleton.applyRules(mapValue, &value, &mapKey, rootSimpifier)
-				continue
+			var subSimplifier ruler
+			if mapValue.IsZero() || removals != nil && root.options.containsName(removals, mapKeyStr) {
+				subSimplifier = removeRulerSingleton
+			} else if _, propertySimplifier := s.keyRuler(mapKeyStr); propertySimplifier != nil {
+				subSimplifier = propertySimplifier
+			} else {
+				subSimplifier = s.unmatchedRuler(mapKeyStr, root)
 			}
-			if subSimplifier := s.propertySimplifiers[mapKeyStr]; subSimplifier != nil {
-				subSimplifier.applyRules(mapValue, &value, &mapKey, rootSimpifier)
-				continue
+			if err := w.visitChild(node.child(value, mapValue, mapKey, mapKeyStr, -1, s, subSimplifier)); err != nil {
+				return err
 			}
-			rootSimpifier.applyRules0(mapValue, rootSimpifier)
 		}
+		if err := s.injectMissing(node, value); err != nil {
+			return err
+		}
+		s.renameKeys(value)
+	default:
+		if node.index < 0 && s != root && s != root.fallback && s.hasPropertyRules() {
+			return w.unexpected(node, fmt.Errorf("%w: %s", ErrUnsupportedKind, value.Kind()))
+		}
+	}
+	return nil
+}
+
+// hasPropertyRules reports whether the rules name properties of the value they apply to.
+func (s *simplifierImpl) hasPropertyRules() bool {
+	return len(s.propertySimplifiers) > 0 || len(s.rule.KeepProperties) > 0 || s.removeIf != nil ||
+		len(s.rule.RemoveIfZero) > 0
+}
+
+// applyStructRules applies the rules to the fields of the struct value held by the node, removing
+// the fields in removals.
+func (s *simplifierImpl) applyStructRules(node *Node, value reflect.Value, removals []string) error {
+	w := node.walk
+	root := w.root
+	valueType := value.Type()
+	if root.options.strictFields && node.explicit() {
+		if err := s.checkFields(valueType, node.ruleLocation(), root.options); err != nil {
+			return err
+		}
+	}
+	fieldNames := root.options.fieldNames(valueType)
+	for i := 0; i < value.NumField(); i++ {
+		if structField := valueType.Field(i); !structField.IsExported() && !structField.Anonymous {
+			// unexported fields cannot be changed, and may hold internals such as the state of
+			// a protobuf message, which is not to be walked into
+			continue
+		}
+		field, fieldName := value.Field(i), fieldNames[i]
+		var subSimplifier ruler
+		promotes := false
+		if root.options.removeTags != nil && root.options.removesTagged(valueType.Field(i)) {
+			subSimplifier = removeRulerSingleton
+		} else if removals != nil && root.options.containsName(removals, fieldName) {
+			subSimplifier = removeRulerSingleton
+		} else if _, propertySimplifier := s.propertyRuler(fieldName); propertySimplifier != nil {
+			subSimplifier = propertySimplifier
+		} else if isEmbeddedStruct(valueType.Field(i)) {
+			// the fields promoted from an embedded struct are matched against the rules of the
+			// struct embedding it
+			subSimplifier, promotes = s, true
+		} else {
+			subSimplifier = s.unmatchedRuler(fieldName, root)
+		}
+		child := node.child(value, field, reflect.Value{}, fieldName, -1, s, subSimplifier)
+		child.promotes = promotes
+		if err := w.visitChild(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedMapKeys returns the keys of the map value in a deterministic order.
+// String-like keys are sorted lexically, numeric keys numerically, and any other key
+// kind falls back to its fmt representation.
+func sortedMapKeys(value reflect.Value) []reflect.Value {
+	return appendSortedMapKeys(nil, value)
+}
+
+// appendSortedMapKeys appends the sorted keys of the map value to keys.
+func appendSortedMapKeys(keys []reflect.Value, value reflect.Value) []reflect.Value {
+	iter := value.MapRange()
+	for iter.Next() {
+		keys = append(keys, iter.Key())
+	}
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		if lessMapKey(a, b) {
+			return -1
+		}
+		if lessMapKey(b, a) {
+			return 1
+		}
+		return 0
+	})
+	return keys
+}
+
+func lessMapKey(a reflect.Value, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.Kind() != b.Kind() {
+		return a.Kind() < b.Kind()
+	}
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	}
+	if !a.IsValid() || !b.IsValid() {
+		return !a.IsValid() && b.IsValid()
 	}
+	return fmt.Sprint(a.Interface()) < fmt.Sprint(b.Interface())
 }
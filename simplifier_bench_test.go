@@ -44,3 +44,31 @@ func BenchmarkSimplify(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkSimplifyInPlace(b *testing.B) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest" ]
+			}
+		}
+	}`)
+	if err != nil {
+		b.Fatalf("Failed to create Simplifier: %v", err)
+	}
+
+	value := &ExampleStruct{
+		Test:       5,
+		Data:       DataStruct{DataDebug: 123},
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{ABC: "abc"}}},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value.Debug, value.Data.DataTest = "debug", "data_test"
+		if err := SimplifyInPlace(simplifier, value); err != nil {
+			b.Fatalf("Failed to simplify struct: %v", err)
+		}
+	}
+}
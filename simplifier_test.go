@@ -1,6 +1,7 @@
 package gosimplifier
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"testing"
@@ -483,8 +484,8 @@ func TestExtendSimplifier(t *testing.T) {
 		Name:     "",
 		Age:      0,
 		Data:     "",
-		Info:     nil,
-		NewField: nil,
+		Info:     &SubStruct{},
+		NewField: &AnotherStruct{},
 	}
 
 	baseSimplifier, err := NewSimplifier(baseRulesJson)
@@ -604,7 +605,7 @@ func TestExtendSimplifierWithConflictingRules(t *testing.T) {
 	expected := &ExampleStruct2{
 		Name: "",
 		Age:  0,
-		Info: nil,
+		Info: &SubStruct{},
 	}
 
 	baseSimplifier, err := NewSimplifier(baseRulesJson)
@@ -626,3 +627,307 @@ func TestExtendSimplifierWithConflictingRules(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, result)
 	}
 }
+
+func TestSortedMapKeys(t *testing.T) {
+	stringKeys := sortedMapKeys(reflect.ValueOf(map[string]int{"c": 3, "a": 1, "b": 2}))
+	var gotStrings []string
+	for _, k := range stringKeys {
+		gotStrings = append(gotStrings, k.String())
+	}
+	if !reflect.DeepEqual(gotStrings, []string{"a", "b", "c"}) {
+		t.Errorf("Expected sorted string keys, got %v", gotStrings)
+	}
+
+	intKeys := sortedMapKeys(reflect.ValueOf(map[int]bool{10: true, -1: true, 2: true}))
+	var gotInts []int64
+	for _, k := range intKeys {
+		gotInts = append(gotInts, k.Int())
+	}
+	if !reflect.DeepEqual(gotInts, []int64{-1, 2, 10}) {
+		t.Errorf("Expected sorted int keys, got %v", gotInts)
+	}
+
+	mixedKeys := sortedMapKeys(reflect.ValueOf(map[interface{}]int{"b": 1, 1: 2, "a": 3}))
+	if len(mixedKeys) != 3 {
+		t.Fatalf("Expected 3 keys, got %d", len(mixedKeys))
+	}
+	for i := 0; i < 5; i++ {
+		again := sortedMapKeys(reflect.ValueOf(map[interface{}]int{"b": 1, 1: 2, "a": 3}))
+		for j := range again {
+			if again[j].Interface() != mixedKeys[j].Interface() {
+				t.Fatal("Expected mixed keys to be ordered deterministically")
+			}
+		}
+	}
+}
+
+func TestSimplifyRootSliceOfMaps(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "debug" ],
+		"property_simplifiers": {
+			"data": {
+				"remove_properties": [ "secret" ]
+			}
+		}
+	}`)
+
+	original := []map[string]interface{}{
+		{"id": 1, "debug": "x", "data": map[string]interface{}{"secret": "s", "value": "v"}},
+		{"id": 2, "debug": "y"},
+	}
+
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []map[string]interface{}{
+		{"id": 1, "data": map[string]interface{}{"value": "v"}},
+		{"id": 2},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+	if original[0]["debug"] != "x" {
+		t.Error("Expected the original to be unchanged")
+	}
+}
+
+func TestSimplifyRootInterfaceSlice(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "debug" ] }`)
+
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(`[{"id": 1, "debug": "x"}, "text", null, [{"debug": "nested"}]]`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		map[string]interface{}{"id": float64(1)},
+		"text",
+		nil,
+		[]interface{}{map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
+
+func TestSimplifyRootArrayAndNil(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+
+	simplified, err := simplifier.Simplify([2]DataStruct{{DataTest: "a"}, {DataTest: "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := simplified.([2]DataStruct); result[1].DataTest != "b" {
+		t.Errorf("Unexpected result %v", result)
+	}
+
+	simplified, err = simplifier.Simplify([]ExampleStruct{{Test: 1, Debug: "debug"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := simplified.([]ExampleStruct); result[0].Debug != "" || result[0].Test != 1 {
+		t.Errorf("Unexpected result %v", result)
+	}
+
+	if simplified, err := simplifier.Simplify(nil); simplified != nil || err != nil {
+		t.Errorf("Expected nil for a nil input, got %v, %v", simplified, err)
+	}
+}
+
+func TestSimplifyInPlace(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest" ]
+			}
+		}
+	}`)
+
+	value := &ExampleStruct{
+		Test:  5,
+		Debug: "debug",
+		Data:  DataStruct{DataTest: "data_test", DataDebug: 123},
+	}
+	if err := SimplifyInPlace(simplifier, value); err != nil {
+		t.Fatal(err)
+	}
+	if value.Debug != "" || value.Data.DataTest != "" {
+		t.Errorf("Expected the value to be simplified in place, got %+v", value)
+	}
+	if value.Test != 5 || value.Data.DataDebug != 123 {
+		t.Errorf("Expected other fields to be kept, got %+v", value)
+	}
+
+	m := map[string]interface{}{"Debug": "debug", "Test": 5}
+	if err := SimplifyInPlace(simplifier, &m); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m["Debug"]; ok {
+		t.Error("Expected Debug to be deleted from the map")
+	}
+}
+
+func TestSimplifyInPlaceRequiresPointer(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{}`)
+
+	if err := SimplifyInPlace(simplifier, ExampleStruct{}); err == nil {
+		t.Error("Expected an error for a non-pointer")
+	}
+	if err := SimplifyInPlace(simplifier, (*ExampleStruct)(nil)); err == nil {
+		t.Error("Expected an error for a nil pointer")
+	}
+}
+
+func TestIdenticalSubRulesAreShared(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Data": { "remove_properties": [ "DataTest" ] },
+			"Nest": {
+				"property_simplifiers": {
+					"Data": { "remove_properties": [ "DataTest" ] }
+				}
+			},
+			"Other": { "remove_properties": [ "DataDebug" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := simplifier.(*simplifierImpl)
+	data := root.propertySimplifiers["Data"]
+	nestData := root.propertySimplifiers["Nest"].(*simplifierImpl).propertySimplifiers["Data"]
+	if data != nestData {
+		t.Error("Expected identical sub-rules to share one instance")
+	}
+	if data == root.propertySimplifiers["Other"] {
+		t.Error("Expected different sub-rules to have their own instances")
+	}
+
+	stats := NewStats()
+	simplifier, _ = NewSimplifierByRule(root.rule, WithStats(stats))
+	simplified, err := simplifier.Simplify(ExampleStruct{
+		Data: DataStruct{DataTest: "a"},
+		Nest: ExampleStruct0{Data: DataStruct{DataTest: "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := simplified.(ExampleStruct); result.Data.DataTest != "" || result.Nest.Data.DataTest != "" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	hits := stats.Snapshot().RuleHits
+	if hits["Data.DataTest"] != 1 || hits["Nest.Data.DataTest"] != 1 {
+		t.Errorf("Expected shared rules to keep their own rule paths, got %v", hits)
+	}
+}
+
+func TestSimplifyStructsHeldByMapsAndInterfaces(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		original interface{}
+		expected interface{}
+	}{
+		{map[string]SubStruct{"k": {Test: "t", Debug: "d"}}, map[string]SubStruct{"k": {Test: "t"}}},
+		{map[string]interface{}{"k": SubStruct{Test: "t", Debug: "d"}}, map[string]interface{}{"k": SubStruct{Test: "t"}}},
+		{[]interface{}{SubStruct{Test: "t", Debug: "d"}}, []interface{}{SubStruct{Test: "t"}}},
+		{map[string][1]SubStruct{"k": {{Test: "t", Debug: "d"}}}, map[string][1]SubStruct{"k": {{Test: "t"}}}},
+	} {
+		simplified, err := simplifier.Simplify(tc.original)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(simplified, tc.expected) {
+			t.Errorf("Expected %+v, got %+v", tc.expected, simplified)
+		}
+	}
+}
+
+// countingSimplifier is a decorator of a Simplifier, extendable through its Rules only.
+type countingSimplifier struct {
+	Simplifier
+	calls int
+}
+
+func (c *countingSimplifier) Simplify(original interface{}) (interface{}, error) {
+	c.calls++
+	return c.Simplifier.Simplify(original)
+}
+
+func (c *countingSimplifier) Rules() *Rule {
+	return Rules(c.Simplifier)
+}
+
+func TestPackageFunctionsFallBackToSimplify(t *testing.T) {
+	base, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &countingSimplifier{Simplifier: base}
+
+	if simplified, err := SimplifyContext(context.Background(), s, SubStruct{Test: "t", Debug: "d"}); err != nil || simplified != (SubStruct{Test: "t"}) {
+		t.Errorf("Unexpected SimplifyContext result %+v, %v", simplified, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SimplifyContext(ctx, s, SubStruct{}); err != context.Canceled {
+		t.Errorf("Expected the error of the context, got %v", err)
+	}
+
+	value := &SubStruct{Test: "t", Debug: "d"}
+	if err := SimplifyInPlace(s, value); err != nil || *value != (SubStruct{Test: "t"}) {
+		t.Errorf("Unexpected SimplifyInPlace result %+v, %v", *value, err)
+	}
+	var dst SubStruct
+	if err := SimplifyInto(s, SubStruct{Test: "t", Debug: "d"}, &dst); err != nil || dst != (SubStruct{Test: "t"}) {
+		t.Errorf("Unexpected SimplifyInto result %+v, %v", dst, err)
+	}
+	if data, err := SimplifyJSON(s, []byte(`{"Test":"t","Debug":"d","n":1.50}`)); err != nil || string(data) != `{"Test":"t","n":1.50}` {
+		t.Errorf("Unexpected SimplifyJSON result %s, %v", data, err)
+	}
+	if s.calls != 4 {
+		t.Errorf("Expected every call to go through Simplify, got %d calls", s.calls)
+	}
+
+	if _, err := DryRun(s, SubStruct{}); err == nil {
+		t.Error("Expected DryRun to fail for a simplifier that does not support it")
+	}
+	if Clone(s) != Simplifier(s) {
+		t.Error("Expected a simplifier that cannot be cloned to be returned as it is")
+	}
+}
+
+func TestExtendSimplifierInterface(t *testing.T) {
+	base, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, s := range map[string]Simplifier{
+		"decorator": &countingSimplifier{Simplifier: base},
+		"composed":  Compose(base),
+	} {
+		extended, err := ExtendSimplifier(s, `{ "mask_properties": [ "Test" ] }`)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		simplified, err := extended.Simplify(SubStruct{Test: "t", Debug: "d"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if simplified != (SubStruct{Test: DefaultMask}) {
+			t.Errorf("%s: expected the rules of both, got %+v", name, simplified)
+		}
+	}
+	if _, ok := base.(ExtendableSimplifier); !ok {
+		t.Error("Expected simplifiers to be extendable")
+	}
+}
@@ -0,0 +1,201 @@
+// Package simplifierbench runs a standard corpus of payload shapes against a simplifier and
+// reports a comparable score, so performance regressions between package versions can be
+// caught in CI:
+//
+//	func TestSimplifierPerformance(t *testing.T) {
+//		report, err := simplifierbench.Run(nil)
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//		if report.Score > baselineScore*1.2 {
+//			t.Errorf("simplification got slower: %s", report)
+//		}
+//	}
+//
+// Benchmark runs the same corpus as sub-benchmarks of a regular benchmark.
+package simplifierbench
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// StandardRules are the rules the corpus is designed for, used by Run and Benchmark when no
+// simplifier is given. They act on properties found in every shape of the corpus.
+const StandardRules = `{
+	"remove_properties": [ "Secret", "Debug" ],
+	"property_simplifiers": {
+		"Children": { "remove_properties": [ "Token" ] },
+		"Attributes": { "remove_properties": [ "Internal" ] }
+	}
+}`
+
+// Shape is a payload shape of the corpus.
+type Shape struct {
+	Name string
+	// Value is the payload simplified by every iteration. It is not modified.
+	Value interface{}
+}
+
+// DeepNode is the element of the deep struct shape.
+type DeepNode struct {
+	Name     string
+	Secret   string
+	Token    string
+	Debug    []byte
+	Children []DeepNode
+}
+
+// Record is the element of the long slice shape.
+type Record struct {
+	ID         int64
+	Name       string
+	Secret     string
+	Debug      string
+	Attributes map[string]string
+}
+
+// Corpus returns the standard payload shapes: deep structs, wide maps, long slices and trees of
+// interface values as decoded from JSON.
+func Corpus() []Shape {
+	return []Shape{
+		{Name: "DeepStruct", Value: deepNode(8)},
+		{Name: "WideMap", Value: wideMap(2000)},
+		{Name: "LongSlice", Value: longSlice(5000)},
+		{Name: "InterfaceTree", Value: interfaceTree(5, 6)},
+	}
+}
+
+func deepNode(depth int) DeepNode {
+	node := DeepNode{Name: "node", Secret: "secret", Token: "token", Debug: []byte("debug")}
+	if depth > 0 {
+		node.Children = []DeepNode{deepNode(depth - 1), deepNode(depth - 1)}
+	}
+	return node
+}
+
+func wideMap(width int) map[string]interface{} {
+	m := make(map[string]interface{}, width+2)
+	for i := 0; i < width; i++ {
+		m["key"+strconv.Itoa(i)] = "value"
+	}
+	m["Secret"] = "secret"
+	m["Debug"] = "debug"
+	return m
+}
+
+func longSlice(length int) []Record {
+	records := make([]Record, length)
+	for i := range records {
+		records[i] = Record{
+			ID:         int64(i),
+			Name:       "record",
+			Secret:     "secret",
+			Debug:      "debug",
+			Attributes: map[string]string{"Internal": "internal", "Region": "eu"},
+		}
+	}
+	return records
+}
+
+func interfaceTree(depth int, width int) interface{} {
+	if depth == 0 {
+		return []interface{}{"leaf", 1.5, true, nil}
+	}
+	m := map[string]interface{}{"Secret": "secret", "Name": "node"}
+	children := make([]interface{}, width)
+	for i := range children {
+		children[i] = interfaceTree(depth-1, width/2+1)
+	}
+	m["Children"] = children
+	return m
+}
+
+// Result is the measurement of a single shape.
+type Result struct {
+	Shape       string
+	NsPerOp     int64
+	AllocsPerOp int64
+	BytesPerOp  int64
+}
+
+// Report is the outcome of Run.
+type Report struct {
+	Results []Result
+	// Score is the geometric mean of the ns/op of the shapes; lower is faster. Scores are only
+	// comparable when measured on the same machine with the same rules.
+	Score float64
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "score %.0f", r.Score)
+	for _, result := range r.Results {
+		fmt.Fprintf(&b, "; %s %d ns/op %d allocs/op %d B/op", result.Shape, result.NsPerOp, result.AllocsPerOp, result.BytesPerOp)
+	}
+	return b.String()
+}
+
+// Run measures s, or a simplifier with StandardRules if s is nil, against every shape of the corpus.
+func Run(s gosimplifier.Simplifier) (Report, error) {
+	s, err := standard(s)
+	if err != nil {
+		return Report{}, err
+	}
+	var report Report
+	logSum := 0.0
+	for _, shape := range Corpus() {
+		if _, err := s.Simplify(shape.Value); err != nil {
+			return Report{}, fmt.Errorf("%s: %w", shape.Name, err)
+		}
+		result := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			benchmarkShape(b, s, shape)
+		})
+		report.Results = append(report.Results, Result{
+			Shape:       shape.Name,
+			NsPerOp:     result.NsPerOp(),
+			AllocsPerOp: result.AllocsPerOp(),
+			BytesPerOp:  result.AllocedBytesPerOp(),
+		})
+		logSum += math.Log(math.Max(float64(result.NsPerOp()), 1))
+	}
+	report.Score = math.Exp(logSum / float64(len(report.Results)))
+	return report, nil
+}
+
+// Benchmark runs every shape of the corpus as a sub-benchmark of b against s, or a simplifier
+// with StandardRules if s is nil.
+func Benchmark(b *testing.B, s gosimplifier.Simplifier) {
+	s, err := standard(s)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, shape := range Corpus() {
+		shape := shape
+		b.Run(shape.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			benchmarkShape(b, s, shape)
+		})
+	}
+}
+
+func benchmarkShape(b *testing.B, s gosimplifier.Simplifier, shape Shape) {
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Simplify(shape.Value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func standard(s gosimplifier.Simplifier) (gosimplifier.Simplifier, error) {
+	if s != nil {
+		return s, nil
+	}
+	return gosimplifier.NewSimplifier(StandardRules)
+}
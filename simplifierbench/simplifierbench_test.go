@@ -0,0 +1,41 @@
+package simplifierbench
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+func TestCorpusIsSimplified(t *testing.T) {
+	s, err := gosimplifier.NewSimplifier(StandardRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, shape := range Corpus() {
+		simplified, err := s.Simplify(shape.Value)
+		if err != nil {
+			t.Fatalf("%s: %v", shape.Name, err)
+		}
+		if reflect.DeepEqual(simplified, shape.Value) {
+			t.Errorf("%s: expected the standard rules to act on the shape", shape.Name)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("measuring the corpus takes a few seconds")
+	}
+	report, err := Run(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != len(Corpus()) || report.Score <= 0 {
+		t.Errorf("Unexpected report %s", report)
+	}
+}
+
+func BenchmarkCorpus(b *testing.B) {
+	Benchmark(b, nil)
+}
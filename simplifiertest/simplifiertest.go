@@ -0,0 +1,207 @@
+// Package simplifiertest runs table-driven expectations against simplification policies, so
+// policy changes are reviewed with behavioral tests instead of by eyeballing JSON.
+//
+//	func TestPublicPolicy(t *testing.T) {
+//		simplifiertest.RunFiles(t, "policies/public.json", "testdata/user.json",
+//			simplifiertest.Removed("password"),
+//			simplifiertest.Kept("name"),
+//			simplifiertest.Masked("cards[0].number"),
+//		)
+//	}
+package simplifiertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// Outcome is what a policy is expected to do with a path.
+type Outcome int
+
+const (
+	// OutcomeRemoved expects the path to be absent or hold a zero value.
+	OutcomeRemoved Outcome = iota
+	// OutcomeKept expects the path to hold the value of the fixture.
+	OutcomeKept
+	// OutcomeMasked expects the path to be present with a value different from the fixture.
+	OutcomeMasked
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeRemoved:
+		return "removed"
+	case OutcomeKept:
+		return "kept"
+	case OutcomeMasked:
+		return "masked"
+	}
+	return "Outcome(" + strconv.Itoa(int(o)) + ")"
+}
+
+// Expectation is a single row of the table: the outcome expected for a path of the fixture.
+// Paths use dots for object properties and brackets for array indexes, e.g. "cards[0].number".
+type Expectation struct {
+	Path    string
+	Outcome Outcome
+	// Value, if set for OutcomeMasked, is the exact value the path must hold.
+	Value interface{}
+}
+
+// Removed expects the path to be removed.
+func Removed(path string) Expectation {
+	return Expectation{Path: path, Outcome: OutcomeRemoved}
+}
+
+// Kept expects the path to be kept unchanged.
+func Kept(path string) Expectation {
+	return Expectation{Path: path, Outcome: OutcomeKept}
+}
+
+// Masked expects the path to be present with a changed value. If value is given, the path
+// must hold exactly that value.
+func Masked(path string, value ...interface{}) Expectation {
+	e := Expectation{Path: path, Outcome: OutcomeMasked}
+	if len(value) > 0 {
+		e.Value = value[0]
+	}
+	return e
+}
+
+// Run simplifies the JSON fixture with s and reports every unmet expectation on t.
+func Run(t testing.TB, s gosimplifier.Simplifier, fixture []byte, expectations ...Expectation) {
+	t.Helper()
+	var original interface{}
+	if err := json.Unmarshal(fixture, &original); err != nil {
+		t.Fatalf("invalid fixture: %v", err)
+	}
+	simplified, err := s.Simplify(original)
+	if err != nil {
+		t.Fatalf("simplify fixture: %v", err)
+	}
+	// Compare in the JSON form, as downstream consumers see it
+	simplified, err = roundTrip(simplified)
+	if err != nil {
+		t.Fatalf("encode simplified fixture: %v", err)
+	}
+
+	for _, e := range expectations {
+		if err := check(e, original, simplified); err != nil {
+			t.Errorf("%s: expected %s: %v", e.Path, e.Outcome, err)
+		}
+	}
+}
+
+// RunFiles loads the rules and the JSON fixture from files and calls Run.
+func RunFiles(t testing.TB, rulesPath string, fixturePath string, expectations ...Expectation) {
+	t.Helper()
+	rules, err := os.ReadFile(rulesPath)
+	if err != nil {
+		t.Fatalf("read rules: %v", err)
+	}
+	fixture, err := os.ReadFile(fixturePath)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	s, err := gosimplifier.NewSimplifier(string(rules))
+	if err != nil {
+		t.Fatalf("load rules %s: %v", rulesPath, err)
+	}
+	Run(t, s, fixture, expectations...)
+}
+
+func check(e Expectation, original interface{}, simplified interface{}) error {
+	originalValue, ok := lookup(original, e.Path)
+	if !ok {
+		return fmt.Errorf("path does not exist in the fixture")
+	}
+	value, present := lookup(simplified, e.Path)
+	switch e.Outcome {
+	case OutcomeRemoved:
+		if present && !isZero(value) {
+			return fmt.Errorf("got %v", value)
+		}
+	case OutcomeKept:
+		if !present {
+			return fmt.Errorf("path was removed")
+		}
+		if !reflect.DeepEqual(value, originalValue) {
+			return fmt.Errorf("got %v, fixture has %v", value, originalValue)
+		}
+	case OutcomeMasked:
+		if !present || isZero(value) {
+			return fmt.Errorf("path was removed")
+		}
+		if e.Value != nil {
+			want, err := roundTrip(e.Value)
+			if err != nil {
+				return err
+			}
+			if !reflect.DeepEqual(value, want) {
+				return fmt.Errorf("got %v, want %v", value, want)
+			}
+		} else if reflect.DeepEqual(value, originalValue) {
+			return fmt.Errorf("value is unchanged")
+		}
+	default:
+		return fmt.Errorf("unknown outcome")
+	}
+	return nil
+}
+
+// lookup returns the value at path in a decoded JSON document.
+func lookup(document interface{}, path string) (interface{}, bool) {
+	current := document
+	for _, segment := range splitPath(path) {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// splitPath splits "cards[0].number" into "cards", "0", "number".
+func splitPath(path string) []string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	var segments []string
+	for _, segment := range strings.Split(path, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func isZero(value interface{}) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
+
+func roundTrip(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	err = json.Unmarshal(data, &decoded)
+	return decoded, err
+}
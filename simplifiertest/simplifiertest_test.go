@@ -0,0 +1,89 @@
+package simplifiertest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+const fixture = `{
+	"name": "john",
+	"password": "secret",
+	"cards": [ { "number": "4111", "type": "visa" } ],
+	"token": "abc"
+}`
+
+// recorder captures the failures reported by Run.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRun(t *testing.T) {
+	vault := func(token string, value interface{}) error { return nil }
+	s, err := gosimplifier.NewSimplifier(`{
+		"remove_properties": [ "password" ],
+		"redact_properties": [ "token" ]
+	}`, gosimplifier.WithVault(vault))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Run(t, s, []byte(fixture),
+		Removed("password"),
+		Kept("name"),
+		Kept("cards[0].number"),
+		Masked("token"),
+	)
+}
+
+func TestRunReportsFailures(t *testing.T) {
+	s, _ := gosimplifier.NewSimplifier(`{ "remove_properties": [ "password" ] }`)
+
+	r := &recorder{TB: t}
+	Run(r, s, []byte(fixture),
+		Kept("password"),
+		Removed("name"),
+		Masked("cards[0].type"),
+		Masked("cards[0].number", "****"),
+		Kept("missing"),
+	)
+
+	expected := []string{
+		"password: expected kept: path was removed",
+		"name: expected removed: got john",
+		"cards[0].type: expected masked: value is unchanged",
+		"cards[0].number: expected masked: got 4111, want ****",
+		"missing: expected kept: path does not exist in the fixture",
+	}
+	if !reflect.DeepEqual(r.errors, expected) {
+		t.Errorf("Expected %q, got %q", expected, r.errors)
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	rulesPath, fixturePath := filepath.Join(dir, "rules.json"), filepath.Join(dir, "fixture.json")
+	if err := os.WriteFile(rulesPath, []byte(`{ "property_simplifiers": { "cards": { "remove_properties": [ "number" ] } } }`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fixturePath, []byte(fixture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	RunFiles(t, rulesPath, fixturePath,
+		Removed("cards[0].number"),
+		Kept("cards[0].type"),
+		Kept("password"),
+	)
+}
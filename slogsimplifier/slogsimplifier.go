@@ -0,0 +1,102 @@
+// Package slogsimplifier connects gosimplifier to log/slog, simplifying the attribute values of
+// records so sensitive sub-fields are scrubbed before they are handled.
+package slogsimplifier
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+// Selector returns the Simplifier of the value of an attribute, or nil to log it as it is.
+type Selector func(key string, value interface{}) gosimplifier.Simplifier
+
+// ByKey selects the Simplifier by the key of the attribute. Attributes with no listed key use
+// the Simplifier of "", if any.
+func ByKey(simplifiers map[string]gosimplifier.Simplifier) Selector {
+	return func(key string, _ interface{}) gosimplifier.Simplifier {
+		if s, ok := simplifiers[key]; ok {
+			return s
+		}
+		return simplifiers[""]
+	}
+}
+
+// ByType selects the Simplifier by the dynamic type of the value of the attribute, pointers to
+// the listed types included.
+func ByType(simplifiers map[reflect.Type]gosimplifier.Simplifier) Selector {
+	return func(_ string, value interface{}) gosimplifier.Simplifier {
+		t := reflect.TypeOf(value)
+		if s, ok := simplifiers[t]; ok || t == nil || t.Kind() != reflect.Ptr {
+			return s
+		}
+		return simplifiers[t.Elem()]
+	}
+}
+
+// NewHandler wraps h so the attributes of records and of WithAttrs holding arbitrary values,
+// as logged with slog.Any, are replaced with the copies simplified by the Simplifier selected
+// for them, in groups too. LogValuer values are resolved first. A value that cannot be
+// simplified is replaced with the error, so the original never reaches the log.
+func NewHandler(h slog.Handler, selector Selector) slog.Handler {
+	return &handler{next: h, selector: selector}
+}
+
+type handler struct {
+	next     slog.Handler
+	selector Selector
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	simplified := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		simplified.AddAttrs(h.simplify(ctx, attr))
+		return true
+	})
+	return h.next.Handle(ctx, simplified)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	simplified := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		simplified[i] = h.simplify(context.Background(), attr)
+	}
+	return &handler{next: h.next.WithAttrs(simplified), selector: h.selector}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name), selector: h.selector}
+}
+
+// simplify returns attr with its value simplified.
+func (h *handler) simplify(ctx context.Context, attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		simplified := make([]slog.Attr, len(group))
+		for i, member := range group {
+			simplified[i] = h.simplify(ctx, member)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(simplified...)}
+	case slog.KindAny:
+		s := h.selector(attr.Key, value.Any())
+		if s == nil {
+			return attr
+		}
+		result, err := gosimplifier.SimplifyContext(ctx, s, value.Any())
+		var partial *gosimplifier.PartialError
+		if err != nil && !errors.As(err, &partial) {
+			return slog.String(attr.Key, err.Error())
+		}
+		return slog.Any(attr.Key, result)
+	}
+	return attr
+}
@@ -0,0 +1,57 @@
+package slogsimplifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+)
+
+type User struct {
+	Name     string
+	Password string
+}
+
+func TestNewHandler(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Password" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&out, nil), ByType(map[reflect.Type]gosimplifier.Simplifier{
+		reflect.TypeOf(User{}): simplifier,
+	})))
+
+	user := &User{Name: "a", Password: "secret"}
+	logger.With(slog.Any("owner", User{Name: "b", Password: "secret"})).
+		Info("login", slog.Any("user", user), slog.Group("request", slog.Any("user", user)), slog.String("password", "kept"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Errorf("Expected the passwords to be removed, got %s", out.String())
+	}
+	if entry["password"] != "kept" {
+		t.Errorf("Expected the other attributes to be left as they are, got %s", out.String())
+	}
+	if user.Password != "secret" {
+		t.Error("Expected the logged value to be left unchanged")
+	}
+}
+
+func TestByKey(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Password" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selector := ByKey(map[string]gosimplifier.Simplifier{"user": simplifier})
+	if selector("user", nil) != simplifier || selector("other", nil) != nil {
+		t.Error("Expected the Simplifier to be selected by key")
+	}
+}
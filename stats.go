@@ -0,0 +1,124 @@
+package gosimplifier
+
+import "sync"
+
+// Stats collects counters of the Simplify calls of the simplifiers it is attached to with
+// WithStats. It is safe for concurrent use: every Simplify call is added atomically, so a
+// Snapshot never observes a call half-counted. The zero value is an empty Stats ready to use.
+type Stats struct {
+	mu       sync.Mutex
+	calls    uint64
+	errors   uint64
+	ruleHits map[string]uint64
+}
+
+// StatsSnapshot is a point-in-time copy of the counters of a Stats.
+type StatsSnapshot struct {
+	// Calls is the number of Simplify calls.
+	Calls uint64
+	// Errors is the number of Simplify calls that returned an error.
+	Errors uint64
+	// RuleHits counts how often each rule acted, keyed by rule path (see Node.RulePath).
+	// Rules of the attached simplifiers that never acted are present with a zero count,
+	// which makes uncovered rules visible.
+	RuleHits map[string]uint64
+}
+
+// NewStats creates an empty Stats.
+func NewStats() *Stats {
+	return &Stats{ruleHits: make(map[string]uint64)}
+}
+
+// WithStats counts the Simplify calls and rule hits of the simplifier into stats.
+func WithStats(stats *Stats) Option {
+	return func(o *options) {
+		o.stats = stats
+		o.middlewares = append(o.middlewares, statsMiddleware)
+	}
+}
+
+// Snapshot returns a copy of the current counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot()
+}
+
+// Reset sets all counters to zero and returns the values they held, in one atomic step, so no
+// Simplify call is lost between a scrape and the reset.
+func (s *Stats) Reset() StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := s.snapshot()
+	s.calls, s.errors = 0, 0
+	for rulePath := range s.ruleHits {
+		s.ruleHits[rulePath] = 0
+	}
+	return snapshot
+}
+
+func (s *Stats) snapshot() StatsSnapshot {
+	ruleHits := make(map[string]uint64, len(s.ruleHits))
+	for rulePath, hits := range s.ruleHits {
+		ruleHits[rulePath] = hits
+	}
+	return StatsSnapshot{
+		Calls:    s.calls,
+		Errors:   s.errors,
+		RuleHits: ruleHits,
+	}
+}
+
+// register makes the rules of s visible in snapshots before they are hit.
+func (s *Stats) register(simplifier *simplifierImpl) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ruleHits == nil {
+		s.ruleHits = make(map[string]uint64)
+	}
+	registerRulePaths(simplifier, "", s.ruleHits)
+}
+
+func registerRulePaths(simplifier *simplifierImpl, path string, ruleHits map[string]uint64) {
+	for propName, r := range simplifier.propertySimplifiers {
+		rulePath := joinRulePath(path, propName)
+		if sub, ok := r.(*simplifierImpl); ok {
+			registerRulePaths(sub, rulePath, ruleHits)
+			continue
+		}
+		if _, ok := ruleHits[rulePath]; !ok {
+			ruleHits[rulePath] = 0
+		}
+	}
+}
+
+// add records a finished Simplify call with the rule hits collected while walking it.
+func (s *Stats) add(ruleHits map[string]uint64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if err != nil {
+		s.errors++
+	}
+	if s.ruleHits == nil && len(ruleHits) > 0 {
+		s.ruleHits = make(map[string]uint64, len(ruleHits))
+	}
+	for rulePath, hits := range ruleHits {
+		s.ruleHits[rulePath] += hits
+	}
+}
+
+// statsMiddleware counts the rule hits of a single Simplify call.
+func statsMiddleware(next Walker) Walker {
+	return func(node *Node) error {
+		if node.Action() != "" {
+			if rulePath := node.RulePath(); rulePath != "" {
+				if node.walk.ruleHits == nil {
+					node.walk.ruleHits = make(map[string]uint64)
+				}
+				node.walk.ruleHits[rulePath]++
+			}
+		}
+		return next(node)
+	}
+}
@@ -0,0 +1,107 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	stats := NewStats()
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest", "Missing" ]
+			}
+		}
+	}`, WithStats(stats))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := simplifier.Simplify(ExampleStruct{Debug: "debug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := StatsSnapshot{
+		Calls: 1,
+		RuleHits: map[string]uint64{
+			"Debug":         2,
+			"Data.DataTest": 2,
+			"Data.Missing":  0,
+		},
+	}
+	if snapshot := stats.Snapshot(); !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, snapshot)
+	}
+
+	if reset := stats.Reset(); !reflect.DeepEqual(reset, expected) {
+		t.Errorf("Expected Reset to return %+v, got %+v", expected, reset)
+	}
+	if snapshot := stats.Snapshot(); snapshot.Calls != 0 || snapshot.RuleHits["Debug"] != 0 {
+		t.Errorf("Expected counters to be reset, got %+v", snapshot)
+	}
+	if _, ok := stats.Snapshot().RuleHits["Data.Missing"]; !ok {
+		t.Error("Expected uncovered rules to stay visible after Reset")
+	}
+}
+
+func TestStatsZeroValue(t *testing.T) {
+	var stats Stats
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithStats(&stats))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := simplifier.Simplify(ExampleStruct{Debug: "debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot := stats.Snapshot(); snapshot.Calls != 1 || snapshot.RuleHits["Debug"] == 0 {
+		t.Errorf("Expected the zero Stats to count the call, got %+v", snapshot)
+	}
+}
+
+func TestStatsConcurrentReset(t *testing.T) {
+	stats := NewStats()
+	simplifier, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`, WithStats(stats))
+
+	const workers, calls = 8, 200
+	var total uint64
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	scraped := make(chan uint64)
+	go func() {
+		var sum uint64
+		for {
+			select {
+			case <-done:
+				scraped <- sum + stats.Reset().Calls
+				return
+			default:
+				snapshot := stats.Reset()
+				if snapshot.RuleHits["Debug"] != snapshot.Calls*2 {
+					t.Errorf("Expected rule hits to match calls, got %+v", snapshot)
+				}
+				sum += snapshot.Calls
+			}
+		}
+	}()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < calls; j++ {
+				if _, err := simplifier.Simplify(ExampleStruct{Debug: "debug"}); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	total = <-scraped
+
+	if total != workers*calls {
+		t.Errorf("Expected %d calls across resets, got %d", workers*calls, total)
+	}
+}
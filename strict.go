@@ -0,0 +1,50 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"sort"
+)
+
+// WithStrictFields makes Simplify fail when a rule names a property that does not exist on the
+// struct it is applied to, so typos in rules are reported instead of silently leaking the field.
+//
+// Only rules reached through their own path are checked: the root rules that are applied to
+// unmatched nested values, and rules applied to maps, whose keys are dynamic, are not.
+func WithStrictFields() Option {
+	return func(o *options) {
+		o.strictFields = true
+	}
+}
+
+// checkFields returns an *ErrUnknownProperty if a rule of s, located at path in the rule tree,
+// names a property that structType does not have. Glob patterns, "*" included, name no property
+// in particular and are not checked, nor are the names globstars push into every rule.
+func (s *simplifierImpl) checkFields(structType reflect.Type, path string, o *options) error {
+	var unknown []string
+	for propName := range s.propertySimplifiers {
+		if isIndexSelector(propName) || isGlob(propName) || o.globstarNames[propName] {
+			continue
+		}
+		if _, ok := o.fieldByName(structType, propName); !ok {
+			unknown = append(unknown, propName)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return &ErrUnknownProperty{Path: path, Properties: unknown, Type: structType}
+}
+
+// explicit reports whether the rules of the node were selected by their path, as opposed to
+// the root rules applied to a value no rule names.
+func (n *Node) explicit() bool {
+	if n.parent == nil {
+		return true
+	}
+	if n.index >= 0 {
+		return n.parent.explicit()
+	}
+	_, ok := n.rules.ruleName(n.name, n.ruler)
+	return ok
+}
@@ -0,0 +1,81 @@
+package gosimplifier
+
+import (
+	"testing"
+)
+
+func TestStrictFields(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTset" ]
+			}
+		}
+	}`, WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(ExampleStruct{Data: DataStruct{DataTest: "data_test"}})
+	if err == nil {
+		t.Fatal("Expected an error for the misspelled property")
+	}
+	if simplified != nil {
+		t.Error("Expected no result on error")
+	}
+	if expected := `rule "Data" names unknown properties ["DataTset"] of gosimplifier.DataStruct`; err.Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestStrictFieldsRemoveAll(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Data": { "remove_properties": [ "*" ], "except": [ "DataTest" ] }
+		}
+	}`, WithStrictFields())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(ExampleStruct{Data: DataStruct{DataTest: "t", DataDebug: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := simplified.(ExampleStruct).Data; data != (DataStruct{DataTest: "t"}) {
+		t.Errorf("Unexpected result %+v", data)
+	}
+}
+
+func TestStrictFieldsValid(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug", "Test" ],
+		"property_simplifiers": {
+			"EntityList": {
+				"property_simplifiers": {
+					"SubProperties": {
+						"remove_properties": [ "ABC" ]
+					}
+				}
+			}
+		}
+	}`, WithStrictFields())
+
+	// Root rules are also applied to Data and Nest, which lack some of the root properties;
+	// that is not reported.
+	simplified, err := simplifier.Simplify(ExampleStruct{
+		Debug:      "debug",
+		EntityList: []EntityStruct{{SubProperties: SubPropertyStruct{ABC: "abc"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := simplified.(ExampleStruct); result.Debug != "" || result.EntityList[0].SubProperties.ABC != "" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	if _, err := simplifier.Simplify(map[string]interface{}{"other": 1}); err != nil {
+		t.Errorf("Expected maps to be exempt, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagName is the struct tag read by NewSimplifierFromType.
+const TagName = "simplify"
+
+// NewSimplifierFromType creates a Simplifier from the `simplify` struct tags of t, so the
+// redaction intent lives next to the field definitions:
+//
+//	type User struct {
+//		Name     string
+//		Password string  `simplify:"remove"`
+//		Token    string  `simplify:"redact"`
+//		Phone    string  `simplify:"mask"`
+//		Email    string  `simplify:"hash"`
+//		SSN      string  `simplify:"tokenize"`
+//		Profile  Profile // tags of Profile are applied to User.Profile
+//	}
+//
+// Nested structs are followed through pointers, slices, arrays and map values.
+func NewSimplifierFromType(t reflect.Type, opts ...Option) (Simplifier, error) {
+	rule, err := ruleFromType(t, newOptions(opts), map[reflect.Type]bool{})
+	if err != nil {
+		return nil, err
+	}
+	return NewSimplifierByRule(rule, opts...)
+}
+
+// ruleFromType builds the rule for the tags of t. inProgress guards against recursive types.
+func ruleFromType(t reflect.Type, o *options, inProgress map[reflect.Type]bool) (*Rule, error) {
+	rule := &Rule{}
+	t = elemStructType(t)
+	if t == nil || inProgress[t] {
+		return rule, nil
+	}
+	inProgress[t] = true
+	defer delete(inProgress, t)
+
+	fieldNames := o.fieldNames(t)
+	for i := 0; i < t.NumField(); i++ {
+		field, fieldName := t.Field(i), fieldNames[i]
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		switch tag := field.Tag.Get(TagName); tag {
+		case "remove":
+			rule.RemoveProperties = append(rule.RemoveProperties, fieldName)
+		case "redact":
+			rule.RedactProperties = append(rule.RedactProperties, fieldName)
+		case "mask":
+			rule.MaskProperties = append(rule.MaskProperties, fieldName)
+		case "hash":
+			rule.HashProperties = append(rule.HashProperties, fieldName)
+		case "tokenize":
+			rule.TokenizeProperties = append(rule.TokenizeProperties, fieldName)
+		case "":
+			subRule, err := ruleFromType(field.Type, o, inProgress)
+			if err != nil {
+				return nil, err
+			}
+			if !isEmptyRule(subRule) {
+				if rule.PropertySimplifiers == nil {
+					rule.PropertySimplifiers = make(map[string]*Rule)
+				}
+				rule.PropertySimplifiers[fieldName] = subRule
+			}
+		default:
+			return nil, fmt.Errorf("%s.%s: unknown %s tag %q", t, field.Name, TagName, tag)
+		}
+	}
+	return rule, nil
+}
+
+// elemStructType returns the struct type reached from t through pointers, slices, arrays
+// and map values, or nil if there is none.
+func elemStructType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			return t
+		default:
+			return nil
+		}
+	}
+}
+
+// isEmptyRule reports whether the rule does nothing.
+func isEmptyRule(rule *Rule) bool {
+	return len(rule.RemoveProperties) == 0 && len(rule.RedactProperties) == 0 && len(rule.MaskProperties) == 0 &&
+		len(rule.HashProperties) == 0 && len(rule.TokenizeProperties) == 0 && len(rule.PropertySimplifiers) == 0 && len(rule.KeepProperties) == 0
+}
+
+// WithRemoveTagged removes every struct field carrying the struct tag key, whatever its name or
+// path, so fields annotated once on the model are dropped by every policy:
+//
+//	type User struct {
+//		Name string
+//		SSN  string `sensitive:"true"`
+//	}
+//
+//	gosimplifier.WithRemoveTagged("sensitive", "true")
+//
+// If values are given, only fields whose tag value is one of them are removed.
+// The removal takes precedence over the rules for the field.
+func WithRemoveTagged(key string, values ...string) Option {
+	return func(o *options) {
+		if o.removeTags == nil {
+			o.removeTags = make(map[string][]string)
+		}
+		o.removeTags[key] = append(o.removeTags[key], values...)
+	}
+}
+
+// removesTagged reports whether the field carries one of the tags configured with WithRemoveTagged.
+func (o *options) removesTagged(field reflect.StructField) bool {
+	for key, values := range o.removeTags {
+		tag, ok := field.Tag.Lookup(key)
+		if ok && (len(values) == 0 || contains(values, tag)) {
+			return true
+		}
+	}
+	return false
+}
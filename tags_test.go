@@ -0,0 +1,117 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TaggedUser struct {
+	Name     string
+	Password string `simplify:"remove"`
+	Profile  *TaggedProfile
+	Friends  []TaggedUser
+}
+
+type TaggedProfile struct {
+	City  string
+	Email string `simplify:"remove"`
+}
+
+func TestNewSimplifierFromType(t *testing.T) {
+	simplifier, err := NewSimplifierFromType(reflect.TypeOf(TaggedUser{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := TaggedUser{
+		Name:     "john",
+		Password: "secret",
+		Profile:  &TaggedProfile{City: "Berlin", Email: "john@example.com"},
+		Friends:  []TaggedUser{{Name: "jane", Password: "secret"}},
+	}
+
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := simplified.(TaggedUser)
+	if result.Name != "john" || result.Password != "" {
+		t.Errorf("Unexpected root fields %+v", result)
+	}
+	if result.Friends[0].Name != "jane" || result.Friends[0].Password != "" {
+		t.Errorf("Unexpected friend %+v", result.Friends[0])
+	}
+	if original.Password != "secret" {
+		t.Error("Expected the original to be unchanged")
+	}
+}
+
+func TestRuleFromType(t *testing.T) {
+	rule, err := ruleFromType(reflect.TypeOf(&TaggedUser{}), newOptions(nil), map[reflect.Type]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Rule{
+		RemoveProperties: []string{"Password"},
+		PropertySimplifiers: map[string]*Rule{
+			"Profile": {
+				RemoveProperties: []string{"Email"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(rule, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rule)
+	}
+}
+
+func TestNewSimplifierFromTypeUnknownTag(t *testing.T) {
+	type Invalid struct {
+		Field string `simplify:"delete"`
+	}
+	if _, err := NewSimplifierFromType(reflect.TypeOf(Invalid{})); err == nil {
+		t.Error("Expected an error for an unknown tag")
+	}
+}
+
+type SensitiveAccount struct {
+	Owner   string
+	IBAN    string `sensitive:"true"`
+	Note    string `sensitive:"false"`
+	Holders []SensitiveHolder
+}
+
+type SensitiveHolder struct {
+	Name string
+	SSN  string `sensitive:"true"`
+}
+
+func TestWithRemoveTagged(t *testing.T) {
+	original := SensitiveAccount{
+		Owner:   "john",
+		IBAN:    "DE00",
+		Note:    "note",
+		Holders: []SensitiveHolder{{Name: "jane", SSN: "123"}},
+	}
+	expected := SensitiveAccount{
+		Owner:   "john",
+		Note:    "note",
+		Holders: []SensitiveHolder{{Name: "jane"}},
+	}
+
+	simplifier, _ := NewSimplifier(`{ "property_simplifiers": { "IBAN": { "remove_properties": [ "X" ] } } }`,
+		WithRemoveTagged("sensitive", "true"))
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Unexpected result %+v", simplified)
+	}
+
+	simplifier, _ = NewSimplifier(`{}`, WithRemoveTagged("sensitive"))
+	simplified, _ = simplifier.Simplify(original)
+	if result := simplified.(SensitiveAccount); result.Note != "" || result.IBAN != "" || result.Owner != "john" {
+		t.Errorf("Expected every tagged field to be removed, got %+v", result)
+	}
+}
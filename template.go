@@ -0,0 +1,53 @@
+package gosimplifier
+
+import "fmt"
+
+// TemplateFuncs returns the "simplify" function for the Funcs method of html/template and
+// text/template templates, so server-rendered pages and notifications can emit scrubbed
+// copies of the objects they are given:
+//
+//	tmpl := template.New("user").Funcs(gosimplifier.TemplateFuncs(publicUser))
+//
+//	{{ (simplify .User).Name }}
+//	{{ with simplify "public_api_v2" .Order }}{{ .ID }}{{ end }}
+//
+// With one argument, the value is simplified by s; with a name before it, by the rules
+// registered under the name, see RegisterRules. A value that cannot be simplified fails the
+// execution of the template rather than rendering the original.
+func TemplateFuncs(s Simplifier) map[string]interface{} {
+	return map[string]interface{}{
+		"simplify": func(args ...interface{}) (interface{}, error) {
+			simplifier, value, err := templateArgs(s, args)
+			if err != nil {
+				return nil, err
+			}
+			simplified, err := simplifier.Simplify(value)
+			if err != nil && !isPartial(err) {
+				return nil, err
+			}
+			return simplified, nil
+		},
+	}
+}
+
+// templateArgs returns the simplifier and the value of the arguments of simplify.
+func templateArgs(s Simplifier, args []interface{}) (Simplifier, interface{}, error) {
+	switch len(args) {
+	case 1:
+		if s == nil {
+			return nil, nil, fmt.Errorf("simplify requires the name of registered rules, as TemplateFuncs has no Simplifier")
+		}
+		return s, args[0], nil
+	case 2:
+		name, ok := args[0].(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("simplify expects the name of registered rules, got %T", args[0])
+		}
+		registered, ok := Get(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("simplify: no rules registered under %q", name)
+		}
+		return registered, args[1], nil
+	}
+	return nil, nil, fmt.Errorf("simplify expects a value, optionally after the name of registered rules, got %d arguments", len(args))
+}
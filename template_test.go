@@ -0,0 +1,55 @@
+package gosimplifier
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+type templateUser struct {
+	Name  string
+	Email string
+}
+
+func init() {
+	MustRegisterRules("template_test_mask_name", `{ "mask_properties": [ "Name" ] }`)
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	s, _ := NewSimplifier(`{ "remove_properties": [ "Email" ] }`)
+	user := templateUser{Name: "alice", Email: "alice@example.com"}
+
+	text := template.Must(template.New("text").Funcs(TemplateFuncs(s)).Parse(
+		`{{ with simplify .User }}{{ .Name }}<{{ .Email }}>{{ end }} {{ (simplify "template_test_mask_name" .User).Name }}`))
+	var out strings.Builder
+	if err := text.Execute(&out, map[string]interface{}{"User": user}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "alice<> ****"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	html := htmltemplate.Must(htmltemplate.New("html").Funcs(TemplateFuncs(s)).Parse(`<b>{{ (simplify .).Email }}</b>`))
+	out.Reset()
+	if err := html.Execute(&out, user); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<b></b>"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestTemplateFuncsErrors(t *testing.T) {
+	for source, expected := range map[string]string{
+		`{{ simplify . }}`:                   "as TemplateFuncs has no Simplifier",
+		`{{ simplify "template_test_x" . }}`: `no rules registered under "template_test_x"`,
+		`{{ simplify 1 . }}`:                 "expects the name of registered rules, got int",
+		`{{ simplify }}`:                     "got 0 arguments",
+	} {
+		tmpl := template.Must(template.New("t").Funcs(TemplateFuncs(nil)).Parse(source))
+		if err := tmpl.Execute(&strings.Builder{}, templateUser{}); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected an error containing %q, got %v", source, expected, err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package gosimplifier
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Tokenizer swaps the values of tokenize_properties for opaque tokens and records the mapping,
+// e.g. in a database behind access control, so support staff can look the original values up
+// from a redacted payload. It must be safe for concurrent use.
+type Tokenizer interface {
+	// Tokenize returns the token replacing value, after recording the mapping. ctx is the
+	// context of the Simplify call.
+	Tokenize(ctx context.Context, value interface{}) (string, error)
+	// Detokenize returns the value the token replaced.
+	Detokenize(ctx context.Context, token string) (interface{}, error)
+}
+
+// WithTokenizer sets the Tokenizer of tokenize_properties. An error returned by the tokenizer
+// aborts Simplify.
+func WithTokenizer(tokenizer Tokenizer) Option {
+	return func(o *options) {
+		o.tokenizer = tokenizer
+	}
+}
+
+// tokenizeRuler replaces values with the token of the tokenizer. Values that cannot hold a
+// string are removed once the tokenizer recorded them.
+type tokenizeRuler struct {
+}
+
+var tokenizeRulerSingleton = &tokenizeRuler{}
+
+func (r *tokenizeRuler) apply(node *Node) error {
+	value := indirect(node.Value)
+	if !value.IsValid() || node.Parent.Kind() == reflect.Invalid || !value.CanInterface() {
+		return nil
+	}
+	if report := node.walk.report; report != nil && report.dryRun {
+		return nil
+	}
+	token, err := node.walk.root.options.tokenizer.Tokenize(node.walk.ctx, value.Interface())
+	if err != nil {
+		return fmt.Errorf("tokenize %s: %w", node.Path(), err)
+	}
+	if value.Kind() == reflect.String && value.CanSet() {
+		value.SetString(token)
+		return nil
+	}
+	replacement := reflect.ValueOf(token)
+	if node.Value.Kind() == reflect.String {
+		replacement = replacement.Convert(node.Value.Type())
+	}
+	if !node.set(replacement) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *tokenizeRuler) action() string {
+	return "tokenize"
+}
+
+// usesTokenization reports whether the rule or any of its nested or type rules has
+// tokenize_properties.
+func usesTokenization(rule *Rule) bool {
+	if rule == nil {
+		return false
+	}
+	if len(rule.TokenizeProperties) > 0 {
+		return true
+	}
+	for _, subRule := range rule.PropertySimplifiers {
+		if usesTokenization(subRule) {
+			return true
+		}
+	}
+	for _, typeRule := range rule.TypeSimplifiers {
+		if usesTokenization(typeRule) {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryTokenizer is a Tokenizer keeping the mapping in memory, for tests and development.
+// The same value is always replaced by the same token.
+type MemoryTokenizer struct {
+	mu       sync.Mutex
+	tokens   map[interface{}]string
+	values   map[string]interface{}
+	newToken func() (string, error)
+}
+
+// NewMemoryTokenizer creates an empty MemoryTokenizer.
+func NewMemoryTokenizer() *MemoryTokenizer {
+	return &MemoryTokenizer{
+		tokens: make(map[interface{}]string),
+		values: make(map[string]interface{}),
+		newToken: func() (string, error) {
+			token := make([]byte, 12)
+			if _, err := rand.Read(token); err != nil {
+				return "", err
+			}
+			return "tok_" + hex.EncodeToString(token), nil
+		},
+	}
+}
+
+// Tokenize returns the token of value, creating it on first use. Values that cannot be map
+// keys, such as slices, get a new token every time.
+func (t *MemoryTokenizer) Tokenize(_ context.Context, value interface{}) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	comparable := value == nil || reflect.TypeOf(value).Comparable()
+	if comparable {
+		if token, ok := t.tokens[value]; ok {
+			return token, nil
+		}
+	}
+	token, err := t.newToken()
+	if err != nil {
+		return "", err
+	}
+	if comparable {
+		t.tokens[value] = token
+	}
+	t.values[token] = value
+	return token, nil
+}
+
+// Detokenize returns the value of token.
+func (t *MemoryTokenizer) Detokenize(_ context.Context, token string) (interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	value, ok := t.values[token]
+	if !ok {
+		return nil, fmt.Errorf("unknown token %s", token)
+	}
+	return value, nil
+}
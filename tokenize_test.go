@@ -0,0 +1,124 @@
+package gosimplifier
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type TokenizeExample struct {
+	Name    string
+	SSN     string
+	Account *TokenizeAccount
+	Extra   map[string]interface{}
+}
+
+type TokenizeAccount struct {
+	Number int
+}
+
+func TestTokenizeProperties(t *testing.T) {
+	tokenizer := NewMemoryTokenizer()
+	simplifier, err := NewSimplifier(`{
+		"tokenize_properties": [ "SSN" ],
+		"property_simplifiers": {
+			"Account": { "tokenize_properties": [ "Number" ] },
+			"Extra": { "tokenize_properties": [ "card" ] }
+		}
+	}`, WithTokenizer(tokenizer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := TokenizeExample{
+		Name:    "n",
+		SSN:     "123-45-6789",
+		Account: &TokenizeAccount{Number: 42},
+		Extra:   map[string]interface{}{"card": 4111},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := simplified.(TokenizeExample)
+	if result.Name != "n" || !strings.HasPrefix(result.SSN, "tok_") {
+		t.Fatalf("Expected the SSN to be tokenized, got %+v", result)
+	}
+	if value, err := tokenizer.Detokenize(context.Background(), result.SSN); err != nil || value != "123-45-6789" {
+		t.Errorf("Expected the token to resolve to the SSN, got %v, %v", value, err)
+	}
+	if result.Account.Number != 0 {
+		t.Errorf("Expected a value that cannot hold the token to be removed, got %d", result.Account.Number)
+	}
+	token, ok := result.Extra["card"].(string)
+	if !ok {
+		t.Fatalf("Expected the map value to be replaced by its token, got %v", result.Extra["card"])
+	}
+	if value, _ := tokenizer.Detokenize(context.Background(), token); value != 4111 {
+		t.Errorf("Expected the token to resolve to the card, got %v", value)
+	}
+	if original.SSN != "123-45-6789" || original.Account.Number != 42 {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	again, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.(TokenizeExample).SSN != result.SSN {
+		t.Errorf("Expected the same value to get the same token")
+	}
+}
+
+func TestTokenizeRequiresTokenizer(t *testing.T) {
+	if _, err := NewSimplifier(`{ "tokenize_properties": [ "SSN" ] }`); err == nil {
+		t.Error("Expected an error without a tokenizer")
+	}
+	if _, err := NewSimplifier(`{ "type_simplifiers": { "pkg.T": { "tokenize_properties": [ "SSN" ] } } }`); err == nil {
+		t.Error("Expected an error without a tokenizer for a type rule")
+	}
+}
+
+func TestTokenizeError(t *testing.T) {
+	failure := errors.New("store unavailable")
+	simplifier, err := NewSimplifier(`{ "tokenize_properties": [ "SSN" ] }`, WithTokenizer(failingTokenizer{failure}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := simplifier.Simplify(TokenizeExample{SSN: "s"}); !errors.Is(err, failure) {
+		t.Errorf("Expected the error of the tokenizer, got %v", err)
+	}
+	if report, err := DryRun(simplifier, TokenizeExample{SSN: "s"}); err != nil || len(report.Changes) != 1 {
+		t.Errorf("Expected DryRun to report the change without tokenizing, got %+v, %v", report, err)
+	}
+}
+
+type failingTokenizer struct {
+	err error
+}
+
+func (t failingTokenizer) Tokenize(context.Context, interface{}) (string, error) {
+	return "", t.err
+}
+
+func (t failingTokenizer) Detokenize(context.Context, string) (interface{}, error) {
+	return nil, t.err
+}
+
+func TestTokenizeTag(t *testing.T) {
+	type tagged struct {
+		SSN string `simplify:"tokenize"`
+	}
+	simplifier, err := NewSimplifierFromType(reflect.TypeOf(tagged{}), WithTokenizer(NewMemoryTokenizer()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(tagged{SSN: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(simplified.(tagged).SSN, "tok_") {
+		t.Errorf("Expected the tagged field to be tokenized, got %+v", simplified)
+	}
+}
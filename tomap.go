@@ -0,0 +1,287 @@
+package gosimplifier
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// MapSimplifier is a Simplifier returning its results as maps, see SimplifyToMap.
+type MapSimplifier interface {
+	Simplifier
+	// SimplifyToMap simplifies a struct or map and returns it as a map in which the removed
+	// fields are absent rather than zeroed.
+	SimplifyToMap(original interface{}) (map[string]interface{}, error)
+}
+
+// SimplifyToMap simplifies a struct or map and returns it as a map in which the removed fields
+// are absent rather than zeroed, honoring json tags. Simplifiers that are not a MapSimplifier
+// are asked for the removed paths, see SimplifyWithAudit.
+func SimplifyToMap(s Simplifier, original interface{}) (map[string]interface{}, error) {
+	if ms, ok := s.(MapSimplifier); ok {
+		return ms.SimplifyToMap(original)
+	}
+	if err := checkMappable(original); err != nil {
+		return nil, err
+	}
+	simplified, removed, err := SimplifyWithAudit(s, original)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	return toMap(simplified, removed, nil), err
+}
+
+// SimplifyToMap simplifies a copy of original, a struct or a map, and returns it as a map in
+// which the removed struct fields and map entries are absent rather than zeroed, so the JSON
+// form of the result carries no misleading empty values. Struct fields are named and omitted
+// as their json tags say, and the fields of embedded structs are inlined, as with
+// encoding/json. Nested structs become maps as well, slices become []interface{}, and values
+// marshaling themselves, such as time.Time, and leaf types, see RegisterLeafType, are kept as
+// they are. With WithDropEmpty, the empty values are left out as well, and the properties of
+// rules with a flatten directive are lifted into their parents, see FlattenRule. Protobuf
+// messages are not supported, see DryRun.
+func (s *simplifierImpl) SimplifyToMap(original interface{}) (map[string]interface{}, error) {
+	if err := checkMappable(original); err != nil {
+		return nil, err
+	}
+	simplified, removed, err := s.SimplifyWithAudit(original)
+	if err != nil && !isPartial(err) {
+		return nil, err
+	}
+	return toMap(simplified, removed, s), err
+}
+
+func (s *simplifierImpl) mapRoot() *simplifierImpl {
+	return s
+}
+
+// WithDropEmpty leaves the empty values out of the maps of SimplifyToMap, so the result has no
+// skeletons of what the rules removed: nil pointers and interfaces, zero structs, and empty
+// slices and maps, including those only left empty by leaving out their own empty values.
+// Zero scalars, such as 0 or "", are kept, as are the elements of lists, which keep their
+// positions.
+func WithDropEmpty() Option {
+	return func(o *options) {
+		o.dropEmpty = true
+	}
+}
+
+// checkMappable returns an error if SimplifyToMap cannot convert original to a map.
+func checkMappable(original interface{}) error {
+	if _, ok := original.(proto.Message); ok {
+		return fmt.Errorf("SimplifyToMap does not support protobuf messages, got %T", original)
+	}
+	root := indirect(reflect.ValueOf(original))
+	if root.Kind() != reflect.Struct && root.Kind() != reflect.Map {
+		return fmt.Errorf("SimplifyToMap requires a struct or a map, got %T", original)
+	}
+	return nil
+}
+
+// toMap converts the simplified value to a map, leaving out the removed paths, named as the
+// options of root name the fields and flattened as its rules say. root may be nil.
+func toMap(simplified interface{}, removed []string, root *simplifierImpl) map[string]interface{} {
+	m := &mapper{root: root, removed: make(map[string]bool, len(removed))}
+	if root != nil {
+		m.options, m.dropEmpty = root.options, root.options.dropEmpty
+	}
+	for _, path := range removed {
+		m.removed[path] = true
+	}
+	result, _ := m.value(reflect.ValueOf(simplified), "", root).(map[string]interface{})
+	if result == nil {
+		result = make(map[string]interface{})
+	}
+	return result
+}
+
+// mapper converts a simplified value to maps, leaving out the removed paths.
+type mapper struct {
+	// root is the simplifier whose rules are followed to flatten properties, nil for none
+	root      *simplifierImpl
+	options   *options
+	removed   map[string]bool
+	dropEmpty bool
+}
+
+// value converts the value at path, to which rules apply, nil if they are unknown.
+func (m *mapper) value(value reflect.Value, path string, rules *simplifierImpl) interface{} {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		return nil
+	}
+	if isLeafType(value.Type()) || value.Type().Implements(jsonMarshalerType) || value.Type().Implements(textMarshalerType) ||
+		reflect.PointerTo(value.Type()).Implements(jsonMarshalerType) || reflect.PointerTo(value.Type()).Implements(textMarshalerType) {
+		return value.Interface()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		object := make(map[string]interface{})
+		var lifted []liftedProperty
+		m.fields(object, value, path, rules, &lifted)
+		lift(object, lifted)
+		return object
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		object := make(map[string]interface{}, value.Len())
+		var lifted []liftedProperty
+		iter := value.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			childPath := joinRulePath(path, key)
+			if m.removed[childPath] {
+				continue
+			}
+			var childRules *simplifierImpl
+			if rules != nil {
+				_, r := rules.keyRuler(key)
+				childRules = m.childRules(r)
+			}
+			m.add(object, &lifted, key, iter.Value(), m.value(iter.Value(), childPath, childRules), childRules)
+		}
+		lift(object, lifted)
+		return object
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		list := make([]interface{}, value.Len())
+		for i := range list {
+			list[i] = m.value(value.Index(i), path+"["+strconv.Itoa(i)+"]", rules)
+		}
+		return list
+	}
+	if value.CanInterface() {
+		return value.Interface()
+	}
+	return nil
+}
+
+// fields adds the fields of the struct value to object, inlining the fields of embedded structs
+// without a json name, and the fields to lift to lifted.
+func (m *mapper) fields(object map[string]interface{}, value reflect.Value, path string, rules *simplifierImpl, lifted *[]liftedProperty) {
+	valueType := value.Type()
+	fieldNames := m.options.fieldNames(valueType)
+	for i := 0; i < value.NumField(); i++ {
+		structField := valueType.Field(i)
+		if !structField.IsExported() && !structField.Anonymous {
+			continue
+		}
+		tag := structField.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, tagOptions, _ := strings.Cut(tag, ",")
+		childPath := joinRulePath(path, fieldNames[i])
+		if m.removed[childPath] {
+			continue
+		}
+		field := value.Field(i)
+		if name == "" && isEmbeddedStruct(structField) {
+			if embedded := indirect(field); embedded.IsValid() {
+				m.fields(object, embedded, childPath, rules, lifted)
+			}
+			continue
+		}
+		if !structField.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = structField.Name
+		}
+		if strings.Contains(","+tagOptions+",", ",omitempty,") && isEmptyJSONValue(field) {
+			continue
+		}
+		var childRules *simplifierImpl
+		if rules != nil {
+			_, r := rules.propertyRuler(fieldNames[i])
+			childRules = m.childRules(r)
+		}
+		m.add(object, lifted, name, field, m.value(field, childPath, childRules), childRules)
+	}
+}
+
+// add adds the property name, converted from value to converted, to object, or to lifted if its
+// rules flatten it.
+func (m *mapper) add(object map[string]interface{}, lifted *[]liftedProperty, name string, value reflect.Value, converted interface{}, rules *simplifierImpl) {
+	if m.dropEmpty && isEmptyValue(value, converted) {
+		return
+	}
+	if rules != nil && rules.rule.Flatten != nil {
+		if properties, ok := converted.(map[string]interface{}); ok {
+			*lifted = append(*lifted, liftedProperty{name: name, prefix: rules.rule.Flatten.Prefix, properties: properties})
+			return
+		}
+	}
+	object[name] = converted
+}
+
+// childRules returns the rules of a property matched by r, as the walk applies them: its own
+// rules, the root rules if no rule names it, or nil if r does not descend into it.
+func (m *mapper) childRules(r ruler) *simplifierImpl {
+	if r == nil {
+		if m.root.fallback != nil {
+			return m.root.fallback
+		}
+		return m.root
+	}
+	sub, _ := r.(*simplifierImpl)
+	return sub
+}
+
+// isEmptyValue reports whether WithDropEmpty leaves out the value, converted to converted.
+func isEmptyValue(value reflect.Value, converted interface{}) bool {
+	if converted == nil {
+		return true
+	}
+	switch value = indirect(value); value.Kind() {
+	case reflect.Struct:
+		if value.IsZero() {
+			return true
+		}
+	case reflect.Slice, reflect.Map:
+		if value.Len() == 0 {
+			return true
+		}
+	}
+	switch converted := converted.(type) {
+	case map[string]interface{}:
+		return len(converted) == 0
+	case []interface{}:
+		return len(converted) == 0
+	}
+	return false
+}
+
+// isEmptyJSONValue reports whether encoding/json considers the value empty for omitempty.
+func isEmptyJSONValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Ptr:
+		return value.IsZero()
+	}
+	return false
+}
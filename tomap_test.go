@@ -0,0 +1,128 @@
+package gosimplifier
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ToMapBase struct {
+	ID int `json:"id"`
+}
+
+type ToMapExample struct {
+	ToMapBase
+	Name     string            `json:"name"`
+	Count    int               `json:"count"`
+	Note     string            `json:"note,omitempty"`
+	Internal string            `json:"-"`
+	Created  time.Time         `json:"created"`
+	Items    []ToMapItem       `json:"items"`
+	Labels   map[string]string `json:"labels"`
+	Plain    string
+}
+
+type ToMapItem struct {
+	Title string `json:"title"`
+	Price int    `json:"price"`
+}
+
+func TestSimplifyToMap(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Count", "ID" ],
+		"property_simplifiers": {
+			"Items": { "remove_properties": [ "Price" ] },
+			"Labels": { "remove_properties": [ "owner" ] }
+		}
+	}`, WithRemovalMode(RemoveZero))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	result, err := SimplifyToMap(simplifier, &ToMapExample{
+		ToMapBase: ToMapBase{ID: 1},
+		Name:      "n",
+		Count:     3,
+		Internal:  "i",
+		Created:   created,
+		Items:     []ToMapItem{{Title: "a", Price: 5}},
+		Labels:    map[string]string{"env": "prod", "owner": "me"},
+		Plain:     "p",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"name":    "n",
+		"created": created,
+		"items":   []interface{}{map[string]interface{}{"title": "a"}},
+		"labels":  map[string]interface{}{"env": "prod"},
+		"Plain":   "p",
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Plain":"p","created":"2024-01-02T03:04:05Z","items":[{"title":"a"}],"labels":{"env":"prod"},"name":"n"}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	if _, err := SimplifyToMap(simplifier, []int{1}); err == nil {
+		t.Error("Expected an error for a slice")
+	}
+}
+
+func TestSimplifyToMapJSONFieldNames(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "remove_properties": [ "id", "name" ] }`, WithJSONFieldNames())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := SimplifyToMap(simplifier, ToMapExample{ToMapBase: ToMapBase{ID: 1}, Name: "n", Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := result["name"]; ok || result["count"] != 2 {
+		t.Errorf("Expected name to be omitted and count to be kept, got %v", result)
+	}
+	if _, ok := result["id"]; ok {
+		t.Errorf("Expected the promoted id to be omitted, got %v", result)
+	}
+}
+
+func TestSimplifyToMapDropEmpty(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"labels": { "remove_properties": [ "owner" ] },
+			"extra": { "remove_properties": [ "debug" ] }
+		}
+	}`, WithDropEmpty())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := SimplifyToMap(simplifier, map[string]interface{}{
+		"name":    "n",
+		"count":   0,
+		"created": time.Time{},
+		"items":   []ToMapItem{},
+		"item":    ToMapItem{},
+		"ptr":     (*ToMapItem)(nil),
+		"labels":  map[string]string{"owner": "me"},
+		"extra":   map[string]interface{}{"debug": "d", "nested": map[string]interface{}{}},
+		"list":    []interface{}{map[string]interface{}{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"name":  "n",
+		"count": 0,
+		"list":  []interface{}{map[string]interface{}{}},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,84 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	transformersMu sync.RWMutex
+	transformers   = make(map[string]func(reflect.Value) reflect.Value)
+)
+
+// RegisterTransformer makes fn available to transform_properties rules under name, so any
+// per-property logic, e.g. tokenizing, rounding or anonymizing, can be plugged into the rules:
+//
+//	gosimplifier.RegisterTransformer("round", func(v reflect.Value) reflect.Value {
+//		return reflect.ValueOf(math.Round(v.Float()))
+//	})
+//
+//	{ "transform_properties": { "Latitude": "round", "Longitude": "round" } }
+//
+// fn receives the value of the property in the copy being simplified and returns its
+// replacement, which must be assignable or convertible to the type of the property; an invalid
+// reflect.Value, or one that does not fit, removes the property. Transformers are looked up
+// when the simplifier is created. RegisterTransformer panics if fn is nil or name is already
+// registered, and is meant to be called from init functions.
+func RegisterTransformer(name string, fn func(reflect.Value) reflect.Value) {
+	transformersMu.Lock()
+	defer transformersMu.Unlock()
+	if fn == nil {
+		panic("gosimplifier: RegisterTransformer " + name + " with a nil function")
+	}
+	if _, ok := transformers[name]; ok {
+		panic("gosimplifier: RegisterTransformer called twice for " + name)
+	}
+	transformers[name] = fn
+}
+
+func lookupTransformer(name string) (func(reflect.Value) reflect.Value, bool) {
+	transformersMu.RLock()
+	defer transformersMu.RUnlock()
+	fn, ok := transformers[name]
+	return fn, ok
+}
+
+// transformRuler replaces a value with the result of a registered transformer.
+type transformRuler struct {
+	name string
+	fn   func(reflect.Value) reflect.Value
+}
+
+func newTransformRuler(propName string, name string) (*transformRuler, error) {
+	fn, ok := lookupTransformer(name)
+	if !ok {
+		return nil, fmt.Errorf("transform_properties %q: unknown transformer %q, see RegisterTransformer", propName, name)
+	}
+	return &transformRuler{name: name, fn: fn}, nil
+}
+
+func (r *transformRuler) apply(node *Node) error {
+	if !node.Value.IsValid() || node.Parent.Kind() == reflect.Invalid {
+		return nil
+	}
+	transformed := r.fn(node.Value)
+	if !transformed.IsValid() {
+		return removeRulerSingleton.apply(node)
+	}
+	slotType := node.Value.Type()
+	if node.Parent.Kind() == reflect.Map {
+		slotType = node.Parent.Type().Elem()
+	}
+	if !transformed.Type().AssignableTo(slotType) && transformed.Type().ConvertibleTo(slotType) {
+		transformed = transformed.Convert(slotType)
+	}
+	if !node.set(transformed) {
+		return removeRulerSingleton.apply(node)
+	}
+	return nil
+}
+
+func (r *transformRuler) action() string {
+	return "transform"
+}
@@ -0,0 +1,64 @@
+package gosimplifier
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	Label     string
+}
+
+func init() {
+	RegisterTransformer("test_round", func(v reflect.Value) reflect.Value {
+		return reflect.ValueOf(math.Round(reflect.Indirect(v).Float()))
+	})
+	RegisterTransformer("test_upper", func(v reflect.Value) reflect.Value {
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		return reflect.ValueOf(strings.ToUpper(v.String()))
+	})
+	RegisterTransformer("test_drop", func(v reflect.Value) reflect.Value {
+		return reflect.Value{}
+	})
+}
+
+func TestTransformProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "transform_properties": { "Latitude": "test_round", "Label": "test_upper", "Longitude": "test_drop" } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(Location{Latitude: 52.52, Longitude: 13.4, Label: "berlin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Location{Latitude: 53, Label: "BERLIN"}); simplified != expected {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+
+	simplified, _ = simplifier.Simplify(map[string]interface{}{"Label": "berlin"})
+	if expected := map[string]interface{}{"Label": "BERLIN"}; !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %v, got %v", expected, simplified)
+	}
+}
+
+func TestTransformPropertiesUnknown(t *testing.T) {
+	if _, err := NewSimplifier(`{ "transform_properties": { "Label": "missing" } }`); err == nil {
+		t.Error("Expected an unknown transformer to fail")
+	}
+}
+
+func TestRegisterTransformerTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering a name twice to panic")
+		}
+	}()
+	RegisterTransformer("test_round", func(v reflect.Value) reflect.Value { return v })
+}
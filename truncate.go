@@ -0,0 +1,114 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"unicode/utf8"
+)
+
+// TruncatedSuffix marks the end of a value shortened by truncate_properties.
+const TruncatedSuffix = "…"
+
+// truncateRuler shortens string and []byte values to at most max bytes.
+type truncateRuler struct {
+	max int
+}
+
+// truncate returns value shortened to at most max bytes, ending with TruncatedSuffix if it was
+// shortened. Strings are cut at a rune boundary so they stay valid UTF-8.
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	suffix := TruncatedSuffix
+	if max <= len(suffix) {
+		suffix = ""
+	}
+	cut := max - len(suffix)
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + suffix
+}
+
+// apply truncates the node. Values of other kinds are left as they are.
+func (r *truncateRuler) apply(node *Node) error {
+	value := indirect(node.Value)
+	if !value.IsValid() || node.Parent.Kind() == reflect.Invalid {
+		return nil
+	}
+	switch {
+	case value.Kind() == reflect.String:
+		if value.Len() <= r.max {
+			return nil
+		}
+		truncated := truncate(value.String(), r.max)
+		if value.CanSet() {
+			value.SetString(truncated)
+			return nil
+		}
+		replacement := reflect.ValueOf(truncated)
+		if node.Value.Kind() == reflect.String {
+			replacement = replacement.Convert(node.Value.Type())
+		}
+		node.set(replacement)
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		if value.Len() <= r.max {
+			return nil
+		}
+		if value.CanSet() {
+			value.SetLen(r.max)
+			return nil
+		}
+		node.set(value.Slice(0, r.max))
+	}
+	return nil
+}
+
+func (r *truncateRuler) action() string {
+	return "truncate"
+}
+
+// canTruncate reports whether values of type t are shortened by truncate_properties.
+func canTruncate(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String || t.Kind() == reflect.Interface ||
+		t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// WithMaxStringLength shortens every string value longer than n bytes like truncate_properties
+// does, as a safety net against huge strings reaching log sinks, whatever the name of the
+// property holding them. The values of the exempt properties, and everything below them, are
+// left whole, as are the values the rules act on, e.g. hashed ones. Every value is visited, so
+// the copy of Simplify shares nothing with the original. Map keys and the fields of protobuf
+// messages are not shortened. A negative n leaves every string whole.
+func WithMaxStringLength(n int, exempt ...string) Option {
+	return func(o *options) {
+		if n < 0 {
+			return
+		}
+		o.middlewares = append(o.middlewares, maxStringLengthMiddleware(n, exempt))
+	}
+}
+
+func maxStringLengthMiddleware(max int, exempt []string) Middleware {
+	truncater := &truncateRuler{max: max}
+	return func(next Walker) Walker {
+		return func(node *Node) error {
+			if err := next(node); err != nil || node.Action() != "" {
+				return err
+			}
+			if value := indirect(node.Value); !value.IsValid() || value.Kind() != reflect.String || value.Len() <= max {
+				return nil
+			}
+			options := node.walk.root.options
+			for n := node; n != nil; n = n.parent {
+				if n.name != "" && options.containsName(exempt, n.name) {
+					return nil
+				}
+			}
+			return truncater.apply(node)
+		}
+	}
+}
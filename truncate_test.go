@@ -0,0 +1,94 @@
+package gosimplifier
+
+import (
+	"strings"
+	"testing"
+)
+
+type CrashReport struct {
+	Message    string
+	StackTrace string
+	Body       []byte
+	Code       int
+}
+
+func TestTruncateProperties(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "truncate_properties": { "StackTrace": 10, "Body": 4, "Code": 1, "html": 8 } }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := CrashReport{Message: "boom", StackTrace: strings.Repeat("frame\n", 100), Body: []byte("<html>"), Code: 500}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := simplified.(CrashReport)
+	if report.StackTrace != "frame\nf"+TruncatedSuffix || string(report.Body) != "<htm" || report.Code != 500 || report.Message != "boom" {
+		t.Errorf("Unexpected result %+v", report)
+	}
+	if len(original.StackTrace) != 600 || string(original.Body) != "<html>" {
+		t.Error("Expected the original to be unchanged")
+	}
+
+	simplified, _ = simplifier.Simplify(map[string]interface{}{"html": "<p>日本語</p>"})
+	if html := simplified.(map[string]interface{})["html"]; html != "<p>"+TruncatedSuffix {
+		t.Errorf("Expected the value to be cut at a rune boundary, got %q", html)
+	}
+
+	if _, err := NewSimplifier(`{ "truncate_properties": { "StackTrace": -1 } }`); err == nil {
+		t.Error("Expected a negative length to fail")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for _, c := range []struct {
+		value    string
+		max      int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"abcdef", 2, "ab"},
+		{"日本", 4, TruncatedSuffix},
+		{"日本", 6, "日本"},
+		{"abcdef", 0, ""},
+	} {
+		if truncated := truncate(c.value, c.max); truncated != c.expected || len(truncated) > c.max && c.max < len(c.value) {
+			t.Errorf("truncate(%q, %d): expected %q, got %q", c.value, c.max, c.expected, truncated)
+		}
+	}
+}
+
+func TestWithMaxStringLength(t *testing.T) {
+	simplifier, err := NewSimplifier(`{ "hash_properties": [ "Message" ] }`, WithMaxStringLength(8, "Attachments"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	long := strings.Repeat("x", 20)
+	original := map[string]interface{}{
+		"Report":      CrashReport{Message: long, StackTrace: long},
+		"Lines":       []string{"short", long},
+		"Attachments": map[string]interface{}{"log": long},
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := simplified.(map[string]interface{})
+	capped := strings.Repeat("x", 8-len(TruncatedSuffix)) + TruncatedSuffix
+	report := result["Report"].(CrashReport)
+	if report.StackTrace != capped || report.Message == capped {
+		t.Errorf("Expected the stack trace to be capped and the hash to be left whole, got %+v", report)
+	}
+	if lines := result["Lines"].([]string); lines[0] != "short" || lines[1] != capped {
+		t.Errorf("Expected the long line to be capped, got %q", lines)
+	}
+	if log := result["Attachments"].(map[string]interface{})["log"]; log != long {
+		t.Errorf("Expected the exempt property to be left whole, got %q", log)
+	}
+	if original["Lines"].([]string)[1] != long {
+		t.Error("Expected the original to be unchanged")
+	}
+}
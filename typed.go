@@ -0,0 +1,35 @@
+package gosimplifier
+
+// TypedSimplifier is a Simplifier for values of type T, returning T without a type assertion.
+type TypedSimplifier[T any] interface {
+	// Simplify returns a simplified copy of original, see Simplifier. The partial result of a
+	// WithBestEffort simplifier is returned along with its *PartialError.
+	Simplify(original T) (T, error)
+}
+
+type typedSimplifier[T any] struct {
+	simplifier Simplifier
+}
+
+// NewTypedSimplifier creates a TypedSimplifier for T with the given rules, see NewSimplifier.
+func NewTypedSimplifier[T any](rulesJson string, opts ...Option) (TypedSimplifier[T], error) {
+	s, err := NewSimplifier(rulesJson, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return Typed[T](s), nil
+}
+
+// Typed adapts a Simplifier to a TypedSimplifier for T.
+func Typed[T any](s Simplifier) TypedSimplifier[T] {
+	return &typedSimplifier[T]{simplifier: s}
+}
+
+func (t *typedSimplifier[T]) Simplify(original T) (T, error) {
+	var zero T
+	simplified, err := t.simplifier.Simplify(original)
+	if err != nil && !isPartial(err) || simplified == nil {
+		return zero, err
+	}
+	return simplified.(T), err
+}
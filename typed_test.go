@@ -0,0 +1,64 @@
+package gosimplifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypedSimplifier(t *testing.T) {
+	simplifier, err := NewTypedSimplifier[*ExampleStruct2](`{ "remove_properties": [ "Name" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simplified, err := simplifier.Simplify(&ExampleStruct2{Name: "John Doe", Age: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.Name != "" || simplified.Age != 30 {
+		t.Errorf("Unexpected result %+v", simplified)
+	}
+
+	nilResult, err := simplifier.Simplify(nil)
+	if err != nil || nilResult != nil {
+		t.Errorf("Expected nil for a nil input, got %v, %v", nilResult, err)
+	}
+}
+
+func TestTypedSimplifierInvalidJson(t *testing.T) {
+	if _, err := NewTypedSimplifier[ExampleStruct](`{ invalid }`); err == nil {
+		t.Error("Expected error, but got none")
+	}
+}
+
+func TestTyped(t *testing.T) {
+	base, _ := NewSimplifier(`{ "remove_properties": [ "Debug" ] }`)
+
+	simplified, err := Typed[ExampleStruct](base).Simplify(ExampleStruct{Test: 1, Debug: "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.Debug != "" || simplified.Test != 1 {
+		t.Errorf("Unexpected result %+v", simplified)
+	}
+}
+
+func TestTypedPartialResult(t *testing.T) {
+	failDebug := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Name() == "Debug" {
+				return errors.New("failed")
+			}
+			return next(node)
+		}
+	}
+	base, _ := NewSimplifier(`{}`, WithMiddleware(failDebug), WithBestEffort())
+
+	simplified, err := Typed[ExampleStruct](base).Simplify(ExampleStruct{Test: 1, Debug: "debug"})
+	if !isPartial(err) {
+		t.Fatalf("Expected a PartialError, got %v", err)
+	}
+	if simplified.Test != 1 {
+		t.Errorf("Expected the partial result, got %+v", simplified)
+	}
+}
@@ -0,0 +1,118 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// typeRules holds the type_simplifiers of the root rule, which apply to the values of a type
+// wherever they are in the graph.
+type typeRules struct {
+	// simplifiers are the compiled type rules by type name
+	simplifiers map[string]*simplifierImpl
+	// merged caches the rules applied to a value of a type in place of given rules, by typedKey
+	merged sync.Map
+	// byType caches the type rule of a type, nil if there is none
+	byType sync.Map
+}
+
+type typedKey struct {
+	rules *simplifierImpl
+	t     reflect.Type
+}
+
+// newTypeRules compiles the type_simplifiers of the root rule, or returns nil if it has none.
+// Type rules are not allowed below the root.
+func newTypeRules(rule *Rule) (*typeRules, error) {
+	for name, sub := range rule.PropertySimplifiers {
+		if hasTypeRules(sub) {
+			return nil, fmt.Errorf("%s: type_simplifiers are only supported in the root rule", name)
+		}
+	}
+	if len(rule.TypeSimplifiers) == 0 {
+		return nil, nil
+	}
+	tr := &typeRules{simplifiers: make(map[string]*simplifierImpl, len(rule.TypeSimplifiers))}
+	for name, typeRule := range rule.TypeSimplifiers {
+		if typeRule == nil {
+			typeRule = &Rule{}
+		}
+		if hasTypeRules(typeRule) {
+			return nil, fmt.Errorf("type_simplifiers %s: type_simplifiers are only supported in the root rule", name)
+		}
+		s, err := newSimplifierByRule0(typeRule, make(map[string]*simplifierImpl))
+		if err != nil {
+			return nil, fmt.Errorf("type_simplifiers %s: %w", name, err)
+		}
+		tr.simplifiers[name] = s
+	}
+	return tr, nil
+}
+
+// hasTypeRules reports whether the rule or one of its sub-rules has type_simplifiers.
+func hasTypeRules(rule *Rule) bool {
+	if rule == nil {
+		return false
+	}
+	if len(rule.TypeSimplifiers) > 0 {
+		return true
+	}
+	for _, sub := range rule.PropertySimplifiers {
+		if hasTypeRules(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeRule returns the type rule of t, named either by its package name and type name, e.g.
+// "mypkg.User", or by its full import path, e.g. "github.com/acme/mypkg.User".
+func (tr *typeRules) typeRule(t reflect.Type) *simplifierImpl {
+	if cached, ok := tr.byType.Load(t); ok {
+		return cached.(*simplifierImpl)
+	}
+	s := tr.simplifiers[t.String()]
+	if s == nil && t.PkgPath() != "" {
+		s = tr.simplifiers[t.PkgPath()+"."+t.Name()]
+	}
+	tr.byType.Store(t, s)
+	return s
+}
+
+// forType returns the rules applied to the value of the node: the rules of s merged onto the
+// type rule of the value's type, so the rules of the path override those of the type, or s
+// itself if the type has no rule.
+func (s *simplifierImpl) forType(node *Node) *simplifierImpl {
+	root := node.walk.root
+	tr := root.typeRules
+	if tr == nil {
+		return s
+	}
+	value := indirect(node.Value)
+	if !value.IsValid() {
+		return s
+	}
+	typeRule := tr.typeRule(value.Type())
+	if typeRule == nil {
+		return s
+	}
+	key := typedKey{rules: s, t: value.Type()}
+	if merged, ok := tr.merged.Load(key); ok {
+		return merged.(*simplifierImpl)
+	}
+	rule := mergeRules(typeRule.rule, s.rule)
+	rule.TypeSimplifiers = nil
+	merged, err := newSimplifierByRule0(rule, make(map[string]*simplifierImpl))
+	if err != nil {
+		// both rules compiled on their own, so their merge does too
+		panic(fmt.Sprintf("type_simplifiers %s: %v", value.Type(), err))
+	}
+	if root.options.caseInsensitive {
+		merged.foldNames(make(map[*simplifierImpl]bool))
+	}
+	// the merged rules must not be merged again for values of the same type
+	tr.merged.Store(typedKey{rules: merged, t: value.Type()}, merged)
+	actual, _ := tr.merged.LoadOrStore(key, merged)
+	return actual.(*simplifierImpl)
+}
@@ -0,0 +1,104 @@
+package gosimplifier
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TypeRuleUser struct {
+	Name     string
+	Password string
+	Email    string
+}
+
+type TypeRuleOrder struct {
+	Buyer   *TypeRuleUser
+	Seller  TypeRuleUser
+	Viewers []TypeRuleUser
+	ByID    map[string]TypeRuleUser
+	Note    string
+}
+
+func TestTypeSimplifiers(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"type_simplifiers": {
+			"gosimplifier.TypeRuleUser": { "remove_properties": [ "Password" ] }
+		},
+		"property_simplifiers": {
+			"Seller": { "mask_properties": [ "Email" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := TypeRuleUser{Name: "n", Password: "p", Email: "e"}
+	original := TypeRuleOrder{
+		Buyer:   &TypeRuleUser{Name: "n", Password: "p", Email: "e"},
+		Seller:  user,
+		Viewers: []TypeRuleUser{user},
+		ByID:    map[string]TypeRuleUser{"1": user},
+		Note:    "note",
+	}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trimmed := TypeRuleUser{Name: "n", Email: "e"}
+	expected := TypeRuleOrder{
+		Buyer:   &TypeRuleUser{Name: "n", Email: "e"},
+		Seller:  TypeRuleUser{Name: "n", Email: DefaultMask},
+		Viewers: []TypeRuleUser{trimmed},
+		ByID:    map[string]TypeRuleUser{"1": trimmed},
+		Note:    "note",
+	}
+	if !reflect.DeepEqual(simplified, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, simplified)
+	}
+	if original.Buyer.Password != "p" || original.Viewers[0].Password != "p" {
+		t.Errorf("Expected the original to be left unchanged, got %+v", original)
+	}
+
+	report, err := DryRun(simplifier, &user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed := report.Removed(); !reflect.DeepEqual(removed, []string{"Password"}) {
+		t.Errorf("Expected the type rule to apply to the root, got %v", removed)
+	}
+}
+
+func TestTypeSimplifiersPathOverrides(t *testing.T) {
+	simplifier, err := NewSimplifier(`{
+		"type_simplifiers": {
+			"github.com/xhinliang/gosimplifier.TypeRuleUser": { "mask_properties": [ "Email" ] }
+		},
+		"property_simplifiers": {
+			"Seller": { "remove_properties": [ "Email" ] }
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	simplified, err := simplifier.Simplify(TypeRuleOrder{
+		Buyer:  &TypeRuleUser{Email: "e"},
+		Seller: TypeRuleUser{Email: "e"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := simplified.(TypeRuleOrder)
+	if result.Buyer.Email != DefaultMask || result.Seller.Email != "" {
+		t.Errorf("Expected the path rule to override the type rule, got %+v %+v", result.Buyer, result.Seller)
+	}
+}
+
+func TestTypeSimplifiersRootOnly(t *testing.T) {
+	_, err := NewSimplifier(`{
+		"property_simplifiers": {
+			"Seller": { "type_simplifiers": { "gosimplifier.TypeRuleUser": {} } }
+		}
+	}`)
+	if err == nil {
+		t.Error("Expected an error for nested type_simplifiers")
+	}
+}
@@ -0,0 +1,182 @@
+package gosimplifier
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationError lists the problems ValidateForType found in the rules.
+type ValidationError struct {
+	Type     reflect.Type
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("rules do not fit %s: %s", e.Type, strings.Join(e.Problems, "; "))
+}
+
+// TypeValidator is a Simplifier checking its rules against a type, see ValidateForType.
+type TypeValidator interface {
+	Simplifier
+	// ValidateForType checks the rules against the type of the values they will be applied to.
+	ValidateForType(t reflect.Type) error
+}
+
+// ValidateForType checks the rules of s against the type of the values they will be applied
+// to, so misconfigured rules can be caught at startup, see ValidationError. It fails for
+// simplifiers that are not a TypeValidator.
+func ValidateForType(s Simplifier, t reflect.Type) error {
+	if v, ok := s.(TypeValidator); ok {
+		return v.ValidateForType(t)
+	}
+	return fmt.Errorf("ValidateForType is not supported by %T", s)
+}
+
+// ValidateForType walks the rule tree against t and returns a *ValidationError describing
+// properties unknown to the structs they are applied to, rules applied to scalar values and
+// sub-rules that can never be reached. Values whose shape is only known at runtime, such as
+// interface{} values, maps with scalar values and key-value lists, are not looked into.
+func (s *simplifierImpl) ValidateForType(t reflect.Type) error {
+	warnings := s.shadowWarnings("")
+	s.validateForType(t, "", s.options, &warnings)
+	if len(warnings) == 0 {
+		return nil
+	}
+	problems := make([]string, len(warnings))
+	for i, w := range warnings {
+		problems[i] = displayPath(w.Path) + ": " + w.Message
+	}
+	return &ValidationError{Type: t, Problems: problems}
+}
+
+// validateForType reports the rules of s, located at path in the rule tree, that do not fit t.
+func (s *simplifierImpl) validateForType(t reflect.Type, path string, o *options, warnings *[]Warning) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Slice, reflect.Array:
+		if s.rule.KeyValue == nil {
+			for _, rule := range s.indexRules {
+				validateRuler(rule.ruler, t.Elem(), joinRulePath(path, rule.selector), o, warnings)
+			}
+			s.validateForType(t.Elem(), path, o, warnings)
+		}
+	case reflect.Struct:
+		if s.removeIf != nil {
+			for _, propName := range append([]string{s.removeIf.rule.Field}, s.removeIf.rule.Properties...) {
+				if _, ok := o.fieldByName(t, propName); !ok {
+					*warnings = append(*warnings, Warning{path, fmt.Sprintf("remove_if names unknown property %s of %s", propName, t)})
+				}
+			}
+		}
+		for _, propName := range s.rule.KeepProperties {
+			if _, ok := o.fieldByName(t, propName); !ok && !o.globstarNames[propName] {
+				*warnings = append(*warnings, Warning{joinRulePath(path, propName),
+					fmt.Sprintf("keep_properties names unknown property of %s", t)})
+			}
+		}
+		for _, propName := range s.rule.RemoveIfZero {
+			if _, ok := o.fieldByName(t, propName); !ok && !o.globstarNames[propName] {
+				*warnings = append(*warnings, Warning{joinRulePath(path, propName),
+					fmt.Sprintf("remove_if_zero names unknown property of %s", t)})
+			}
+		}
+		for _, propName := range s.rule.Except {
+			if _, ok := o.fieldByName(t, propName); !ok && !o.globstarNames[propName] {
+				*warnings = append(*warnings, Warning{joinRulePath(path, propName),
+					fmt.Sprintf("except names unknown property of %s", t)})
+			}
+		}
+		for _, propName := range sortedKeys(s.rule.RenameProperties) {
+			*warnings = append(*warnings, Warning{joinRulePath(path, propName),
+				fmt.Sprintf("rename_properties entry has no effect on the fields of %s", t)})
+		}
+		for _, propName := range sortedKeys(s.propertySimplifiers) {
+			if isIndexSelector(propName) {
+				continue
+			}
+			propPath := joinRulePath(path, propName)
+			if propName == "*" && s.removesAll() {
+				continue
+			}
+			if isGlob(propName) {
+				*warnings = append(*warnings, Warning{propPath, fmt.Sprintf("glob patterns only match map keys, not the fields of %s", t)})
+				continue
+			}
+			field, ok := o.fieldByName(t, propName)
+			if !ok && o.globstarNames[propName] {
+				// globstar names are pushed into every rule, whatever the struct it applies to
+				continue
+			}
+			if !ok {
+				*warnings = append(*warnings, Warning{propPath, fmt.Sprintf("unknown property of %s", t)})
+				continue
+			}
+			validateRuler(s.propertySimplifiers[propName], field.Type, propPath, o, warnings)
+		}
+	case reflect.Map:
+		for _, propName := range sortedKeys(s.propertySimplifiers) {
+			if sub, ok := s.propertySimplifiers[propName].(*simplifierImpl); ok && !isIndexSelector(propName) {
+				sub.validateForType(t.Elem(), joinRulePath(path, propName), o, warnings)
+			}
+		}
+	default:
+		if len(s.propertySimplifiers) > len(s.indexRules) {
+			*warnings = append(*warnings, Warning{path, fmt.Sprintf("rules applied to a value of scalar type %s", t)})
+		}
+	}
+}
+
+// validateRuler reports the problems of applying r, located at path, to values of type t.
+func validateRuler(r ruler, t reflect.Type, path string, o *options, warnings *[]Warning) {
+	switch r := r.(type) {
+	case *simplifierImpl:
+		r.validateForType(t, path, o, warnings)
+	case *maskRuler, *injectRuler, *hashRuler:
+		if !canHoldString(t) {
+			*warnings = append(*warnings, Warning{path,
+				fmt.Sprintf("the %s action on a property of type %s removes it instead", r.action(), t)})
+		}
+	case *replaceRuler:
+		if _, err := r.replacement(t); err != nil {
+			*warnings = append(*warnings, Warning{path,
+				fmt.Sprintf("replacement %s does not fit %s, the property is removed instead", r.literal, t)})
+		}
+	case *truncateRuler:
+		if !canTruncate(t) {
+			*warnings = append(*warnings, Warning{path,
+				fmt.Sprintf("the truncate action has no effect on a property of type %s", t)})
+		}
+	}
+}
+
+// displayPath returns the rule path, or "$" for the root.
+func displayPath(path string) string {
+	if path == "" {
+		return "$"
+	}
+	return path
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// canHoldString reports whether a value of type t can be set to a string, directly or through pointers.
+func canHoldString(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String || t.Kind() == reflect.Interface
+}
@@ -0,0 +1,64 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateForType(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug", "Dbug" ],
+		"property_simplifiers": {
+			"Debug": {
+				"remove_properties": [ "Anything" ]
+			},
+			"Test": {
+				"remove_properties": [ "Inner" ]
+			},
+			"EntityList": {
+				"property_simplifiers": {
+					"SubProperties": {
+						"remove_properties": [ "ABC", "XYZ" ]
+					}
+				}
+			}
+		}
+	}`)
+
+	err := ValidateForType(simplifier, reflect.TypeOf(&ExampleStruct{}))
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	expected := []string{
+		"Debug: property_simplifiers entry is unreachable, the remove action takes precedence",
+		"Dbug: unknown property of gosimplifier.ExampleStruct",
+		"EntityList.SubProperties.XYZ: unknown property of gosimplifier.SubPropertyStruct",
+		"Test: rules applied to a value of scalar type int",
+	}
+	if !reflect.DeepEqual(validationErr.Problems, expected) {
+		t.Errorf("Expected %q, got %q", expected, validationErr.Problems)
+	}
+}
+
+func TestValidateForTypeValid(t *testing.T) {
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest" ]
+			}
+		}
+	}`)
+
+	if err := ValidateForType(simplifier, reflect.TypeOf(ExampleStruct{})); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := ValidateForType(simplifier, reflect.TypeOf(map[string]interface{}{})); err != nil {
+		t.Errorf("Expected dynamic maps to be accepted, got %v", err)
+	}
+	if err := ValidateForType(simplifier, reflect.TypeOf(map[string]ExampleStruct{})); err == nil {
+		t.Error("Expected map values to be validated against the rules of their key")
+	}
+}
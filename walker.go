@@ -0,0 +1,222 @@
+package gosimplifier
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+)
+
+// Node is a single value reached while walking the copy being simplified. Nodes are reused once
+// walked, so a Walker must not keep a node, or its parents, after it returns.
+type Node struct {
+	// Value is the value at this position of the copy.
+	// Struct fields and slice elements reached through pointers are addressable.
+	Value reflect.Value
+	// Parent is the struct, map or slice holding Value. It is invalid for the root.
+	Parent reflect.Value
+	// Key is the map key of Value when Parent is a map.
+	Key reflect.Value
+	// Depth is 0 for the root and grows by one for every level below it.
+	Depth int
+
+	parent *Node
+	name   string
+	index  int
+	rules  *simplifierImpl
+	ruler  ruler
+	walk   *walk
+	// promotes is set for embedded structs, whose fields are matched against the rules of the
+	// struct embedding them
+	promotes bool
+	// pruneDepth is the depth below which the values are removed by the prune_below_depth of
+	// pruner, 0 if no rule prunes the node's children, see prune
+	pruneDepth int
+	pruner     *simplifierImpl
+}
+
+// Walker processes a single Node. The walker at the end of the chain applies the rules
+// matching the node and walks into its children.
+type Walker func(node *Node) error
+
+// Middleware wraps a Walker to add cross-cutting behavior to the traversal, such as tracing,
+// budgets, metrics or skipping subtrees. A middleware that returns without calling next skips
+// the node and everything below it; a returned error aborts Simplify.
+type Middleware func(next Walker) Walker
+
+// Name returns the struct field name or map key of the node, or "" for the root and slice elements.
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Path returns the location of the node from the root, e.g. "EntityList[3].SubProperties.ABC".
+func (n *Node) Path() string {
+	if n.parent == nil {
+		return ""
+	}
+	prefix := n.parent.Path()
+	if n.index >= 0 {
+		return prefix + "[" + strconv.Itoa(n.index) + "]"
+	}
+	if prefix == "" {
+		return n.name
+	}
+	return prefix + "." + n.name
+}
+
+// Removing reports whether the rules will remove the node.
+func (n *Node) Removing() bool {
+	return n.ruler == removeRulerSingleton
+}
+
+// Action names what the rules do to the node, e.g. "remove", or "" if they only descend into it.
+func (n *Node) Action() string {
+	return n.ruler.action()
+}
+
+// RulePath returns the location in the rule tree of the rule acting on the node,
+// e.g. "EntityList.SubProperties.ABC", or "" if no rule names the node.
+func (n *Node) RulePath() string {
+	if n.parent == nil || n.ruler.action() == "" {
+		return ""
+	}
+	ruleName, ok := n.rules.ruleName(n.name, n.ruler)
+	if !ok {
+		return ""
+	}
+	return joinRulePath(n.parent.ruleLocation(), ruleName)
+}
+
+// ruleLocation returns the location in the rule tree of the rules the node's children are
+// matched against, "" for the root rules. Rule instances can be shared between several
+// locations, so the location is derived from the path the node was reached by.
+func (n *Node) ruleLocation() string {
+	if n.parent == nil {
+		return ""
+	}
+	if n.promotes {
+		return n.parent.ruleLocation()
+	}
+	if n.name != "" {
+		if ruleName, ok := n.rules.ruleName(n.name, n.ruler); ok {
+			return joinRulePath(n.parent.ruleLocation(), ruleName)
+		}
+	}
+	if n.index >= 0 {
+		return n.parent.ruleLocation()
+	}
+	return ""
+}
+
+// set replaces the value of the node in its parent. It reports false if the node cannot hold
+// the replacement.
+func (n *Node) set(replacement reflect.Value) bool {
+	if n.Parent.Kind() == reflect.Map {
+		if !replacement.Type().AssignableTo(n.Parent.Type().Elem()) {
+			return false
+		}
+		n.Parent.SetMapIndex(n.Key, replacement)
+		return true
+	}
+	if !n.Value.CanSet() || !replacement.Type().AssignableTo(n.Value.Type()) {
+		return false
+	}
+	n.Value.Set(replacement)
+	return true
+}
+
+// setIndirect replaces the value the node refers to through pointers and interfaces with
+// replacement, setting the deepest slot that can hold it, or the node itself in its parent.
+func (n *Node) setIndirect(replacement reflect.Value) bool {
+	var slot reflect.Value
+	for v := n.Value; v.IsValid(); {
+		if v.CanSet() && replacement.Type().AssignableTo(v.Type()) {
+			slot = v
+		}
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface || v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+	if slot.IsValid() {
+		slot.Set(replacement)
+		return true
+	}
+	return n.set(replacement)
+}
+
+// child creates the node for a value held by parentValue, whose rules are given by rules, or
+// removed if it is below the depth a rule prunes at. The node comes from a pool, and is
+// released by visitChild.
+func (n *Node) child(parentValue reflect.Value, value reflect.Value, key reflect.Value, name string, index int, rules *simplifierImpl, r ruler) *Node {
+	if n.prunesChildren() {
+		r = removeRulerSingleton
+	}
+	child := nodePool.Get().(*Node)
+	*child = Node{
+		Value:      value,
+		Parent:     parentValue,
+		Key:        key,
+		Depth:      n.Depth + 1,
+		parent:     n,
+		name:       name,
+		index:      index,
+		rules:      rules,
+		ruler:      r,
+		walk:       n.walk,
+		pruneDepth: n.pruneDepth,
+		pruner:     n.pruner,
+	}
+	return child
+}
+
+// walk holds the state of a single Simplify call.
+type walk struct {
+	root   *simplifierImpl
+	walker Walker
+	// provenance records the rule behind every action when WithProvenance is set
+	provenance map[string]interface{}
+	// ruleHits counts the rule hits of the call when WithStats is set
+	ruleHits map[string]uint64
+	// visited counts the visited nodes when a node budget is set
+	visited int
+	// errors collects the failures recovered by WithBestEffort
+	errors []error
+	// ctx is checked every contextCheckInterval nodes, unless done is nil as it can never be done
+	ctx   context.Context
+	done  <-chan struct{}
+	steps int
+	// metadata is the metadata of the context, see ContextWithMetadata
+	metadata map[string]string
+	// parallelism is the number of workers for large lists, see WithParallelism. Forks of the
+	// walk leave it unset, so the lists within the elements are walked by a single worker.
+	parallelism int
+	// report records the changes of the call, see DryRun
+	report *Report
+}
+
+// contextCheckInterval is the number of nodes visited between two checks of the context.
+const contextCheckInterval = 256
+
+func (w *walk) visit(node *Node) error {
+	if w.done != nil {
+		if w.steps++; w.steps%contextCheckInterval == 0 {
+			if err := w.ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+	if w.report != nil {
+		w.report.record(node)
+	}
+	return w.walker(node)
+}
+
+// cancelled reports whether the context of the call is done.
+func (w *walk) cancelled() bool {
+	return w.done != nil && w.ctx.Err() != nil
+}
+
+// applyNode is the innermost Walker, applying the rule matching the node.
+func applyNode(node *Node) error {
+	return node.ruler.apply(node)
+}
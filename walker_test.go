@@ -0,0 +1,150 @@
+package gosimplifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMiddlewareSeesPaths(t *testing.T) {
+	var paths []string
+	recorder := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Removing() {
+				paths = append(paths, node.Path())
+			}
+			return next(node)
+		}
+	}
+
+	simplifier, err := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"EntityList": {
+				"property_simplifiers": {
+					"SubProperties": {
+						"remove_properties": [ "ABC" ]
+					}
+				}
+			}
+		}
+	}`, WithMiddleware(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := ExampleStruct{
+		Debug: "debug",
+		EntityList: []EntityStruct{
+			{SubProperties: SubPropertyStruct{ABC: "abc"}},
+			{SubProperties: SubPropertyStruct{ABC: "abc"}},
+		},
+	}
+	if _, err := simplifier.Simplify(original); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"Debug",
+		"EntityList[0].SubProperties.ABC",
+		"EntityList[1].SubProperties.ABC",
+		"Nest.Debug",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+}
+
+func TestMiddlewareSkipsSubtree(t *testing.T) {
+	skipData := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Path() == "Data" {
+				return nil
+			}
+			return next(node)
+		}
+	}
+
+	simplifier, _ := NewSimplifier(`{
+		"remove_properties": [ "Debug" ],
+		"property_simplifiers": {
+			"Data": {
+				"remove_properties": [ "DataTest" ]
+			}
+		}
+	}`, WithMiddleware(skipData))
+
+	simplified, err := simplifier.Simplify(ExampleStruct{
+		Debug: "debug",
+		Data:  DataStruct{DataTest: "data_test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := simplified.(ExampleStruct)
+	if result.Debug != "" {
+		t.Error("Expected Debug to be removed")
+	}
+	if result.Data.DataTest != "data_test" {
+		t.Error("Expected Data to be skipped by the middleware")
+	}
+}
+
+func TestMiddlewareOrderAndError(t *testing.T) {
+	var order []string
+	named := func(name string) Middleware {
+		return func(next Walker) Walker {
+			return func(node *Node) error {
+				if node.Depth == 0 {
+					order = append(order, name)
+				}
+				return next(node)
+			}
+		}
+	}
+	errBudget := errors.New("budget exceeded")
+	budget := func(next Walker) Walker {
+		return func(node *Node) error {
+			if node.Depth > 1 {
+				return errBudget
+			}
+			return next(node)
+		}
+	}
+
+	simplifier, _ := NewSimplifier(`{}`, WithMiddleware(named("outer"), named("inner")), WithMiddleware(budget))
+
+	simplified, err := simplifier.Simplify(ExampleStruct{Data: DataStruct{DataTest: "data_test"}})
+	if !errors.Is(err, errBudget) {
+		t.Errorf("Expected budget error, got %v", err)
+	}
+	if simplified != nil {
+		t.Error("Expected no result when a middleware fails")
+	}
+	if !reflect.DeepEqual(order, []string{"outer", "inner"}) {
+		t.Errorf("Expected outer middleware first, got %v", order)
+	}
+}
+
+func TestExtendSimplifierKeepsMiddleware(t *testing.T) {
+	visited := 0
+	counter := func(next Walker) Walker {
+		return func(node *Node) error {
+			visited++
+			return next(node)
+		}
+	}
+
+	base, _ := NewSimplifier(`{}`, WithMiddleware(counter))
+	extended, err := ExtendSimplifier(base, `{ "remove_properties": [ "Test" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := extended.Simplify(DataStruct{}); err != nil {
+		t.Fatal(err)
+	}
+	if visited != 3 {
+		t.Errorf("Expected 3 visited nodes, got %d", visited)
+	}
+}
@@ -0,0 +1,25 @@
+package gosimplifier
+
+import "time"
+
+// WatchingSimplifier is a Simplifier whose rules are loaded from a file and reloaded when the
+// file changes, so the policy can be updated without redeploying the service. The file is
+// polled rather than watched through file system notifications, which miss the symlink swaps
+// of mounted Kubernetes ConfigMaps.
+//
+// It is the ManagedSimplifier of a FileRuleProvider: every call uses the rules current when it
+// starts, and rules that fail to load are not swapped in.
+type WatchingSimplifier struct {
+	*ManagedSimplifier
+}
+
+// NewWatchingSimplifier loads the rules of the JSON file at path with the options, and checks
+// the file for changes every interval until Close is called. It fails if the rules cannot be
+// loaded initially.
+func NewWatchingSimplifier(path string, interval time.Duration, opts ...Option) (*WatchingSimplifier, error) {
+	m, err := NewManagedSimplifier(FileRuleProvider(path), interval, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &WatchingSimplifier{ManagedSimplifier: m}, nil
+}
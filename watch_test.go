@@ -0,0 +1,60 @@
+package gosimplifier
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchingSimplifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{ "remove_properties": [ "Debug" ] }`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	simplifier, err := NewWatchingSimplifier(path, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer simplifier.Close()
+	original := ExampleStruct0{Test: 1, Debug: "d"}
+	simplified, err := simplifier.Simplify(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(simplified, ExampleStruct0{Test: 1}) {
+		t.Errorf("Expected Debug to be removed, got %+v", simplified)
+	}
+
+	if err := os.WriteFile(path, []byte(`{ "remove_properties": [ "Test" ] }`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		simplified, err := simplifier.Simplify(original)
+		return err == nil && reflect.DeepEqual(simplified, ExampleStruct0{Debug: "d"})
+	})
+
+	if err := os.WriteFile(path, []byte(`{ "remove_properties": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return simplifier.Err() != nil })
+	if simplified, err := simplifier.Simplify(original); err != nil || !reflect.DeepEqual(simplified, ExampleStruct0{Debug: "d"}) {
+		t.Errorf("Expected the previous rules to stay in use, got %+v, %v", simplified, err)
+	}
+}
+
+func TestNewWatchingSimplifierFails(t *testing.T) {
+	if _, err := NewWatchingSimplifier(filepath.Join(t.TempDir(), "missing.json"), time.Second); err == nil {
+		t.Error("Expected an error for a missing rules file")
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !condition(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the condition")
+		}
+	}
+}
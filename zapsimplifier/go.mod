@@ -0,0 +1,13 @@
+module github.com/xhinliang/gosimplifier/zapsimplifier
+
+go 1.23.0
+
+require (
+	github.com/xhinliang/gosimplifier v0.1.0
+	go.uber.org/zap v1.27.1
+)
+
+require (
+	go.uber.org/multierr v1.10.0 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
+)
@@ -0,0 +1,96 @@
+// Package zapsimplifier connects gosimplifier to zap, simplifying the object fields of log
+// entries so sensitive sub-fields are scrubbed before they are encoded.
+package zapsimplifier
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/xhinliang/gosimplifier"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Selector returns the Simplifier of the value of a field, or nil to log it as it is.
+type Selector func(key string, value interface{}) gosimplifier.Simplifier
+
+// ByKey selects the Simplifier by the key of the field. Fields with no listed key use the
+// Simplifier of "", if any.
+func ByKey(simplifiers map[string]gosimplifier.Simplifier) Selector {
+	return func(key string, _ interface{}) gosimplifier.Simplifier {
+		if s, ok := simplifiers[key]; ok {
+			return s
+		}
+		return simplifiers[""]
+	}
+}
+
+// ByType selects the Simplifier by the dynamic type of the value of the field, pointers to the
+// listed types included.
+func ByType(simplifiers map[reflect.Type]gosimplifier.Simplifier) Selector {
+	return func(_ string, value interface{}) gosimplifier.Simplifier {
+		t := reflect.TypeOf(value)
+		if s, ok := simplifiers[t]; ok || t == nil || t.Kind() != reflect.Ptr {
+			return s
+		}
+		return simplifiers[t.Elem()]
+	}
+}
+
+// Object returns the field logging the copy of v simplified by s. If v cannot be simplified,
+// the field logs the error instead, so the original value never reaches the log.
+func Object(key string, v interface{}, s gosimplifier.Simplifier) zap.Field {
+	simplified, err := s.Simplify(v)
+	var partial *gosimplifier.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return zap.NamedError(key, err)
+	}
+	return zap.Reflect(key, simplified)
+}
+
+// NewCore wraps core so the fields of entries and of With, logged with zap.Any or zap.Reflect,
+// are replaced with the copies simplified by the Simplifier selected for them, see Object.
+// Fields encoding themselves, such as zap.Object, are left as they are.
+func NewCore(core zapcore.Core, selector Selector) zapcore.Core {
+	return &simplifyingCore{Core: core, selector: selector}
+}
+
+type simplifyingCore struct {
+	zapcore.Core
+	selector Selector
+}
+
+func (c *simplifyingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &simplifyingCore{Core: c.Core.With(c.simplify(fields)), selector: c.selector}
+}
+
+func (c *simplifyingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *simplifyingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.simplify(fields))
+}
+
+// simplify returns fields with the reflected values simplified, copying the slice, which
+// belongs to the caller, if a field changes.
+func (c *simplifyingCore) simplify(fields []zapcore.Field) []zapcore.Field {
+	simplified, copied := fields, false
+	for i, field := range fields {
+		if field.Type != zapcore.ReflectType || field.Interface == nil {
+			continue
+		}
+		s := c.selector(field.Key, field.Interface)
+		if s == nil {
+			continue
+		}
+		if !copied {
+			simplified, copied = append([]zapcore.Field(nil), fields...), true
+		}
+		simplified[i] = Object(field.Key, field.Interface, s)
+	}
+	return simplified
+}
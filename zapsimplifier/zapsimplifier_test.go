@@ -0,0 +1,60 @@
+package zapsimplifier
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xhinliang/gosimplifier"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type User struct {
+	Name     string
+	Password string
+}
+
+func TestNewCore(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Password" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observed, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(NewCore(observed, ByType(map[reflect.Type]gosimplifier.Simplifier{
+		reflect.TypeOf(User{}): simplifier,
+	})))
+
+	user := &User{Name: "a", Password: "secret"}
+	logger.With(zap.Any("owner", User{Name: "b", Password: "secret"})).
+		Info("login", zap.Any("user", user), zap.String("password", "kept"), zap.Any("count", 1))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if owner := fields["owner"]; !reflect.DeepEqual(owner, User{Name: "b"}) {
+		t.Errorf("Expected the With field to be simplified, got %+v", owner)
+	}
+	if logged := fields["user"]; !reflect.DeepEqual(logged, &User{Name: "a"}) {
+		t.Errorf("Expected the user to be simplified, got %+v", logged)
+	}
+	if fields["password"] != "kept" || fields["count"] != int64(1) {
+		t.Errorf("Expected the other fields to be left as they are, got %+v", fields)
+	}
+	if user.Password != "secret" {
+		t.Error("Expected the logged value to be left unchanged")
+	}
+}
+
+func TestObject(t *testing.T) {
+	simplifier, err := gosimplifier.NewSimplifier(`{ "remove_properties": [ "Password" ] }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := Object("user", map[string]string{"Name": "a", "Password": "secret"}, ByKey(map[string]gosimplifier.Simplifier{"user": simplifier})("user", nil))
+	if !reflect.DeepEqual(field.Interface, map[string]string{"Name": "a"}) {
+		t.Errorf("Expected the simplified map, got %+v", field.Interface)
+	}
+}